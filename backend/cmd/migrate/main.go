@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log/slog"
 	"os"
 	"time"
@@ -12,6 +13,9 @@ import (
 )
 
 func main() {
+	reset := flag.Bool("reset", false, "drop all tables and re-run migrations from scratch (dev only)")
+	flag.Parse()
+
 	config.LoadDotenv()
 	cfg := config.Load()
 
@@ -30,6 +34,15 @@ func main() {
 	}
 	defer d.Close()
 
+	if *reset {
+		if err := migrate.Reset(ctx, d.Pool, migrate.MigrateOptions{AllowDestructive: true}); err != nil {
+			slog.Error("migrate reset failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("database reset and migrations applied")
+		return
+	}
+
 	if err := migrate.Up(ctx, d.Pool); err != nil {
 		slog.Error("migrate up failed", "error", err)
 		os.Exit(1)
@@ -0,0 +1,104 @@
+// Package backoff computes exponential retry delays and sleeps through them
+// without outliving a context.
+package backoff
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff describes a retry schedule: the first retry waits Base, and each
+// later one Multiplier times longer, capped at Max. With Jitter, each wait is
+// a random duration between zero and the computed delay ("full jitter"), so
+// concurrent retriers spread out instead of retrying in lockstep.
+type Backoff struct {
+	Base time.Duration
+	// Max caps the delay; zero leaves it uncapped
+	Max time.Duration
+	// Multiplier is the growth per attempt (default: 2). Values below 1 keep
+	// the delay constant at Base.
+	Multiplier float64
+	Jitter     bool
+	// JitterFunc returns a duration in [0, d]; results outside the range are
+	// clamped. Nil uses math/rand. Inject a deterministic function in tests.
+	JitterFunc func(d time.Duration) time.Duration
+}
+
+// Delay returns the wait before retry number attempt, counting the first
+// retry as 1. Attempts below 1 don't wait.
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 || b.Base <= 0 {
+		return 0
+	}
+
+	mult := b.Multiplier
+	switch {
+	case mult == 0:
+		mult = 2
+	case mult < 1:
+		mult = 1
+	}
+
+	// Grow in floating point and stop at the cap, so large attempt numbers
+	// can't overflow
+	limit := time.Duration(math.MaxInt64)
+	if b.Max > 0 {
+		limit = b.Max
+	}
+	d := float64(b.Base)
+	for i := 1; i < attempt && d < float64(limit); i++ {
+		d *= mult
+	}
+	if d >= float64(limit) {
+		return b.Apply(limit)
+	}
+	return b.Apply(time.Duration(d))
+}
+
+// Apply caps d at Max and applies jitter as Delay does, for callers that
+// compute the base delay themselves
+func (b Backoff) Apply(d time.Duration) time.Duration {
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	if !b.Jitter || d <= 0 {
+		return d
+	}
+
+	var j time.Duration
+	if b.JitterFunc != nil {
+		j = b.JitterFunc(d)
+	} else {
+		j = time.Duration(rand.Int63n(int64(d) + 1))
+	}
+	if j < 0 {
+		return 0
+	}
+	if j > d {
+		return d
+	}
+	return j
+}
+
+// Sleep waits Delay(attempt), returning ctx's error early if it is done
+// first
+func (b Backoff) Sleep(ctx context.Context, attempt int) error {
+	return Wait(ctx, b.Delay(attempt))
+}
+
+// Wait sleeps for d, returning ctx's error early if it is done first
+func Wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelay_GrowsAndCaps(t *testing.T) {
+	b := Backoff{Base: time.Second, Max: 5 * time.Second}
+	want := []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, w := range want {
+		if d := b.Delay(attempt); d != w {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, w, d)
+		}
+	}
+
+	// Far beyond the cap, the growth must not overflow
+	if d := b.Delay(1000); d != 5*time.Second {
+		t.Errorf("expected the cap for a large attempt, got %v", d)
+	}
+	if d := (Backoff{Base: time.Second}).Delay(1000); d <= 0 {
+		t.Errorf("expected an uncapped delay to saturate rather than overflow, got %v", d)
+	}
+}
+
+func TestDelay_Multiplier(t *testing.T) {
+	if d := (Backoff{Base: time.Second, Multiplier: 3}).Delay(3); d != 9*time.Second {
+		t.Errorf("expected 9s, got %v", d)
+	}
+	if d := (Backoff{Base: time.Second, Multiplier: 0.5}).Delay(4); d != time.Second {
+		t.Errorf("expected a sub-1 multiplier to keep the delay constant, got %v", d)
+	}
+}
+
+func TestDelay_JitterBounds(t *testing.T) {
+	b := Backoff{Base: 100 * time.Millisecond, Max: time.Second, Jitter: true}
+	for i := 0; i < 1000; i++ {
+		if d := b.Delay(3); d < 0 || d > 400*time.Millisecond {
+			t.Fatalf("jittered delay %v outside [0, 400ms]", d)
+		}
+	}
+
+	b.JitterFunc = func(d time.Duration) time.Duration { return 2 * d }
+	if d := b.Delay(1); d != 100*time.Millisecond {
+		t.Errorf("expected an oversized jitter to be clamped, got %v", d)
+	}
+	b.JitterFunc = func(d time.Duration) time.Duration { return -d }
+	if d := b.Delay(1); d != 0 {
+		t.Errorf("expected a negative jitter to be clamped, got %v", d)
+	}
+}
+
+func TestSleep_Cancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := Backoff{Base: time.Hour}.Sleep(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected a canceled sleep to return immediately")
+	}
+
+	if err := (Backoff{Base: time.Millisecond}).Sleep(context.Background(), 1); err != nil {
+		t.Errorf("expected a completed sleep to return nil, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrorKind classifies why a migration run failed
+type ErrorKind string
+
+const (
+	// LockTimeout means another instance held the migration lock; retry later
+	LockTimeout ErrorKind = "lock_timeout"
+	// Dirty means a previous run failed part way and the version must be
+	// fixed and forced by hand
+	Dirty ErrorKind = "dirty"
+	// SQLError means a migration's SQL failed and needs a human
+	SQLError ErrorKind = "sql_error"
+	// Connection means the database could not be reached; retry later
+	Connection ErrorKind = "connection"
+	// NoChange means there was nothing to apply
+	NoChange ErrorKind = "no_change"
+	// Unknown covers everything else
+	Unknown ErrorKind = "unknown"
+)
+
+// MigrationError is returned by Up and its variants when a run fails. Match it
+// with errors.As, or use KindOf and IsRetryable.
+type MigrationError struct {
+	Kind ErrorKind
+	// Version is the migration the run failed on, or -1 if unknown
+	Version int
+	Err     error
+}
+
+func newMigrationError(err error, version int) *MigrationError {
+	var dirty migrate.ErrDirty
+	if errors.As(err, &dirty) && version < 0 {
+		version = dirty.Version
+	}
+	return &MigrationError{Kind: classify(err), Version: version, Err: err}
+}
+
+// failedVersion returns the version a failed run stopped on: the current
+// version if it is dirty, since a failed migration leaves its version dirty.
+// A clean version is the last one that applied, not the one that failed, so
+// it gives -1.
+func failedVersion(version uint, dirty bool, err error) int {
+	if err != nil || !dirty {
+		return -1
+	}
+	return int(version)
+}
+
+func (e *MigrationError) Error() string {
+	if e.Version >= 0 {
+		return fmt.Sprintf("migration %d failed (%s): %v", e.Version, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("migration failed (%s): %v", e.Kind, e.Err)
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether running the migration again later may succeed
+// without anyone fixing anything
+func (e *MigrationError) Retryable() bool {
+	return e.Kind == LockTimeout || e.Kind == Connection
+}
+
+// KindOf returns the kind of a MigrationError in err's chain, or Unknown
+func KindOf(err error) ErrorKind {
+	var me *MigrationError
+	if errors.As(err, &me) {
+		return me.Kind
+	}
+	return Unknown
+}
+
+// IsRetryable reports whether err is a MigrationError worth retrying
+func IsRetryable(err error) bool {
+	var me *MigrationError
+	return errors.As(err, &me) && me.Retryable()
+}
+
+// classify maps an error from golang-migrate or the postgres driver to a kind
+func classify(err error) ErrorKind {
+	var (
+		dirty   migrate.ErrDirty
+		dbErr   database.Error
+		pgErr   *pgconn.PgError
+		connErr *pgconn.ConnectError
+		netErr  net.Error
+	)
+	switch {
+	case err == nil:
+		return Unknown
+	case errors.Is(err, migrate.ErrNoChange):
+		return NoChange
+	case errors.Is(err, migrate.ErrLockTimeout), errors.Is(err, migrate.ErrLocked), errors.Is(err, database.ErrLocked):
+		return LockTimeout
+	case errors.As(err, &dirty):
+		return Dirty
+	case errors.As(err, &connErr), errors.As(err, &netErr), errors.Is(err, driver.ErrBadConn):
+		return Connection
+	case errors.As(err, &dbErr):
+		// database.Error doesn't unwrap, so check what it wraps by hand
+		if kind := classify(dbErr.OrigErr); kind == LockTimeout || kind == Connection {
+			return kind
+		}
+		return SQLError
+	case errors.As(err, &pgErr):
+		// 55P03 is lock_not_available
+		if pgErr.Code == "55P03" {
+			return LockTimeout
+		}
+		return SQLError
+	}
+
+	msg := err.Error()
+	if contains(msg, "can't acquire") || contains(msg, "55P03") {
+		return LockTimeout
+	}
+	return Unknown
+}
@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"no change", migrate.ErrNoChange, NoChange},
+		{"lock timeout", migrate.ErrLockTimeout, LockTimeout},
+		{"driver locked", fmt.Errorf("create driver: %w", database.ErrLocked), LockTimeout},
+		{"lock not available", &pgconn.PgError{Code: "55P03"}, LockTimeout},
+		{"dirty", migrate.ErrDirty{Version: 15}, Dirty},
+		{"syntax error", database.Error{OrigErr: &pgconn.PgError{Code: "42601"}, Line: 3}, SQLError},
+		{"lock inside sql error", database.Error{OrigErr: &pgconn.PgError{Code: "55P03"}}, LockTimeout},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, Connection},
+		{"other", errors.New("boom"), Unknown},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.err); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestMigrationError_Accessors(t *testing.T) {
+	err := fmt.Errorf("deploy: %w", newMigrationError(migrate.ErrDirty{Version: 15}, -1))
+
+	var me *MigrationError
+	if !errors.As(err, &me) {
+		t.Fatal("expected errors.As to find the MigrationError")
+	}
+	if me.Version != 15 {
+		t.Errorf("expected the dirty version, got %d", me.Version)
+	}
+	if KindOf(err) != Dirty || IsRetryable(err) {
+		t.Errorf("expected a non-retryable dirty error, got %s", KindOf(err))
+	}
+	if !errors.Is(err, me.Err) {
+		t.Error("expected the underlying error to unwrap")
+	}
+
+	if !IsRetryable(newMigrationError(migrate.ErrLockTimeout, -1)) {
+		t.Error("expected a lock timeout to be retryable")
+	}
+	if KindOf(errors.New("plain")) != Unknown {
+		t.Error("expected Unknown for an unclassified error")
+	}
+}
+
+func TestFailedVersion(t *testing.T) {
+	if v := failedVersion(15, true, nil); v != 15 {
+		t.Errorf("expected the dirty version, got %d", v)
+	}
+	// A lock timeout or connection error before any file ran leaves the last
+	// applied version clean; it didn't fail
+	if v := failedVersion(14, false, nil); v != -1 {
+		t.Errorf("expected -1 for a clean version, got %d", v)
+	}
+	if v := failedVersion(0, false, migrate.ErrNilVersion); v != -1 {
+		t.Errorf("expected -1 when the version can't be read, got %d", v)
+	}
+}
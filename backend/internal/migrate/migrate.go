@@ -2,9 +2,9 @@ package migrate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
 	"strings"
 	"time"
 
@@ -17,9 +17,14 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 
+	"github.com/jagadeesh/grainlify/backend/internal/backoff"
 	"github.com/jagadeesh/grainlify/backend/migrations"
 )
 
+// lockRetryBackoff is the fixed delay between attempts while another instance
+// holds the migration lock
+var lockRetryBackoff = backoff.Backoff{Base: 500 * time.Millisecond, Multiplier: 1}
+
 // NeedsMigration checks if migrations are needed by comparing the current database version
 // with available migrations. Returns true if migrations are needed, false otherwise.
 // This function queries the database directly to avoid acquiring locks.
@@ -124,9 +129,23 @@ func getLatestMigrationVersion(src source.Driver) (uint, error) {
 	return latestVersion, nil
 }
 
+// Up applies all pending migrations. A failed run returns a *MigrationError
+// classifying the failure.
 func Up(ctx context.Context, pool *pgxpool.Pool) error {
+	return UpWithOptions(ctx, pool, MigrateOptions{})
+}
+
+// UpWithOptions applies all pending migrations, invoking opts.Hooks as each
+// migration file is applied. Like Up, failures are returned as *MigrationError.
+func UpWithOptions(ctx context.Context, pool *pgxpool.Pool, opts MigrateOptions) error {
 	if pool == nil {
-		return fmt.Errorf("db pool is nil")
+		return newMigrationError(errors.New("db pool is nil"), -1)
+	}
+
+	if opts.ValidateSequence {
+		if err := ValidateMigrationSequence(); err != nil {
+			return newMigrationError(err, -1)
+		}
 	}
 
 	slog.Info("loading embedded migration files")
@@ -136,7 +155,7 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 			"error", err,
 			"error_type", fmt.Sprintf("%T", err),
 		)
-		return fmt.Errorf("open embedded migrations: %w", err)
+		return newMigrationError(fmt.Errorf("open embedded migrations: %w", err), -1)
 	}
 	slog.Info("embedded migrations loaded")
 
@@ -144,12 +163,14 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 	sqlDB := stdlib.OpenDB(*pool.Config().ConnConfig)
 	defer sqlDB.Close()
 
-	// Add random jitter (0-2 seconds) to avoid thundering herd problem
+	// Add random jitter (0-2 seconds by default) to avoid thundering herd problem
 	// This helps when multiple instances start simultaneously
-	jitter := time.Duration(rand.Intn(2000)) * time.Millisecond
+	jitter := opts.jitter()
 	if jitter > 0 {
 		slog.Info("adding random jitter before migration", "jitter_ms", jitter.Milliseconds())
-		time.Sleep(jitter)
+		if err := backoff.Wait(ctx, jitter); err != nil {
+			return newMigrationError(err, -1)
+		}
 	}
 
 	// Retry driver creation with simple fixed delay
@@ -164,7 +185,9 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 				"attempt", driverAttempt,
 				"max_retries", maxDriverRetries,
 			)
-			time.Sleep(500 * time.Millisecond)
+			if err := lockRetryBackoff.Sleep(ctx, driverAttempt-1); err != nil {
+				return newMigrationError(err, -1)
+			}
 		}
 
 		slog.Info("creating postgres migration driver", "attempt", driverAttempt)
@@ -201,17 +224,21 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 			"error_type", fmt.Sprintf("%T", err),
 			"attempt", driverAttempt,
 		)
-		return fmt.Errorf("create postgres migration driver: %w", err)
+		return newMigrationError(fmt.Errorf("create postgres migration driver: %w", err), -1)
 	}
 
+	// Wrap the drivers so each migration file's apply time is logged and reported
+	timedSrc := newTimingSource(src)
+	timedDB := newTimingDatabase(db, timedSrc, opts.Hooks)
+
 	slog.Info("creating migrator instance")
-	m, err := migrate.NewWithInstance("iofs", src, "postgres", db)
+	m, err := migrate.NewWithInstance("iofs", timedSrc, "postgres", timedDB)
 	if err != nil {
 		slog.Error("failed to create migrator",
 			"error", err,
 			"error_type", fmt.Sprintf("%T", err),
 		)
-		return fmt.Errorf("create migrator: %w", err)
+		return newMigrationError(fmt.Errorf("create migrator: %w", err), -1)
 	}
 	defer func() {
 		slog.Info("closing migrator")
@@ -231,8 +258,7 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 		)
 	}
 
-	// migrate.Up() is not context-aware; we still accept ctx for future evolutions.
-	_ = ctx
+	// migrate.Up() is not context-aware; ctx only cuts short the waits between attempts.
 
 	slog.Info("running database migrations")
 	
@@ -248,7 +274,9 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 				"attempt", attempt,
 				"max_retries", maxRetries,
 			)
-			time.Sleep(500 * time.Millisecond)
+			if err := lockRetryBackoff.Sleep(ctx, attempt-1); err != nil {
+				return newMigrationError(err, -1)
+			}
 		}
 		
 		err := m.Up()
@@ -280,11 +308,14 @@ func Up(ctx context.Context, pool *pgxpool.Pool) error {
 	}
 	
 	if lastErr != nil && lastErr != migrate.ErrNoChange {
+		migErr := newMigrationError(lastErr, failedVersion(m.Version()))
 		slog.Error("migration failed after retries",
 			"error", lastErr,
 			"error_type", fmt.Sprintf("%T", lastErr),
+			"kind", migErr.Kind,
+			"version", migErr.Version,
 		)
-		return lastErr
+		return migErr
 	}
 	
 	err = lastErr
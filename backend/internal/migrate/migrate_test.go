@@ -0,0 +1,27 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter_Disabled(t *testing.T) {
+	opts := MigrateOptions{DisableJitter: true, MaxJitter: time.Hour}
+	for i := 0; i < 100; i++ {
+		if d := opts.jitter(); d != 0 {
+			t.Fatalf("expected no jitter when disabled, got %v", d)
+		}
+	}
+}
+
+func TestJitter_Capped(t *testing.T) {
+	opts := MigrateOptions{MaxJitter: 10 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		if d := opts.jitter(); d < 0 || d >= 10*time.Millisecond {
+			t.Fatalf("expected jitter below 10ms, got %v", d)
+		}
+	}
+	if d := (MigrateOptions{}).jitter(); d >= DefaultMaxJitter {
+		t.Errorf("expected default jitter below %v, got %v", DefaultMaxJitter, d)
+	}
+}
@@ -0,0 +1,171 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/jagadeesh/grainlify/backend/migrations"
+)
+
+// DefaultProtectedDatabases is used when MigrateOptions.ProtectedDatabases is nil.
+// A database is refused when one of these starts or ends one of its name's
+// tokens, split on "_", "-" and "." and compared case-insensitively: "prod"
+// refuses app_prod, grainlifyprod and prod1 but not reproduce. The guard
+// errs toward refusing, so products_dev is refused too.
+var DefaultProtectedDatabases = []string{"prod", "production", "live"}
+
+// MigrateOptions controls optional migration behavior
+type MigrateOptions struct {
+	// AllowDestructive must be set explicitly for Reset to run
+	AllowDestructive bool
+	// ProtectedDatabases lists database names, or name tokens, Reset must
+	// never touch; see DefaultProtectedDatabases for how they match. Nil
+	// means DefaultProtectedDatabases.
+	ProtectedDatabases []string
+	// Hooks observe individual migrations as they are applied
+	Hooks Hooks
+	// DisableJitter skips the random delay before migrating. The delay keeps
+	// instances that start together from racing for the migration lock, so
+	// only disable it where a single instance migrates, e.g. local dev and
+	// tests.
+	DisableJitter bool
+	// MaxJitter caps the random delay before migrating (default:
+	// DefaultMaxJitter)
+	MaxJitter time.Duration
+	// ValidateSequence runs ValidateMigrationSequence before migrating and
+	// refuses to migrate if it fails
+	ValidateSequence bool
+}
+
+// DefaultMaxJitter is the default cap on the random delay before migrating
+const DefaultMaxJitter = 2 * time.Second
+
+// jitter returns a random delay in [0, MaxJitter), or zero when disabled
+func (o MigrateOptions) jitter() time.Duration {
+	if o.DisableJitter {
+		return 0
+	}
+	max := o.MaxJitter
+	if max <= 0 {
+		max = DefaultMaxJitter
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// Reset drops every table in the migration schema, including
+// schema_migrations, then re-runs all migrations from scratch. It is meant for
+// development databases only: it requires opts.AllowDestructive and refuses
+// to run against a database whose name matches the protected list. Like Up,
+// failures are returned as *MigrationError.
+func Reset(ctx context.Context, pool *pgxpool.Pool, opts MigrateOptions) error {
+	if pool == nil {
+		return newMigrationError(errors.New("db pool is nil"), -1)
+	}
+	if !opts.AllowDestructive {
+		return newMigrationError(errors.New("reset refused: AllowDestructive is not set"), -1)
+	}
+
+	var dbName string
+	if err := pool.QueryRow(ctx, `SELECT current_database()`).Scan(&dbName); err != nil {
+		return newMigrationError(fmt.Errorf("get database name: %w", err), -1)
+	}
+
+	protected := opts.ProtectedDatabases
+	if protected == nil {
+		protected = DefaultProtectedDatabases
+	}
+	if p, ok := protectedMatch(dbName, protected); ok {
+		return newMigrationError(fmt.Errorf("reset refused: database %q matches protected name %q", dbName, p), -1)
+	}
+
+	slog.Warn("!!! RESETTING DATABASE: dropping all tables and re-running migrations !!!",
+		"database", dbName,
+	)
+
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return newMigrationError(fmt.Errorf("open embedded migrations: %w", err), -1)
+	}
+
+	sqlDB := stdlib.OpenDB(*pool.Config().ConnConfig)
+	defer sqlDB.Close()
+
+	db, err := postgres.WithInstance(sqlDB, &postgres.Config{
+		MigrationsTable: "schema_migrations",
+	})
+	if err != nil {
+		return newMigrationError(fmt.Errorf("create postgres migration driver: %w", err), -1)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", db)
+	if err != nil {
+		return newMigrationError(fmt.Errorf("create migrator: %w", err), -1)
+	}
+
+	// Drop removes every table in the schema, schema_migrations included
+	dropErr := m.Drop()
+	_, _ = m.Close()
+	if dropErr != nil {
+		return newMigrationError(fmt.Errorf("drop tables: %w", dropErr), -1)
+	}
+	slog.Warn("database reset: all tables dropped", "database", dbName)
+
+	return UpWithOptions(ctx, pool, opts)
+}
+
+// protectedMatch returns the first protected name matching dbName. A name
+// matches when its tokens appear consecutively among dbName's tokens, the
+// first allowed to end a longer token and the last to start one, so "prod"
+// refuses grainlifyprod and prod1 and "app-prod" refuses myapp_prod2_eu.
+func protectedMatch(dbName string, protected []string) (string, bool) {
+	tokens := nameTokens(dbName)
+	for _, p := range protected {
+		want := nameTokens(p)
+		if len(want) == 0 {
+			continue
+		}
+		for i := 0; i+len(want) <= len(tokens); i++ {
+			if tokensMatch(tokens[i:i+len(want)], want) {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// tokensMatch reports whether got matches want token for token, except that
+// got's first token need only end with want's and its last only start with
+// want's; a single token may do either
+func tokensMatch(got, want []string) bool {
+	last := len(want) - 1
+	if last == 0 {
+		return strings.HasPrefix(got[0], want[0]) || strings.HasSuffix(got[0], want[0])
+	}
+	for i := range want {
+		switch {
+		case i == 0 && !strings.HasSuffix(got[i], want[i]),
+			i == last && !strings.HasPrefix(got[i], want[i]),
+			i > 0 && i < last && got[i] != want[i]:
+			return false
+		}
+	}
+	return true
+}
+
+// nameTokens lowercases name and splits it on "_", "-" and "."
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+}
@@ -0,0 +1,67 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProtectedMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"prod", true},
+		{"PROD", true},
+		{"grainlify_prod", true},
+		{"grainlify-production", true},
+		{"prod.main", true},
+		{"app_live_eu", true},
+		{"grainlifyprod", true},
+		{"prod1", true},
+		{"production2024", true},
+		{"liveDB", true},
+		// The guard errs toward refusing
+		{"products_dev", true},
+		{"liveness", true},
+		{"delivery_test", false},
+		{"reproduce", false},
+		{"grainlify_dev", false},
+		{"staging", false},
+	}
+	for _, tt := range tests {
+		_, got := protectedMatch(tt.name, DefaultProtectedDatabases)
+		if got != tt.want {
+			t.Errorf("protectedMatch(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	custom := []string{"", "app-prod"}
+	if _, ok := protectedMatch("app_prod_eu", custom); !ok {
+		t.Error("expected a multi-token name to match consecutive tokens")
+	}
+	if _, ok := protectedMatch("myapp_prod2", custom); !ok {
+		t.Error("expected a multi-token name to match at token edges")
+	}
+	if _, ok := protectedMatch("app_staging_prod", custom); ok {
+		t.Error("expected a multi-token name not to match split tokens")
+	}
+	if _, ok := protectedMatch("app_reproduce", custom); ok {
+		t.Error("expected a multi-token name not to match inside a token")
+	}
+	if _, ok := protectedMatch("anything", []string{""}); ok {
+		t.Error("expected an empty protected name to match nothing")
+	}
+}
+
+func TestReset_NilPoolIsMigrationError(t *testing.T) {
+	for name, err := range map[string]error{
+		"Reset":         Reset(context.Background(), nil, MigrateOptions{AllowDestructive: true}),
+		"UpWithOptions": UpWithOptions(context.Background(), nil, MigrateOptions{}),
+	} {
+		var me *MigrationError
+		if !errors.As(err, &me) {
+			t.Errorf("%s: expected a *MigrationError, got %T: %v", name, err, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/source"
+
+	"github.com/jagadeesh/grainlify/backend/migrations"
+)
+
+// ValidateMigrationSequence checks that the embedded migrations are numbered
+// without gaps or duplicate versions, either of which usually means a bad
+// merge. It doesn't touch the database.
+func ValidateMigrationSequence() error {
+	return validateMigrationSequence(migrations.FS)
+}
+
+// validateMigrationSequence checks the migration files at the root of fsys.
+// Files that aren't migrations, like migrations.go, are ignored.
+func validateMigrationSequence(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("read migrations: %w", err)
+	}
+
+	// Files per version and direction; more than one is a duplicate
+	files := make(map[uint]map[source.Direction][]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			continue
+		}
+		if files[m.Version] == nil {
+			files[m.Version] = make(map[source.Direction][]string)
+		}
+		files[m.Version][m.Direction] = append(files[m.Version][m.Direction], m.Raw)
+	}
+
+	versions := make([]uint, 0, len(files))
+	for v := range files {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	var problems []error
+	for i, v := range versions {
+		if i > 0 && v != versions[i-1]+1 {
+			problems = append(problems, fmt.Errorf("missing %s", versionRange(versions[i-1]+1, v-1)))
+		}
+		for _, dir := range []source.Direction{source.Up, source.Down} {
+			if names := files[v][dir]; len(names) > 1 {
+				problems = append(problems, fmt.Errorf("duplicate %s migration %d: %s", dir, v, strings.Join(names, ", ")))
+			}
+		}
+		if len(files[v][source.Up]) == 0 {
+			problems = append(problems, fmt.Errorf("migration %d has no up file", v))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid migration sequence: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// versionRange describes the versions from first to last inclusive
+func versionRange(first, last uint) string {
+	if first == last {
+		return fmt.Sprintf("version %d", first)
+	}
+	return fmt.Sprintf("versions %d-%d", first, last)
+}
@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func migrationFS(names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{"migrations.go": {Data: []byte("package migrations")}}
+	for _, name := range names {
+		fsys[name] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	}
+	return fsys
+}
+
+func TestValidateMigrationSequence_Embedded(t *testing.T) {
+	if err := ValidateMigrationSequence(); err != nil {
+		t.Errorf("embedded migrations are invalid: %v", err)
+	}
+}
+
+func TestValidateMigrationSequence_Gap(t *testing.T) {
+	err := validateMigrationSequence(migrationFS(
+		"000013_a.up.sql", "000013_a.down.sql",
+		"000015_c.up.sql",
+		"000019_d.up.sql",
+	))
+	if err == nil {
+		t.Fatal("expected gaps to be reported")
+	}
+	for _, want := range []string{"missing version 14", "missing versions 16-18"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestValidateMigrationSequence_Duplicate(t *testing.T) {
+	err := validateMigrationSequence(migrationFS(
+		"000001_init.up.sql",
+		"000002_users.up.sql",
+		"000002_projects.up.sql",
+	))
+	if err == nil || !strings.Contains(err.Error(), "duplicate up migration 2: 000002_projects.up.sql, 000002_users.up.sql") {
+		t.Errorf("expected a duplicate to be reported, got %v", err)
+	}
+}
+
+func TestValidateMigrationSequence_Valid(t *testing.T) {
+	if err := validateMigrationSequence(migrationFS("000001_a.up.sql", "000002_b.up.sql", "000002_b.down.sql")); err != nil {
+		t.Errorf("expected a valid sequence, got %v", err)
+	}
+}
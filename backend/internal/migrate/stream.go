@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MigrationState is the stage a migration has reached
+type MigrationState string
+
+const (
+	MigrationStarted MigrationState = "started"
+	MigrationApplied MigrationState = "applied"
+	MigrationFailed  MigrationState = "failed"
+)
+
+// MigrationProgress is emitted by UpStream as each migration runs
+type MigrationProgress struct {
+	Version uint
+	Name    string
+	State   MigrationState
+	// Elapsed is how long the migration ran; zero for MigrationStarted
+	Elapsed time.Duration
+}
+
+// String renders the event for an operator, e.g.
+// "applied 15 015_add_claims.up.sql (2.3s)"
+func (p MigrationProgress) String() string {
+	if p.State == MigrationStarted {
+		return fmt.Sprintf("applying %d %s...", p.Version, p.Name)
+	}
+	return fmt.Sprintf("%s %d %s (%s)", p.State, p.Version, p.Name, p.Elapsed.Round(100*time.Millisecond))
+}
+
+// ProgressStream delivers migration progress from UpStream
+type ProgressStream struct {
+	// Events receives progress as migrations run and is closed when the run
+	// finishes, successfully or not
+	Events <-chan MigrationProgress
+
+	done chan struct{}
+	err  error
+}
+
+// Err blocks until the run finishes and returns its final error
+func (s *ProgressStream) Err() error {
+	<-s.done
+	return s.err
+}
+
+// UpStream runs UpWithOptions in the background and streams a progress event
+// as each migration starts, is applied or fails. Hooks already set in opts are
+// still called. Events must be drained for the run to make progress.
+func UpStream(ctx context.Context, pool *pgxpool.Pool, opts MigrateOptions) *ProgressStream {
+	events := make(chan MigrationProgress, 16)
+	stream := &ProgressStream{Events: events, done: make(chan struct{})}
+
+	send := func(p MigrationProgress) {
+		select {
+		case events <- p:
+		case <-ctx.Done():
+		}
+	}
+
+	hooks := opts.Hooks
+	opts.Hooks = Hooks{
+		OnMigrationStarted: func(version uint, name string) {
+			send(MigrationProgress{Version: version, Name: name, State: MigrationStarted})
+			if hooks.OnMigrationStarted != nil {
+				hooks.OnMigrationStarted(version, name)
+			}
+		},
+		OnMigrationApplied: func(version uint, name string, dur time.Duration) {
+			send(MigrationProgress{Version: version, Name: name, State: MigrationApplied, Elapsed: dur})
+			if hooks.OnMigrationApplied != nil {
+				hooks.OnMigrationApplied(version, name, dur)
+			}
+		},
+		OnMigrationFailed: func(version uint, name string, dur time.Duration, err error) {
+			send(MigrationProgress{Version: version, Name: name, State: MigrationFailed, Elapsed: dur})
+			if hooks.OnMigrationFailed != nil {
+				hooks.OnMigrationFailed(version, name, dur, err)
+			}
+		},
+	}
+
+	go func() {
+		defer close(stream.done)
+		defer close(events)
+		stream.err = UpWithOptions(ctx, pool, opts)
+	}()
+
+	return stream
+}
@@ -0,0 +1,197 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// Hooks are optional callbacks invoked while migrations run
+type Hooks struct {
+	// OnMigrationStarted is called just before a migration file's SQL runs
+	OnMigrationStarted func(version uint, name string)
+	// OnMigrationApplied is called after each migration file is applied with
+	// its version, file name and how long it took to execute
+	OnMigrationApplied func(version uint, name string, dur time.Duration)
+	// OnMigrationFailed is called when a migration file's SQL fails
+	OnMigrationFailed func(version uint, name string, dur time.Duration, err error)
+}
+
+// timingSource decorates a source driver to log each migration file as it is
+// read and to remember file names by version for timingDatabase.
+//
+// golang-migrate prefetches files ahead of applying them and closes each body
+// before its SQL runs, so read timing alone says nothing about how long a
+// migration took to apply; that is measured by timingDatabase.
+type timingSource struct {
+	source.Driver
+
+	mu    sync.Mutex
+	names map[uint]string
+}
+
+func newTimingSource(src source.Driver) *timingSource {
+	return &timingSource{Driver: src, names: make(map[uint]string)}
+}
+
+// Open is not supported; timingSource only wraps an existing driver
+func (s *timingSource) Open(url string) (source.Driver, error) {
+	return nil, fmt.Errorf("timing source must wrap an opened driver")
+}
+
+func (s *timingSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	r, identifier, err := s.Driver.ReadUp(version)
+	if err != nil {
+		return r, identifier, err
+	}
+
+	s.mu.Lock()
+	s.names[version] = identifier
+	s.mu.Unlock()
+
+	return newTimedReader(r, version, identifier, "up"), identifier, nil
+}
+
+func (s *timingSource) ReadDown(version uint) (io.ReadCloser, string, error) {
+	r, identifier, err := s.Driver.ReadDown(version)
+	if err != nil {
+		return r, identifier, err
+	}
+	return newTimedReader(r, version, identifier, "down"), identifier, nil
+}
+
+// name returns the up-migration file name recorded for version
+func (s *timingSource) name(version uint) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.names[version]
+}
+
+// timedReader logs when a migration file body starts and finishes being read
+type timedReader struct {
+	io.ReadCloser
+	version   uint
+	name      string
+	direction string
+	start     time.Time
+	bytes     int64
+}
+
+func newTimedReader(r io.ReadCloser, version uint, name, direction string) *timedReader {
+	slog.Debug("migration file read started",
+		"version", version,
+		"name", name,
+		"direction", direction,
+	)
+	return &timedReader{ReadCloser: r, version: version, name: name, direction: direction, start: time.Now()}
+}
+
+func (r *timedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *timedReader) Close() error {
+	slog.Debug("migration file read finished",
+		"version", r.version,
+		"name", r.name,
+		"direction", r.direction,
+		"bytes", r.bytes,
+		"duration_ms", time.Since(r.start).Milliseconds(),
+	)
+	return r.ReadCloser.Close()
+}
+
+// timingDatabase decorates a database driver to time each applied migration.
+// golang-migrate marks a version dirty before running its SQL and clean once it
+// succeeds, so the time between the two SetVersion calls is the apply time.
+type timingDatabase struct {
+	database.Driver
+	src   *timingSource
+	hooks Hooks
+
+	mu      sync.Mutex
+	started map[int]time.Time
+	// running is the version whose SQL is executing, or -1
+	running int
+}
+
+func newTimingDatabase(db database.Driver, src *timingSource, hooks Hooks) *timingDatabase {
+	return &timingDatabase{Driver: db, src: src, hooks: hooks, started: make(map[int]time.Time), running: -1}
+}
+
+// Run reports a failed migration; success is reported by the clean SetVersion
+// that follows it
+func (d *timingDatabase) Run(migration io.Reader) error {
+	err := d.Driver.Run(migration)
+	if err == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	version := d.running
+	start, ok := d.started[version]
+	delete(d.started, version)
+	d.running = -1
+	d.mu.Unlock()
+
+	if !ok || version < 0 {
+		return err
+	}
+	dur := time.Since(start)
+	name := d.src.name(uint(version))
+	slog.Error("migration failed",
+		"version", version,
+		"name", name,
+		"duration_ms", dur.Milliseconds(),
+		"error", err,
+	)
+	if d.hooks.OnMigrationFailed != nil {
+		d.hooks.OnMigrationFailed(uint(version), name, dur, err)
+	}
+	return err
+}
+
+func (d *timingDatabase) SetVersion(version int, dirty bool) error {
+	if err := d.Driver.SetVersion(version, dirty); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	if dirty {
+		d.started[version] = time.Now()
+		d.running = version
+		d.mu.Unlock()
+		if version >= 0 && d.hooks.OnMigrationStarted != nil {
+			d.hooks.OnMigrationStarted(uint(version), d.src.name(uint(version)))
+		}
+		return nil
+	}
+	start, ok := d.started[version]
+	delete(d.started, version)
+	d.running = -1
+	d.mu.Unlock()
+
+	// A clean SetVersion without a preceding dirty one (e.g. Force) isn't a migration run
+	if !ok || version < 0 {
+		return nil
+	}
+
+	dur := time.Since(start)
+	name := d.src.name(uint(version))
+	slog.Info("migration applied",
+		"version", version,
+		"name", name,
+		"duration_ms", dur.Milliseconds(),
+	)
+	if d.hooks.OnMigrationApplied != nil {
+		d.hooks.OnMigrationApplied(uint(version), name, dur)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package soroban
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AmountFormatter renders integer token amounts as decimal strings
+type AmountFormatter struct {
+	// Decimals is the number of fractional digits the token uses
+	Decimals int
+	// Symbol is appended after the amount when set
+	Symbol string
+}
+
+// XLMFormatter formats stroop amounts as XLM (1 XLM = 10^7 stroops)
+var XLMFormatter = AmountFormatter{Decimals: 7, Symbol: "XLM"}
+
+// Format renders amount with f.Decimals fractional digits, trimming trailing
+// zeros, e.g. 12345000 stroops -> "1.2345 XLM"
+func (f AmountFormatter) Format(amount int64) string {
+	sign := ""
+	digits := fmt.Sprintf("%d", amount)
+	if amount < 0 {
+		sign = "-"
+		digits = digits[1:]
+	}
+
+	if f.Decimals > 0 {
+		if len(digits) <= f.Decimals {
+			digits = strings.Repeat("0", f.Decimals-len(digits)+1) + digits
+		}
+		whole, frac := digits[:len(digits)-f.Decimals], strings.TrimRight(digits[len(digits)-f.Decimals:], "0")
+		digits = whole
+		if frac != "" {
+			digits += "." + frac
+		}
+	}
+
+	if f.Symbol == "" {
+		return sign + digits
+	}
+	return sign + digits + " " + f.Symbol
+}
@@ -0,0 +1,24 @@
+package soroban
+
+import "testing"
+
+func TestAmountFormatter_Format(t *testing.T) {
+	tests := []struct {
+		f      AmountFormatter
+		amount int64
+		want   string
+	}{
+		{XLMFormatter, 12345000, "1.2345 XLM"},
+		{XLMFormatter, 10000000, "1 XLM"},
+		{XLMFormatter, 100, "0.00001 XLM"},
+		{XLMFormatter, 0, "0 XLM"},
+		{XLMFormatter, -25000000, "-2.5 XLM"},
+		{AmountFormatter{Decimals: 2}, 1999, "19.99"},
+		{AmountFormatter{}, 42, "42"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Format(tt.amount); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package soroban
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// maxSummaryItems is the number of vec or map items ArgsSummary shows before
+// eliding the rest
+const maxSummaryItems = 4
+
+// maxSummaryString is the number of characters of a string ArgsSummary shows
+const maxSummaryString = 32
+
+// argNames are the parameter names of the host functions this package
+// calls, so summaries can label arguments. Other functions are summarized
+// positionally.
+var argNames = map[string][]string{
+	"init":          {"admin", "token"},
+	"lock_funds":    {"depositor", "bounty_id", "amount", "deadline"},
+	"release_funds": {"bounty_id", "contributor"},
+	"refund":        {"bounty_id"},
+	"batch_payout":  {"recipients", "amounts"},
+	"single_payout": {"recipient", "amount"},
+	"upgrade":       {"new_wasm_hash"},
+	"set_admin":     {"new_admin"},
+}
+
+// ArgsSummary renders a compact, human-readable summary of a host function
+// call for logs and errors, e.g. upgrade(new_wasm_hash=ab12…ef). Long bytes,
+// strings, vecs and maps are truncated, and addresses are shortened the way
+// SandboxConfig.RedactAddresses shortens them.
+func ArgsSummary(fn string, args []xdr.ScVal) string {
+	names := argNames[fn]
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = scValSummary(arg)
+		if i < len(names) {
+			parts[i] = names[i] + "=" + parts[i]
+		}
+	}
+	return fn + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// invokeSummary returns the ArgsSummary of the first contract invocation in
+// operations, or "" if there is none
+func invokeSummary(operations []txnbuild.Operation) string {
+	for _, op := range operations {
+		ihf, ok := op.(*txnbuild.InvokeHostFunction)
+		if !ok {
+			continue
+		}
+		if invoke, ok := ihf.HostFunction.GetInvokeContract(); ok {
+			return ArgsSummary(string(invoke.FunctionName), invoke.Args)
+		}
+	}
+	return ""
+}
+
+// scValSummary renders one value for ArgsSummary
+func scValSummary(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvVoid:
+		return "()"
+	case xdr.ScValTypeScvBool:
+		return strconv.FormatBool(*v.B)
+	case xdr.ScValTypeScvU32:
+		return strconv.FormatUint(uint64(*v.U32), 10)
+	case xdr.ScValTypeScvI32:
+		return strconv.FormatInt(int64(*v.I32), 10)
+	case xdr.ScValTypeScvU64:
+		return strconv.FormatUint(uint64(*v.U64), 10)
+	case xdr.ScValTypeScvI64:
+		return strconv.FormatInt(int64(*v.I64), 10)
+	case xdr.ScValTypeScvTimepoint:
+		return strconv.FormatUint(uint64(*v.Timepoint), 10)
+	case xdr.ScValTypeScvDuration:
+		return strconv.FormatUint(uint64(*v.Duration), 10)
+	case xdr.ScValTypeScvU128:
+		n := new(big.Int).Lsh(new(big.Int).SetUint64(uint64(v.U128.Hi)), 64)
+		return n.Or(n, new(big.Int).SetUint64(uint64(v.U128.Lo))).String()
+	case xdr.ScValTypeScvI128:
+		n := new(big.Int).Lsh(big.NewInt(int64(v.I128.Hi)), 64)
+		return n.Add(n, new(big.Int).SetUint64(uint64(v.I128.Lo))).String()
+	case xdr.ScValTypeScvBytes:
+		return shortHex(*v.Bytes)
+	case xdr.ScValTypeScvString:
+		return strconv.Quote(truncateSummary(string(*v.Str)))
+	case xdr.ScValTypeScvSymbol:
+		return string(*v.Sym)
+	case xdr.ScValTypeScvAddress:
+		addr, err := v.Address.String()
+		if err != nil {
+			return "<invalid address>"
+		}
+		return redactAddress(addr, true)
+	case xdr.ScValTypeScvVec:
+		if v.Vec == nil || *v.Vec == nil {
+			return "[]"
+		}
+		vec := **v.Vec
+		items := make([]string, 0, min(len(vec), maxSummaryItems))
+		for _, item := range vec[:min(len(vec), maxSummaryItems)] {
+			items = append(items, scValSummary(item))
+		}
+		return "[" + strings.Join(items, ", ") + elided(len(vec)) + "]"
+	case xdr.ScValTypeScvMap:
+		if v.Map == nil || *v.Map == nil {
+			return "{}"
+		}
+		m := **v.Map
+		items := make([]string, 0, min(len(m), maxSummaryItems))
+		for _, entry := range m[:min(len(m), maxSummaryItems)] {
+			items = append(items, scValSummary(entry.Key)+": "+scValSummary(entry.Val))
+		}
+		return "{" + strings.Join(items, ", ") + elided(len(m)) + "}"
+	case xdr.ScValTypeScvError:
+		return fmt.Sprintf("Error(%s)", v.Error.Type)
+	default:
+		return "<" + v.Type.String() + ">"
+	}
+}
+
+// shortHex hex-encodes b, keeping the first two and last byte of long values
+func shortHex(b []byte) string {
+	s := hex.EncodeToString(b)
+	if len(b) <= 8 {
+		return s
+	}
+	return s[:4] + "…" + s[len(s)-2:]
+}
+
+// truncateSummary cuts s to maxSummaryString characters
+func truncateSummary(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxSummaryString {
+		return s
+	}
+	return string(runes[:maxSummaryString]) + "…"
+}
+
+// elided notes how many of n items a truncated summary left out
+func elided(n int) string {
+	if n <= maxSummaryItems {
+		return ""
+	}
+	return fmt.Sprintf(", …+%d", n-maxSummaryItems)
+}
@@ -0,0 +1,70 @@
+package soroban
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestArgsSummary(t *testing.T) {
+	hash := make([]byte, 32)
+	hash[0], hash[1], hash[31] = 0xab, 0x12, 0xef
+	hashVal, _ := EncodeScValBytes(hash)
+	if got := ArgsSummary("upgrade", []xdr.ScVal{hashVal}); got != "upgrade(new_wasm_hash=ab12…ef)" {
+		t.Errorf("unexpected summary %q", got)
+	}
+
+	addr := keypair.MustRandom().Address()
+	addrVal, _ := EncodeScValAddress(addr)
+	id, _ := EncodeScValUint64(7)
+	got := ArgsSummary("release_funds", []xdr.ScVal{id, addrVal})
+	if want := "release_funds(bounty_id=7, contributor=" + redactAddress(addr, true) + ")"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if strings.Contains(got, addr) {
+		t.Error("expected the address to be redacted")
+	}
+
+	// Unknown functions are summarized positionally
+	flag, _ := EncodeScValBool(true)
+	if got := ArgsSummary("custom", []xdr.ScVal{flag, NewScValVec()}); got != "custom(true, [])" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
+
+func TestScValSummary_TruncatesVecs(t *testing.T) {
+	items := make([]xdr.ScVal, 10)
+	for i := range items {
+		items[i], _ = EncodeScValUint32(uint32(i))
+	}
+	if got := scValSummary(NewScValVec(items...)); got != "[0, 1, 2, 3, …+6]" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
+
+func TestScValSummary_I128(t *testing.T) {
+	neg := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(^uint64(0))}}
+	if got := scValSummary(neg); got != "-1" {
+		t.Errorf("expected -1, got %q", got)
+	}
+	big := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: 1, Lo: 0}}
+	if got := scValSummary(big); got != "18446744073709551616" {
+		t.Errorf("expected 2^64, got %q", got)
+	}
+}
+
+func TestInvokeSummary(t *testing.T) {
+	op, err := buildContractOp(testContractHex, "refund", []xdr.ScVal{{Type: xdr.ScValTypeScvU64, U64: new(xdr.Uint64)}})
+	if err != nil {
+		t.Fatalf("buildContractOp failed: %v", err)
+	}
+	if got := invokeSummary([]txnbuild.Operation{op}); got != "refund(bounty_id=0)" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	if got := invokeSummary(nil); got != "" {
+		t.Errorf("expected no summary, got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one privileged operation submitted through this package
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Operation string    `json:"operation"`
+	Contract  string    `json:"contract"`
+	// Signer is the public key that signed the transaction
+	Signer string                 `json:"signer"`
+	Args   map[string]interface{} `json:"args,omitempty"`
+	// Call is the ArgsSummary of the submitted host function call
+	Call   string `json:"call,omitempty"`
+	TxHash string `json:"tx_hash"`
+}
+
+// AuditLogger persists AuditEntry records
+type AuditLogger interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
+
+// FileAuditLogger appends audit entries to a file as JSON lines
+type FileAuditLogger struct {
+	mu   sync.Mutex
+	file *os.File
+	w    jsonlWriter
+}
+
+// NewFileAuditLogger opens path for appending, creating it if needed
+func NewFileAuditLogger(path string) (*FileAuditLogger, error) {
+	return NewFileAuditLoggerWithCodec(path, CodecJSONL)
+}
+
+// NewFileAuditLoggerWithCodec is NewFileAuditLogger writing with codec. Read
+// the file back with OpenJSONL.
+func NewFileAuditLoggerWithCodec(path string, codec Codec) (*FileAuditLogger, error) {
+	if err := codec.validate(); err != nil {
+		return nil, err
+	}
+	file, err := openJSONLFile(path, codec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileAuditLogger{file: file, w: newJSONLWriter(file, codec)}, nil
+}
+
+// Record appends entry as a single JSON line and syncs it to disk
+func (l *FileAuditLogger) Record(ctx context.Context, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	return nil
+}
+
+// Close ends the encoding and closes the underlying file
+func (l *FileAuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.w.Close(), l.file.Close())
+}
+
+// recordAudit writes entry to logger if one is configured. It runs after the
+// transaction was submitted, so a failed write is logged as an error but never
+// reported as a failure of the operation itself.
+func recordAudit(ctx context.Context, logger AuditLogger, entry AuditEntry) {
+	if logger == nil {
+		return
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if err := logger.Record(ctx, entry); err != nil {
+		slog.Error("audit log write failed for committed operation",
+			"error", err,
+			"operation", entry.Operation,
+			"contract", entry.Contract,
+			"signer", entry.Signer,
+			"call", entry.Call,
+			"tx_hash", entry.TxHash,
+		)
+	}
+}
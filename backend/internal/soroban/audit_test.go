@@ -0,0 +1,65 @@
+package soroban
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileAuditLogger_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for i, op := range []string{"upgrade", "set_admin"} {
+		logger, err := NewFileAuditLogger(path)
+		if err != nil {
+			t.Fatalf("NewFileAuditLogger failed: %v", err)
+		}
+		recordAudit(context.Background(), logger, AuditEntry{Operation: op, Contract: "C1", TxHash: string(rune('a' + i))})
+		logger.Close()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 || entries[0].Operation != "upgrade" || entries[1].Operation != "set_admin" {
+		t.Fatalf("expected both entries appended in order, got %+v", entries)
+	}
+	if entries[0].Timestamp.IsZero() {
+		t.Error("expected a timestamp to be filled in")
+	}
+}
+
+type failingAuditLogger struct{ calls int }
+
+func (l *failingAuditLogger) Record(ctx context.Context, entry AuditEntry) error {
+	l.calls++
+	return errors.New("disk full")
+}
+
+func TestRecordAudit_FailureIsNotFatal(t *testing.T) {
+	logger := &failingAuditLogger{}
+	recordAudit(context.Background(), logger, AuditEntry{Operation: "upgrade"})
+	if logger.calls != 1 {
+		t.Errorf("expected one record attempt, got %d", logger.calls)
+	}
+
+	// A nil logger disables auditing
+	recordAudit(context.Background(), nil, AuditEntry{Operation: "upgrade"})
+}
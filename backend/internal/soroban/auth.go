@@ -0,0 +1,115 @@
+package soroban
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// authSignatureValidityLedgers is how many ledgers past the simulation's latest
+// ledger a signed authorization entry remains valid (~10 minutes at 5s/ledger)
+const authSignatureValidityLedgers = 120
+
+// signAuthEntries signs every address-credential authorization entry that
+// belongs to the signer. Source-account credentials are authorized by the
+// transaction signature itself and are passed through unchanged. Entries that
+// require a signature from any other address cannot be satisfied and are
+// reported as an error, as are all address entries when signer is nil.
+func signAuthEntries(entries []xdr.SorobanAuthorizationEntry, signer *keypair.Full, networkPassphrase string, latestLedger uint32) ([]xdr.SorobanAuthorizationEntry, error) {
+	signed := make([]xdr.SorobanAuthorizationEntry, len(entries))
+	for i, entry := range entries {
+		if entry.Credentials.Type != xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+			signed[i] = entry
+			continue
+		}
+
+		creds := entry.Credentials.Address
+		if creds == nil {
+			return nil, fmt.Errorf("auth entry %d has no address credentials", i)
+		}
+
+		addr, err := creds.Address.String()
+		if err != nil {
+			return nil, fmt.Errorf("auth entry %d: invalid address: %w", i, err)
+		}
+		if signer == nil {
+			return nil, fmt.Errorf("auth entry %d requires a signature from %s, but the signer cannot sign authorization entries", i, addr)
+		}
+		if addr != signer.Address() {
+			return nil, fmt.Errorf("auth entry %d requires a signature from %s, which is not the signing account", i, addr)
+		}
+
+		signedEntry, err := signAuthEntry(entry, signer, networkPassphrase, latestLedger+authSignatureValidityLedgers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign auth entry %d: %w", i, err)
+		}
+		signed[i] = signedEntry
+	}
+	return signed, nil
+}
+
+// signAuthEntry signs a single address-credential authorization entry with the
+// given key, valid until expirationLedger
+func signAuthEntry(entry xdr.SorobanAuthorizationEntry, signer *keypair.Full, networkPassphrase string, expirationLedger uint32) (xdr.SorobanAuthorizationEntry, error) {
+	creds := *entry.Credentials.Address
+	creds.SignatureExpirationLedger = xdr.Uint32(expirationLedger)
+
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 xdr.Hash(network.ID(networkPassphrase)),
+			Nonce:                     creds.Nonce,
+			SignatureExpirationLedger: creds.SignatureExpirationLedger,
+			Invocation:                entry.RootInvocation,
+		},
+	}
+	payload, err := preimage.MarshalBinary()
+	if err != nil {
+		return xdr.SorobanAuthorizationEntry{}, fmt.Errorf("failed to encode auth preimage: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+
+	sig, err := signer.Sign(hash[:])
+	if err != nil {
+		return xdr.SorobanAuthorizationEntry{}, fmt.Errorf("failed to sign auth preimage: %w", err)
+	}
+
+	// Stellar accounts expect Vec<Map{public_key: Bytes, signature: Bytes}>
+	publicKey, err := strkey.Decode(strkey.VersionByteAccountID, signer.Address())
+	if err != nil {
+		return xdr.SorobanAuthorizationEntry{}, fmt.Errorf("failed to decode signer public key: %w", err)
+	}
+	creds.Signature = authSignatureScVal(publicKey, sig)
+
+	entry.Credentials.Address = &creds
+	return entry, nil
+}
+
+// authSignatureScVal builds the account signature value expected by the host
+func authSignatureScVal(publicKey, signature []byte) xdr.ScVal {
+	pkBytes := xdr.ScBytes(publicKey)
+	sigBytes := xdr.ScBytes(signature)
+	pkSym := xdr.ScSymbol("public_key")
+	sigSym := xdr.ScSymbol("signature")
+
+	sigMap := xdr.ScMap{
+		{
+			Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &pkSym},
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &pkBytes},
+		},
+		{
+			Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sigSym},
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &sigBytes},
+		},
+	}
+	sigMapPtr := &sigMap
+	sigVal := xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &sigMapPtr}
+
+	vec := xdr.ScVec{sigVal}
+	vecPtr := &vec
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}
+}
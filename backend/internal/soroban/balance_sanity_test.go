@@ -0,0 +1,36 @@
+package soroban
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewBalanceSanityReport(t *testing.T) {
+	r := newBalanceSanityReport(1200, 1000, 3)
+	if !r.Sane || r.Surplus != 200 || r.Discrepancy != "" {
+		t.Errorf("expected a 200 surplus to pass, got %+v", r)
+	}
+
+	r = newBalanceSanityReport(1000, 1000, 3)
+	if !r.Sane || r.Surplus != 0 {
+		t.Errorf("expected an exactly covered balance to pass, got %+v", r)
+	}
+
+	r = newBalanceSanityReport(900, 1000, 3)
+	if r.Sane || r.Surplus != -100 {
+		t.Fatalf("expected a 100 deficit to fail, got %+v", r)
+	}
+	if !strings.Contains(r.Discrepancy, "100 short of 1000 locked across 3 escrows") {
+		t.Errorf("expected the deficit in the discrepancy, got %q", r.Discrepancy)
+	}
+}
+
+func TestCheckBalanceSanity_ListFailure(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: failingServer(t).URL})
+	mc := NewMaintenanceClient(client, nil, testContractHex)
+
+	if _, err := mc.CheckBalanceSanity(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to list bounties") {
+		t.Errorf("expected the listing failure to fail the check, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package soroban
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+// safetyReportVal encodes an UpgradeSafetyReport the way simulate_upgrade
+// returns it, with one warning
+func safetyReportVal(passed uint32) xdr.ScVal {
+	structVal := func(fields ...xdr.ScMapEntry) xdr.ScVal {
+		m := xdr.ScMap(fields)
+		mPtr := &m
+		return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+	}
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	u32 := func(n uint32) xdr.ScVal {
+		v := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	}
+	msg := xdr.ScString("pending claims")
+	isSafe := true
+
+	warning := structVal(field("code", u32(1004)), field("message", xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &msg}))
+	return structVal(
+		field("checks_failed", u32(0)),
+		field("checks_passed", u32(passed)),
+		field("errors", NewScValVec()),
+		field("is_safe", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &isSafe}),
+		field("warnings", NewScValVec(warning)),
+	)
+}
+
+func BenchmarkDecodeUpgradeSafetyReport(b *testing.B) {
+	v := safetyReportVal(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeUpgradeSafetyReport(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewScValVec(b *testing.B) {
+	vals := make([]xdr.ScVal, 1000)
+	for i := range vals {
+		vals[i], _ = EncodeScValUint64(uint64(i))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewScValVec(vals...)
+	}
+}
+
+func BenchmarkBatchPayoutOp_10k(b *testing.B) {
+	payouts := make([]PayoutItem, 10000)
+	for i := range payouts {
+		payouts[i] = PayoutItem{Recipient: keypair.MustRandom().Address(), Amount: int64(i + 1)}
+	}
+	pec := &ProgramEscrowContract{contractAddress: testContractHex}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := pec.batchPayoutOp(payouts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package soroban
+
+import (
+	"math"
+)
+
+const (
+	// DefaultBatchChunkSize is the fixed number of items per batch transaction
+	DefaultBatchChunkSize = 50
+	// MaxBatchSize is the most payouts BatchPayout sends in one transaction,
+	// matching the program contract's own batch limit. Larger batches fail
+	// with ErrBatchTooLarge; use BatchPayoutChunked for them.
+	MaxBatchSize = 100
+	// DefaultChunkSafetyMargin is the share of the resource limits an
+	// adaptive chunk is allowed to use
+	DefaultChunkSafetyMargin = 0.8
+
+	// chunkProbeSize is how many items are simulated to measure per-item cost
+	chunkProbeSize = 5
+)
+
+// ResourceLimits are the per-transaction Soroban resource limits
+type ResourceLimits struct {
+	Instructions  uint32
+	DiskReadBytes uint32
+	WriteBytes    uint32
+	ReadEntries   uint32
+	WriteEntries  uint32
+}
+
+// DefaultResourceLimits are the network's per-transaction limits
+var DefaultResourceLimits = ResourceLimits{
+	Instructions:  100_000_000,
+	DiskReadBytes: 200_000,
+	WriteBytes:    132_096,
+	ReadEntries:   40,
+	WriteEntries:  25,
+}
+
+// perItemUsage divides the resources of a simulated batch of n items into a
+// per-item cost. The batch's fixed overhead is attributed to the items too,
+// which keeps the estimate conservative.
+func perItemUsage(usage ResourceUsage, n int) ResourceLimits {
+	div := func(v uint32) uint32 {
+		return uint32(math.Ceil(float64(v) / float64(n)))
+	}
+	return ResourceLimits{
+		Instructions:  div(usage.Instructions),
+		DiskReadBytes: div(usage.DiskReadBytes),
+		WriteBytes:    div(usage.WriteBytes),
+		ReadEntries:   div(usage.ReadEntries),
+		WriteEntries:  div(usage.WriteEntries),
+	}
+}
+
+// maxItems returns the largest number of items of the given per-item cost
+// that fit within margin of every limit. Zero-cost resources don't constrain.
+func (l ResourceLimits) maxItems(perItem ResourceLimits, margin float64) int {
+	if margin <= 0 || margin > 1 {
+		margin = DefaultChunkSafetyMargin
+	}
+	best := math.MaxInt32
+	fit := func(limit, cost uint32) {
+		if cost == 0 {
+			return
+		}
+		if n := int(float64(limit) * margin / float64(cost)); n < best {
+			best = n
+		}
+	}
+	fit(l.Instructions, perItem.Instructions)
+	fit(l.DiskReadBytes, perItem.DiskReadBytes)
+	fit(l.WriteBytes, perItem.WriteBytes)
+	fit(l.ReadEntries, perItem.ReadEntries)
+	fit(l.WriteEntries, perItem.WriteEntries)
+	return best
+}
@@ -0,0 +1,106 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestResourceLimits_MaxItems(t *testing.T) {
+	limits := ResourceLimits{Instructions: 1000, DiskReadBytes: 1000, WriteBytes: 1000, ReadEntries: 40, WriteEntries: 25}
+
+	// Write entries are the binding constraint: 25 * 0.8 / 2 = 10
+	got := limits.maxItems(ResourceLimits{Instructions: 10, WriteEntries: 2}, 0.8)
+	if got != 10 {
+		t.Errorf("expected 10 items, got %d", got)
+	}
+
+	// An out-of-range margin falls back to the default
+	if got := limits.maxItems(ResourceLimits{Instructions: 100}, 0); got != 8 {
+		t.Errorf("expected the default margin to allow 8 items, got %d", got)
+	}
+}
+
+func TestPerItemUsage_RoundsUp(t *testing.T) {
+	got := perItemUsage(ResourceUsage{Instructions: 1001, WriteBytes: 10}, 5)
+	if got.Instructions != 201 || got.WriteBytes != 2 {
+		t.Errorf("expected per-item cost to round up, got %+v", got)
+	}
+}
+
+func TestChunkSize_Adaptive(t *testing.T) {
+	txData, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{Instructions: 5_000_000},
+	})
+	srv := rpcServer(t, fmt.Sprintf(`{"minResourceFee":"100","transactionData":%q,"latestLedger":10}`, txData), nil)
+	pec := testProgramContract(t, srv.URL)
+	pec.AdaptiveChunking = true
+
+	// 5 probe items cost 1M instructions each: 100M * 0.8 / 1M = 80
+	if got := pec.chunkSize(context.Background(), testPayouts(200)); got != 80 {
+		t.Errorf("expected adaptive chunk size 80, got %d", got)
+	}
+}
+
+func TestChunkSize_FallsBackWithoutSimulation(t *testing.T) {
+	pec := testProgramContract(t, failingServer(t).URL)
+	pec.AdaptiveChunking = true
+	pec.BatchChunkSize = 7
+
+	if got := pec.chunkSize(context.Background(), testPayouts(20)); got != 7 {
+		t.Errorf("expected fallback to the fixed chunk size, got %d", got)
+	}
+}
+
+func TestChunkSize_CappedAtMaxBatchSize(t *testing.T) {
+	pec := testProgramContract(t, failingServer(t).URL)
+	pec.BatchChunkSize = MaxBatchSize + 50
+
+	if got := pec.chunkSize(context.Background(), testPayouts(500)); got != MaxBatchSize {
+		t.Errorf("expected the chunk size capped at %d, got %d", MaxBatchSize, got)
+	}
+}
+
+func TestBatchPayout_MaxBatchSize(t *testing.T) {
+	pec := testProgramContract(t, failingServer(t).URL)
+
+	_, err := pec.BatchPayout(context.Background(), testPayouts(MaxBatchSize+1))
+	if !errors.Is(err, ErrBatchTooLarge) {
+		t.Fatalf("expected ErrBatchTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "101 payouts, limit is 100") {
+		t.Errorf("expected the size and limit in the error, got %v", err)
+	}
+
+	// A batch at the limit gets past the guard and fails later, offline
+	if _, err := pec.BatchPayout(context.Background(), testPayouts(MaxBatchSize)); err == nil || errors.Is(err, ErrBatchTooLarge) {
+		t.Errorf("expected a batch at the limit to be accepted by the guard, got %v", err)
+	}
+}
+
+func testProgramContract(t *testing.T, rpcURL string) *ProgramEscrowContract {
+	t.Helper()
+	client, err := NewClient(Config{RPCURL: rpcURL})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	pec, err := NewProgramEscrowContract(client, tb, testContractHex)
+	if err != nil {
+		t.Fatalf("NewProgramEscrowContract failed: %v", err)
+	}
+	return pec
+}
+
+func testPayouts(n int) []PayoutItem {
+	payouts := make([]PayoutItem, n)
+	for i := range payouts {
+		payouts[i] = PayoutItem{Recipient: keypair.MustRandom().Address(), Amount: int64(i + 1)}
+	}
+	return payouts
+}
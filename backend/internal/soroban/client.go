@@ -1,13 +1,16 @@
 package soroban
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/network"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client wraps Soroban RPC client and Horizon client for contract interactions
@@ -17,6 +20,12 @@ type Client struct {
 	horizonClient     *horizonclient.Client
 	httpClient        *http.Client
 	network           Network
+	maxReturnBytes    int
+	endpoints         *endpointPool
+	tracer            trace.Tracer
+	observer          RPCObserver
+	confirmSlots      chan struct{} // bounds in-flight confirmation polls
+	confirmEstimate   *confirmTimeCache
 }
 
 // Config holds configuration for Soroban client
@@ -24,9 +33,51 @@ type Config struct {
 	RPCURL           string // Soroban RPC endpoint
 	NetworkPassphrase string // Network passphrase
 	Network         Network // "testnet" or "mainnet"
-	HTTPTimeout     time.Duration
+	HTTPTimeout     time.Duration // Whole-request timeout of the default HTTP client (default: 30s)
+	MaxReturnBytes  int // Largest simulated return value decoded, in XDR bytes (default: 1 MiB)
+
+	// FallbackRPCURLs are tried in order when RPCURL fails at the connection
+	// level. Endpoints that fail EndpointEjectAfter times in a row (default 3)
+	// are skipped for EndpointEjectFor (default 30s).
+	FallbackRPCURLs    []string
+	EndpointEjectAfter int
+	EndpointEjectFor   time.Duration
+
+	// Tracer, when set, wraps each RPC call and transaction submission in an
+	// OpenTelemetry span. Nil disables tracing.
+	Tracer trace.Tracer
+
+	// RPCObserver, when set, is told about every RPC request the client
+	// makes, including each failover attempt
+	RPCObserver RPCObserver
+
+	// MaxConfirmationPollers caps how many confirmation polls are in flight
+	// at once across every TransactionBuilder sharing the client, so a burst
+	// of submissions doesn't flood the network with status lookups
+	// (default: DefaultMaxConfirmationPollers)
+	MaxConfirmationPollers int
+
+	// HTTPClient, if set, carries every RPC and Horizon request, e.g. to
+	// route through a proxy, present a client certificate or tune
+	// keep-alives; HTTPTimeout is then ignored. When nil, the client uses
+	// one with HTTPTimeout and the Default*Timeout transport limits. Either
+	// way a context deadline bounds each call.
+	HTTPClient *http.Client
+}
+
+// RPCObserver is notified of each JSON-RPC request with its method, how long
+// it took and its error, e.g. to count the simulation, submission and
+// confirmation polls behind one LockFunds. Source account loads go to
+// Horizon, not the RPC, and aren't reported. OnCall must be safe for
+// concurrent use.
+type RPCObserver interface {
+	OnCall(method string, dur time.Duration, err error)
 }
 
+// DefaultMaxReturnBytes bounds the size of a simulated return value so a
+// contract returning an unbounded collection can't exhaust memory
+const DefaultMaxReturnBytes = 1 << 20
+
 // NewClient creates a new Soroban client
 func NewClient(cfg Config) (*Client, error) {
 	if cfg.RPCURL == "" {
@@ -41,35 +92,99 @@ func NewClient(cfg Config) (*Client, error) {
 			cfg.NetworkPassphrase = network.TestNetworkPassphrase
 		}
 	}
+	// A blank passphrase would only surface later as rejected signatures
+	if strings.TrimSpace(cfg.NetworkPassphrase) == "" {
+		return nil, fmt.Errorf("network passphrase is required")
+	}
+	switch known := networkForPassphrase(cfg.NetworkPassphrase); {
+	case cfg.Network == "":
+		cfg.Network = known
+	case known != "" && known != cfg.Network:
+		return nil, fmt.Errorf("network passphrase %q does not match network %q", cfg.NetworkPassphrase, cfg.Network)
+	}
 
 	if cfg.HTTPTimeout == 0 {
 		cfg.HTTPTimeout = 30 * time.Second
 	}
 
-	// Create Horizon client
-	horizonURL := "https://horizon-testnet.stellar.org"
-	if cfg.Network == NetworkMainnet {
-		horizonURL = "https://horizon.stellar.org"
+	if cfg.MaxReturnBytes <= 0 {
+		cfg.MaxReturnBytes = DefaultMaxReturnBytes
 	}
 
-	horizonClient := &horizonclient.Client{
-		HorizonURL: horizonURL,
-		HTTP: &http.Client{
-			Timeout: cfg.HTTPTimeout,
-		},
+	if cfg.MaxConfirmationPollers <= 0 {
+		cfg.MaxConfirmationPollers = DefaultMaxConfirmationPollers
 	}
 
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = newDefaultHTTPClient(cfg.HTTPTimeout)
+	}
+
+	// Create Horizon client. Setting the timeout up front matters: the
+	// client otherwise writes its default into itself on first use, which
+	// races between concurrent confirmation polls.
+	horizonClient := (&horizonclient.Client{
+		HorizonURL: horizonURLFor(cfg.Network),
+		HTTP:       httpClient,
+	}).SetHorizonTimeout(horizonclient.HorizonTimeout)
+
 	return &Client{
 		rpcURL:            cfg.RPCURL,
 		networkPassphrase: cfg.NetworkPassphrase,
 		horizonClient:     horizonClient,
-		httpClient: &http.Client{
-			Timeout: cfg.HTTPTimeout,
-		},
-		network: cfg.Network,
+		httpClient:        httpClient,
+		network:        cfg.Network,
+		maxReturnBytes: cfg.MaxReturnBytes,
+		endpoints: newEndpointPool(append([]string{cfg.RPCURL}, cfg.FallbackRPCURLs...),
+			cfg.EndpointEjectAfter, cfg.EndpointEjectFor),
+		tracer:          cfg.Tracer,
+		observer:        cfg.RPCObserver,
+		confirmSlots:    make(chan struct{}, cfg.MaxConfirmationPollers),
+		confirmEstimate: &confirmTimeCache{},
 	}, nil
 }
 
+// horizonURLFor returns the public Horizon endpoint for a network
+func horizonURLFor(n Network) string {
+	if n == NetworkMainnet {
+		return "https://horizon.stellar.org"
+	}
+	return "https://horizon-testnet.stellar.org"
+}
+
+// WithNetwork returns a lightweight derived client targeting a different
+// network, sharing this client's HTTP transports. Transactions built with the
+// derived client are signed with its passphrase, so signatures can't be
+// replayed across networks. The network type (and Horizon endpoint) is derived
+// from the public testnet/mainnet passphrases; for any other passphrase the
+// parent's network type and Horizon endpoint are kept.
+func (c *Client) WithNetwork(passphrase, rpcURL string) *Client {
+	derived := *c
+	// An empty passphrase can't sign anything usable, so keep the parent's
+	if passphrase != "" {
+		derived.networkPassphrase = passphrase
+	}
+	derived.rpcURL = rpcURL
+	// Fallback endpoints belong to the parent's network, so they aren't carried over
+	if c.endpoints != nil {
+		derived.endpoints = newEndpointPool([]string{rpcURL}, c.endpoints.ejectAfter, c.endpoints.ejectFor)
+	}
+
+	if n := networkForPassphrase(passphrase); n != "" {
+		derived.network = n
+	}
+	derived.confirmEstimate = &confirmTimeCache{}
+
+	if derived.network != c.network && c.horizonClient != nil {
+		derived.horizonClient = (&horizonclient.Client{
+			HorizonURL: horizonURLFor(derived.network),
+			HTTP:       c.horizonClient.HTTP,
+		}).SetHorizonTimeout(horizonclient.HorizonTimeout)
+	}
+
+	return &derived
+}
+
 // GetNetwork returns the network type
 func (c *Client) GetNetwork() Network {
 	return c.network
@@ -80,6 +195,46 @@ func (c *Client) GetNetworkPassphrase() string {
 	return c.networkPassphrase
 }
 
+// NetworkPassphrase returns the passphrase transactions are signed with
+func (c *Client) NetworkPassphrase() string {
+	return c.networkPassphrase
+}
+
+// IsTestnet reports whether the client signs for the public testnet
+func (c *Client) IsTestnet() bool {
+	return c.networkPassphrase == network.TestNetworkPassphrase
+}
+
+// IsMainnet reports whether the client signs for the public network
+func (c *Client) IsMainnet() bool {
+	return c.networkPassphrase == network.PublicNetworkPassphrase
+}
+
+// networkForPassphrase returns the network a standard passphrase belongs to,
+// or "" for any other passphrase
+func networkForPassphrase(passphrase string) Network {
+	switch passphrase {
+	case network.PublicNetworkPassphrase:
+		return NetworkMainnet
+	case network.TestNetworkPassphrase:
+		return NetworkTestnet
+	}
+	return ""
+}
+
+// requireNetwork returns ErrWrongNetwork unless the client uses the expected
+// network passphrase. An empty expected passphrase disables the check.
+func (c *Client) requireNetwork(expected string) error {
+	if expected == "" || c.networkPassphrase == expected {
+		return nil
+	}
+	slog.Error("refusing privileged operation on unexpected network",
+		"intended_network", expected,
+		"actual_network", c.networkPassphrase,
+	)
+	return fmt.Errorf("%w: intended %q, connected to %q", ErrWrongNetwork, expected, c.networkPassphrase)
+}
+
 // GetHorizonClient returns the Horizon client
 func (c *Client) GetHorizonClient() *horizonclient.Client {
 	return c.horizonClient
@@ -90,6 +245,42 @@ func (c *Client) GetRPCURL() string {
 	return c.rpcURL
 }
 
+// EndpointHealth returns the health of each configured RPC endpoint, primary
+// first, for monitoring
+func (c *Client) EndpointHealth() []EndpointHealth {
+	if c.endpoints == nil {
+		return []EndpointHealth{{URL: c.rpcURL, Healthy: true}}
+	}
+	return c.endpoints.health()
+}
+
+// endpointURLs returns the RPC endpoints to try, in order
+func (c *Client) endpointURLs() []string {
+	if c.endpoints == nil {
+		return []string{c.rpcURL}
+	}
+	return c.endpoints.order()
+}
+
+type maxReturnBytesKey struct{}
+
+// WithMaxReturnBytes overrides the client's MaxReturnBytes for calls made with
+// the returned context, for read methods that legitimately expect large results
+func WithMaxReturnBytes(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxReturnBytesKey{}, n)
+}
+
+// returnLimit returns the return-value size limit that applies to ctx
+func (c *Client) returnLimit(ctx context.Context) int {
+	if n, ok := ctx.Value(maxReturnBytesKey{}).(int); ok && n > 0 {
+		return n
+	}
+	if c.maxReturnBytes > 0 {
+		return c.maxReturnBytes
+	}
+	return DefaultMaxReturnBytes
+}
+
 // LogContractInteraction logs a contract interaction for debugging
 func (c *Client) LogContractInteraction(contractID, function string, args map[string]interface{}) {
 	slog.Info("contract interaction",
@@ -0,0 +1,157 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+)
+
+func TestClientWithNetwork(t *testing.T) {
+	base, err := NewClient(Config{
+		RPCURL:  "https://soroban-testnet.stellar.org",
+		Network: NetworkTestnet,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	mainnet := base.WithNetwork(network.PublicNetworkPassphrase, "https://mainnet.example.org")
+
+	if mainnet.GetNetworkPassphrase() != network.PublicNetworkPassphrase {
+		t.Errorf("expected mainnet passphrase, got %q", mainnet.GetNetworkPassphrase())
+	}
+	if mainnet.GetRPCURL() != "https://mainnet.example.org" {
+		t.Errorf("expected overridden RPC URL, got %q", mainnet.GetRPCURL())
+	}
+	if mainnet.GetNetwork() != NetworkMainnet {
+		t.Errorf("expected mainnet network, got %q", mainnet.GetNetwork())
+	}
+	if mainnet.GetHorizonClient().HorizonURL != "https://horizon.stellar.org" {
+		t.Errorf("expected mainnet horizon, got %q", mainnet.GetHorizonClient().HorizonURL)
+	}
+	if mainnet.httpClient != base.httpClient {
+		t.Error("expected derived client to share the RPC HTTP client")
+	}
+
+	// The parent must be unchanged.
+	if base.GetNetworkPassphrase() != network.TestNetworkPassphrase {
+		t.Errorf("parent passphrase changed to %q", base.GetNetworkPassphrase())
+	}
+	if base.GetHorizonClient().HorizonURL != "https://horizon-testnet.stellar.org" {
+		t.Errorf("parent horizon changed to %q", base.GetHorizonClient().HorizonURL)
+	}
+}
+
+func TestNewClient_NetworkPassphrase(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		passphrase string
+		testnet    bool
+		mainnet    bool
+	}{
+		{"default", Config{}, network.TestNetworkPassphrase, true, false},
+		{"testnet", Config{NetworkPassphrase: network.TestNetworkPassphrase}, network.TestNetworkPassphrase, true, false},
+		{"mainnet", Config{Network: NetworkMainnet}, network.PublicNetworkPassphrase, false, true},
+		{"mainnet passphrase", Config{NetworkPassphrase: network.PublicNetworkPassphrase}, network.PublicNetworkPassphrase, false, true},
+		{"standalone", Config{NetworkPassphrase: "Standalone Network ; February 2017"}, "Standalone Network ; February 2017", false, false},
+	}
+	for _, tt := range tests {
+		tt.cfg.RPCURL = "http://localhost"
+		c, err := NewClient(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: NewClient failed: %v", tt.name, err)
+		}
+		if c.NetworkPassphrase() != tt.passphrase || c.IsTestnet() != tt.testnet || c.IsMainnet() != tt.mainnet {
+			t.Errorf("%s: got passphrase %q, testnet %v, mainnet %v", tt.name, c.NetworkPassphrase(), c.IsTestnet(), c.IsMainnet())
+		}
+	}
+	if c, _ := NewClient(Config{RPCURL: "http://localhost", NetworkPassphrase: network.PublicNetworkPassphrase}); c.GetNetwork() != NetworkMainnet {
+		t.Errorf("expected the network to follow the passphrase, got %q", c.GetNetwork())
+	}
+}
+
+func TestNewClient_RejectsBadPassphrase(t *testing.T) {
+	if _, err := NewClient(Config{RPCURL: "http://localhost", NetworkPassphrase: "  "}); err == nil {
+		t.Error("expected an error for a blank passphrase")
+	}
+	_, err := NewClient(Config{RPCURL: "http://localhost", Network: NetworkMainnet, NetworkPassphrase: network.TestNetworkPassphrase})
+	if err == nil {
+		t.Error("expected an error for a passphrase that contradicts the network")
+	}
+}
+
+// countingTransport counts the requests it carries
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClient_InjectedHTTPClient(t *testing.T) {
+	srv := rpcServer(t, `{"status":"healthy"}`, nil)
+	transport := &countingTransport{}
+	client, err := NewClient(Config{RPCURL: srv.URL, HTTPClient: &http.Client{Transport: transport}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if _, err := client.Call(context.Background(), "getHealth", nil); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if n := transport.requests.Load(); n != 1 {
+		t.Errorf("expected the injected client to carry 1 request, got %d", n)
+	}
+	if client.GetHorizonClient().HTTP != client.httpClient {
+		t.Error("expected Horizon requests to use the injected client too")
+	}
+}
+
+func TestNewClient_DefaultHTTPClient(t *testing.T) {
+	client, err := NewClient(Config{RPCURL: "http://localhost"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.httpClient.Timeout != 30*time.Second {
+		t.Errorf("expected a 30s default timeout, got %v", client.httpClient.Timeout)
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected an *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.ResponseHeaderTimeout != DefaultResponseHeaderTimeout || transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("unexpected transport limits: %+v", transport)
+	}
+}
+
+func TestCall_ContextDeadlineBoundsInjectedClient(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	// No client timeout, so only the context can end the call
+	client, _ := NewClient(Config{RPCURL: srv.URL, HTTPClient: &http.Client{}})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Call(ctx, "getHealth", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline to end the call, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call outlived its context by %v", elapsed)
+	}
+}
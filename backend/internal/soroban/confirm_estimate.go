@@ -0,0 +1,131 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultConfirmTime is the confirmation estimate used when the RPC
+	// can't provide enough ledger history, the network's nominal close time
+	DefaultConfirmTime = 5 * time.Second
+	// confirmEstimateLedgers is how many recent ledgers are averaged
+	confirmEstimateLedgers = 10
+	// confirmEstimateTTL is how long an estimate is reused; ledger cadence
+	// rarely changes faster than this
+	confirmEstimateTTL = time.Minute
+)
+
+// confirmTimeCache holds the last EstimateConfirmTime result
+type confirmTimeCache struct {
+	mu       sync.Mutex
+	estimate time.Duration
+	at       time.Time
+}
+
+func (c *confirmTimeCache) get() (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.estimate == 0 || time.Since(c.at) > confirmEstimateTTL {
+		return 0, false
+	}
+	return c.estimate, true
+}
+
+func (c *confirmTimeCache) set(estimate time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.estimate = estimate
+	c.at = time.Now()
+}
+
+// EstimateConfirmTime estimates how long a submitted transaction takes to
+// confirm, as the average interval between the last few ledger closes, for
+// progress indicators such as "confirming, ~5s remaining". The estimate is
+// cached for a minute. If the RPC can't serve enough history, e.g. because
+// it doesn't support getLedgers, DefaultConfirmTime is returned with a nil
+// error; other RPC failures return DefaultConfirmTime with the error.
+func (c *Client) EstimateConfirmTime(ctx context.Context) (time.Duration, error) {
+	if estimate, ok := c.confirmEstimate.get(); ok {
+		return estimate, nil
+	}
+
+	latest, err := c.LatestLedgerSequence(ctx)
+	if err != nil {
+		return DefaultConfirmTime, err
+	}
+	if latest <= confirmEstimateLedgers {
+		return DefaultConfirmTime, nil
+	}
+
+	closeTimes, err := c.ledgerCloseTimes(ctx, latest-confirmEstimateLedgers+1, confirmEstimateLedgers)
+	if isMethodUnsupported(err) {
+		slog.Debug("RPC does not support getLedgers, using default confirmation estimate")
+		return DefaultConfirmTime, nil
+	}
+	if err != nil {
+		return DefaultConfirmTime, err
+	}
+
+	estimate, ok := averageInterval(closeTimes)
+	if !ok {
+		return DefaultConfirmTime, nil
+	}
+	c.confirmEstimate.set(estimate)
+	return estimate, nil
+}
+
+// ledgerCloseTimes reads the close times of up to limit ledgers from start
+func (c *Client) ledgerCloseTimes(ctx context.Context, start uint32, limit int) ([]time.Time, error) {
+	resp, err := c.Call(ctx, "getLedgers", map[string]interface{}{
+		"startLedger": start,
+		"pagination":  map[string]interface{}{"limit": limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Ledgers []struct {
+			Sequence  uint32 `json:"sequence"`
+			CloseTime string `json:"ledgerCloseTime"` // unix seconds
+		} `json:"ledgers"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+
+	times := make([]time.Time, 0, len(result.Ledgers))
+	for _, l := range result.Ledgers {
+		secs, err := strconv.ParseInt(l.CloseTime, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid close time %q for ledger %d: %w", l.CloseTime, l.Sequence, err)
+		}
+		times = append(times, time.Unix(secs, 0))
+	}
+	return times, nil
+}
+
+// averageInterval returns the mean gap between consecutive close times,
+// reporting false if there are too few to measure or they aren't increasing
+func averageInterval(closeTimes []time.Time) (time.Duration, bool) {
+	if len(closeTimes) < 2 {
+		return 0, false
+	}
+	span := closeTimes[len(closeTimes)-1].Sub(closeTimes[0])
+	if span <= 0 {
+		return 0, false
+	}
+	return span / time.Duration(len(closeTimes)-1), true
+}
@@ -0,0 +1,70 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ledgerHistoryServer answers getLatestLedger with ledger 100 and getLedgers
+// with ledgers closing interval seconds apart. A non-positive interval
+// leaves getLedgers unsupported.
+func ledgerHistoryServer(t *testing.T, interval int) *fakeRPC {
+	t.Helper()
+	srv := newFakeRPC(t)
+	srv.answer("getLatestLedger", `{"sequence":100}`)
+	if interval > 0 {
+		ledgers := make([]string, 0, confirmEstimateLedgers)
+		for i := 0; i < confirmEstimateLedgers; i++ {
+			ledgers = append(ledgers, fmt.Sprintf(`{"sequence":%d,"ledgerCloseTime":"%d"}`, 91+i, 1000+i*interval))
+		}
+		srv.answer("getLedgers", fmt.Sprintf(`{"ledgers":[%s],"latestLedger":100}`, strings.Join(ledgers, ",")))
+	}
+	return srv
+}
+
+func TestEstimateConfirmTime(t *testing.T) {
+	srv := ledgerHistoryServer(t, 6)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+
+	for i := 0; i < 2; i++ {
+		got, err := client.EstimateConfirmTime(context.Background())
+		if err != nil {
+			t.Fatalf("EstimateConfirmTime failed: %v", err)
+		}
+		if got != 6*time.Second {
+			t.Errorf("expected 6s, got %v", got)
+		}
+	}
+	if n := srv.callCount("getLedgers"); n != 1 {
+		t.Errorf("expected the estimate to be cached, got %d getLedgers calls", n)
+	}
+}
+
+func TestEstimateConfirmTime_FallsBackWithoutHistory(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: ledgerHistoryServer(t, 0).URL})
+
+	got, err := client.EstimateConfirmTime(context.Background())
+	if err != nil {
+		t.Fatalf("EstimateConfirmTime failed: %v", err)
+	}
+	if got != DefaultConfirmTime {
+		t.Errorf("expected the %v fallback, got %v", DefaultConfirmTime, got)
+	}
+}
+
+func TestAverageInterval(t *testing.T) {
+	base := time.Unix(1000, 0)
+	if _, ok := averageInterval([]time.Time{base}); ok {
+		t.Error("expected a single ledger to be too few to measure")
+	}
+	if _, ok := averageInterval([]time.Time{base, base}); ok {
+		t.Error("expected ledgers closing at the same second to be rejected")
+	}
+	got, ok := averageInterval([]time.Time{base, base.Add(4 * time.Second), base.Add(11 * time.Second)})
+	if !ok || got != 5500*time.Millisecond {
+		t.Errorf("expected 5.5s, got %v (%v)", got, ok)
+	}
+}
@@ -0,0 +1,31 @@
+package soroban
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultMaxConfirmationPollers is the default cap on in-flight confirmation
+// polls per client
+const DefaultMaxConfirmationPollers = 8
+
+// acquireConfirmSlot waits for a confirmation poll slot and returns the
+// function that frees it, or false if ctx is done or deadline passes first.
+// Clients built without NewClient have no cap.
+func (c *Client) acquireConfirmSlot(ctx context.Context, deadline time.Time) (func(), bool) {
+	if c.confirmSlots == nil {
+		return func() {}, true
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case c.confirmSlots <- struct{}{}:
+		return func() { <-c.confirmSlots }, true
+	case <-ctx.Done():
+		return nil, false
+	case <-timer.C:
+		return nil, false
+	}
+}
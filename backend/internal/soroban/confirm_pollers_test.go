@@ -0,0 +1,68 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForConfirmation_BoundsConcurrentPolls(t *testing.T) {
+	var inFlight, peak int32
+	srv := newFakeRPC(t)
+	srv.answerFunc("getTransaction", func(json.RawMessage) string {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return pendingTx
+	})
+
+	client, _ := NewClient(Config{RPCURL: srv.URL, MaxConfirmationPollers: 2})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = time.Millisecond
+	rc.ConfirmMaxAttempts = 3
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = tb.WaitForConfirmation(context.Background(), "abc123", time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 polls in flight, saw %d", peak)
+	}
+}
+
+func TestWaitForConfirmation_QueuedRespectsContext(t *testing.T) {
+	srv := newFakeRPC(t)
+	srv.answer("getTransaction", pendingTx)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL, MaxConfirmationPollers: 1})
+	client.confirmSlots <- struct{}{}
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = time.Millisecond
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tb.WaitForConfirmation(ctx, "abc123", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the queued poll to give up at the context deadline, got %v", err)
+	}
+	if n := srv.callCount("getTransaction"); n != 0 {
+		t.Errorf("expected no lookups without a free slot, got %d", n)
+	}
+}
@@ -0,0 +1,80 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConsistencyReport compares a bounty's escrow accounting with the token
+// balance the contract actually holds
+type ConsistencyReport struct {
+	BountyID uint64 `json:"bounty_id"`
+	Status   string `json:"status"`
+	// Locked is the escrow's remaining amount
+	Locked int64 `json:"locked"`
+	// Claimed is the sum of the bounty's pending claims
+	Claimed int64 `json:"claimed"`
+	// Balance is the contract's token balance
+	Balance int64 `json:"balance"`
+	// Required is the balance the escrow needs, Locked - Claimed
+	Required int64 `json:"required"`
+	// Consistent is true when Balance covers Required
+	Consistent bool `json:"consistent"`
+	// Discrepancy describes the shortfall when the check fails
+	Discrepancy string `json:"discrepancy,omitempty"`
+}
+
+// CheckEscrowConsistency verifies that the contract's token balance covers
+// what a bounty still has locked, net of its pending claims. It complements
+// the contract's own simulate-time check by reading storage and the token
+// directly, so it also catches token transfers that bypass the escrow.
+//
+// The balance is contract-wide, so a passing check doesn't rule out a
+// shortfall spread across several bounties.
+func (ec *EscrowContract) CheckEscrowConsistency(ctx context.Context, bountyID uint64) (*ConsistencyReport, error) {
+	states, err := ec.readEscrowStates(ctx, []uint64{bountyID})
+	if err != nil {
+		return nil, err
+	}
+	state := states[bountyID]
+	if !state.found {
+		return nil, fmt.Errorf("bounty %d not found", bountyID)
+	}
+
+	claims, err := ec.GetPendingClaims(ctx, bountyID)
+	if err != nil {
+		return nil, err
+	}
+	var claimed int64
+	for _, c := range claims {
+		claimed += c.Amount
+	}
+
+	token, err := ec.GetTokenConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := NewTokenContract(ec.client, ec.txBuilder, token.Address).Balance(ctx, ec.contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConsistencyReport(bountyID, state.status, state.remaining, claimed, balance), nil
+}
+
+func newConsistencyReport(bountyID uint64, status string, locked, claimed, balance int64) *ConsistencyReport {
+	r := &ConsistencyReport{
+		BountyID: bountyID,
+		Status:   status,
+		Locked:   locked,
+		Claimed:  claimed,
+		Balance:  balance,
+		Required: locked - claimed,
+	}
+	r.Consistent = r.Balance >= r.Required
+	if !r.Consistent {
+		r.Discrepancy = fmt.Sprintf("balance %d is %d short of locked %d minus claimed %d",
+			r.Balance, r.Required-r.Balance, r.Locked, r.Claimed)
+	}
+	return r
+}
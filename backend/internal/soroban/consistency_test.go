@@ -0,0 +1,32 @@
+package soroban
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewConsistencyReport(t *testing.T) {
+	r := newConsistencyReport(1, "Locked", 1000, 200, 800)
+	if !r.Consistent || r.Required != 800 || r.Discrepancy != "" {
+		t.Errorf("expected a balance equal to the requirement to pass, got %+v", r)
+	}
+
+	r = newConsistencyReport(1, "Locked", 1000, 200, 750)
+	if r.Consistent {
+		t.Fatal("expected a short balance to fail")
+	}
+	if !strings.Contains(r.Discrepancy, "50 short") {
+		t.Errorf("expected the shortfall in the discrepancy, got %q", r.Discrepancy)
+	}
+}
+
+func TestCheckEscrowConsistency_NotFound(t *testing.T) {
+	srv := rpcServer(t, `{"entries":[],"latestLedger":100}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	if _, err := ec.CheckEscrowConsistency(context.Background(), 7); err == nil {
+		t.Error("expected an error for a missing bounty")
+	}
+}
@@ -0,0 +1,143 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Contract is a deployed contract that host functions can be invoked on by
+// name, for tooling that doesn't need to know the concrete client type.
+// Function names go through the client's FunctionNames remapping.
+type Contract interface {
+	// Address returns the contract's address
+	Address() string
+	// Invoke submits a call to fn and waits for its confirmation
+	Invoke(ctx context.Context, fn string, args []xdr.ScVal) (*TransactionResult, error)
+	// Simulate simulates a call to fn without submitting it
+	Simulate(ctx context.Context, fn string, args []xdr.ScVal) (*SimResult, error)
+	// InvokeMulti submits several calls and returns one result per call
+	InvokeMulti(ctx context.Context, calls []InvokeCall) ([]*TransactionResult, error)
+}
+
+// InvokeCall is one host function call for InvokeMulti
+type InvokeCall struct {
+	Function string
+	Args     []xdr.ScVal
+}
+
+var (
+	_ Contract = (*EscrowContract)(nil)
+	_ Contract = (*ProgramEscrowContract)(nil)
+)
+
+// Address returns the escrow contract's address
+func (ec *EscrowContract) Address() string {
+	return ec.contractAddress
+}
+
+// Invoke submits a call to an escrow host function
+func (ec *EscrowContract) Invoke(ctx context.Context, fn string, args []xdr.ScVal) (*TransactionResult, error) {
+	return invokeContract(ctx, ec.client, ec.txBuilder, ec.contractAddress, hostFunction(ec.FunctionNames, fn), args)
+}
+
+// Simulate simulates a call to an escrow host function
+func (ec *EscrowContract) Simulate(ctx context.Context, fn string, args []xdr.ScVal) (*SimResult, error) {
+	return simulateContract(ctx, ec.txBuilder, ec.contractAddress, hostFunction(ec.FunctionNames, fn), args)
+}
+
+// InvokeMulti submits calls to escrow host functions. See invokeMulti.
+func (ec *EscrowContract) InvokeMulti(ctx context.Context, calls []InvokeCall) ([]*TransactionResult, error) {
+	return invokeMulti(ctx, ec, calls)
+}
+
+// Address returns the program escrow contract's address
+func (pec *ProgramEscrowContract) Address() string {
+	return pec.contractAddress
+}
+
+// Invoke submits a call to a program escrow host function
+func (pec *ProgramEscrowContract) Invoke(ctx context.Context, fn string, args []xdr.ScVal) (*TransactionResult, error) {
+	return invokeContract(ctx, pec.client, pec.txBuilder, pec.contractAddress, hostFunction(pec.FunctionNames, fn), args)
+}
+
+// Simulate simulates a call to a program escrow host function
+func (pec *ProgramEscrowContract) Simulate(ctx context.Context, fn string, args []xdr.ScVal) (*SimResult, error) {
+	return simulateContract(ctx, pec.txBuilder, pec.contractAddress, hostFunction(pec.FunctionNames, fn), args)
+}
+
+// InvokeMulti submits calls to program escrow host functions. See invokeMulti.
+func (pec *ProgramEscrowContract) InvokeMulti(ctx context.Context, calls []InvokeCall) ([]*TransactionResult, error) {
+	return invokeMulti(ctx, pec, calls)
+}
+
+// invokeMulti submits calls in order. Soroban requires a transaction with an
+// invoke host function operation to carry no other operation, so the calls
+// can't share a transaction: each is submitted on its own and they are not
+// atomic. On failure the results of the calls already made are returned with
+// the error.
+func invokeMulti(ctx context.Context, c Contract, calls []InvokeCall) ([]*TransactionResult, error) {
+	results := make([]*TransactionResult, 0, len(calls))
+	for i, call := range calls {
+		result, err := c.Invoke(ctx, call.Function, call.Args)
+		if err != nil {
+			return results, fmt.Errorf("call %d (%s) failed: %w", i, call.Function, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// invokeContract builds, submits and confirms a single host function call
+func invokeContract(ctx context.Context, client *Client, txBuilder *TransactionBuilder, address, fn string, args []xdr.ScVal) (*TransactionResult, error) {
+	client.LogContractInteraction(address, fn, map[string]interface{}{"args": len(args)})
+
+	op, err := buildContractOp(address, fn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	confirmed, err := txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
+	if err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+		return result, nil
+	}
+	return confirmed, nil
+}
+
+// simulateContract simulates a single host function call
+func simulateContract(ctx context.Context, txBuilder *TransactionBuilder, address, fn string, args []xdr.ScVal) (*SimResult, error) {
+	op, err := buildContractOp(address, fn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate %s: %w", fn, err)
+	}
+	return sim, nil
+}
+
+func buildContractOp(address, fn string, args []xdr.ScVal) (txnbuild.Operation, error) {
+	contractAddr, err := EncodeContractAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+	if args == nil {
+		args = []xdr.ScVal{}
+	}
+	op, err := BuildInvokeHostFunctionOp(contractAddr, fn, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build operation: %w", err)
+	}
+	return op, nil
+}
@@ -0,0 +1,82 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestContract_SimulateRemapsFunction(t *testing.T) {
+	void, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	var called string
+	srv := newFakeRPC(t)
+	srv.answerFunc("simulateTransaction", func(params json.RawMessage) string {
+		var p struct {
+			Transaction string `json:"transaction"`
+		}
+		_ = json.Unmarshal(params, &p)
+		var env xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(p.Transaction, &env); err == nil {
+			fn := env.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.FunctionName
+			called = string(fn)
+		}
+		return fmt.Sprintf(`{"latestLedger":5,"results":[{"xdr":%q}]}`, void)
+	})
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	tb.account.store(&txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey(), Sequence: 1})
+	ec, _ := NewEscrowContract(client, tb, testContractHex)
+	ec.FunctionNames = map[string]string{"get_balance": "get_balance_v2"}
+
+	var c Contract = ec
+	if c.Address() != testContractHex {
+		t.Errorf("expected address %s, got %s", testContractHex, c.Address())
+	}
+	sim, err := c.Simulate(context.Background(), "get_balance", nil)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.LatestLedger != 5 {
+		t.Errorf("expected latest ledger 5, got %d", sim.LatestLedger)
+	}
+	if called != "get_balance_v2" {
+		t.Errorf("expected the remapped function to be called, got %q", called)
+	}
+}
+
+// fakeContract records Invoke calls and fails the one named failOn
+type fakeContract struct {
+	Contract
+	invoked []string
+	failOn  string
+}
+
+func (f *fakeContract) Invoke(ctx context.Context, fn string, args []xdr.ScVal) (*TransactionResult, error) {
+	if fn == f.failOn {
+		return nil, fmt.Errorf("boom")
+	}
+	f.invoked = append(f.invoked, fn)
+	return &TransactionResult{Hash: fn}, nil
+}
+
+func TestInvokeMulti_OneTransactionPerCall(t *testing.T) {
+	f := &fakeContract{failOn: "refund"}
+	calls := []InvokeCall{{Function: "lock_funds"}, {Function: "set_flag"}, {Function: "refund"}, {Function: "never"}}
+
+	results, err := invokeMulti(context.Background(), f, calls)
+	if err == nil {
+		t.Fatal("expected the failing call to stop the batch")
+	}
+	if len(results) != 2 || results[0].Hash != "lock_funds" || results[1].Hash != "set_flag" {
+		t.Errorf("expected the results of the calls before the failure, got %+v", results)
+	}
+	if len(f.invoked) != 2 {
+		t.Errorf("expected no calls after the failure, got %v", f.invoked)
+	}
+}
@@ -0,0 +1,80 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// maxOperationsPerTransaction is the protocol limit on classic operations in
+// one transaction. Soroban invocations always travel alone.
+const maxOperationsPerTransaction = 100
+
+// CostEstimate is the projected fee of submitting a set of operations. All
+// amounts are in stroops.
+type CostEstimate struct {
+	// Transactions is how many transactions the operations are split into
+	Transactions int   `json:"transactions"`
+	ResourceFee  int64 `json:"resource_fee"`
+	InclusionFee int64 `json:"inclusion_fee"`
+	Total        int64 `json:"total"`
+	// TotalXLM is Total formatted with XLMFormatter
+	TotalXLM string `json:"total_xlm"`
+}
+
+// EstimateCost simulates the operations and returns the fees they would cost,
+// summed across the transactions they are chunked into. Nothing is signed or
+// submitted. Each contract invocation needs its own transaction and pays the
+// simulated resource fee; other operations are grouped up to the protocol
+// limit. Every transaction pays the base fee per operation.
+func (tb *TransactionBuilder) EstimateCost(ctx context.Context, ops []txnbuild.Operation) (CostEstimate, error) {
+	var estimate CostEstimate
+	if len(ops) == 0 {
+		return estimate, fmt.Errorf("operations list cannot be empty")
+	}
+
+	for i, chunk := range chunkOperations(ops) {
+		estimate.Transactions++
+		estimate.InclusionFee += int64(len(chunk)) * txnbuild.MinBaseFee
+
+		if _, ok := chunk[0].(*txnbuild.InvokeHostFunction); !ok {
+			continue
+		}
+		sim, err := tb.simulatePreview(ctx, chunk)
+		if err != nil {
+			return CostEstimate{}, fmt.Errorf("failed to simulate transaction %d: %w", i, err)
+		}
+		estimate.ResourceFee += sim.MinResourceFee
+	}
+
+	estimate.Total = estimate.ResourceFee + estimate.InclusionFee
+	estimate.TotalXLM = XLMFormatter.Format(estimate.Total)
+	return estimate, nil
+}
+
+// chunkOperations splits ops into transactions: each host function invocation
+// on its own, and runs of classic operations up to maxOperationsPerTransaction
+func chunkOperations(ops []txnbuild.Operation) [][]txnbuild.Operation {
+	var chunks [][]txnbuild.Operation
+	var classic []txnbuild.Operation
+	for _, op := range ops {
+		if _, ok := op.(*txnbuild.InvokeHostFunction); ok {
+			if len(classic) > 0 {
+				chunks = append(chunks, classic)
+				classic = nil
+			}
+			chunks = append(chunks, []txnbuild.Operation{op})
+			continue
+		}
+		classic = append(classic, op)
+		if len(classic) == maxOperationsPerTransaction {
+			chunks = append(chunks, classic)
+			classic = nil
+		}
+	}
+	if len(classic) > 0 {
+		chunks = append(chunks, classic)
+	}
+	return chunks
+}
@@ -0,0 +1,61 @@
+package soroban
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestEstimateCost_SumsChunks(t *testing.T) {
+	var sims int32
+	srv := rpcServer(t, `{"minResourceFee":"50000","latestLedger":10}`, map[string]*int32{
+		"simulateTransaction": &sims,
+	})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+
+	contractAddr, _ := EncodeContractAddress(testContractHex)
+	invoke, err := BuildInvokeHostFunctionOp(contractAddr, "batch_payout", nil)
+	if err != nil {
+		t.Fatalf("failed to build operation: %v", err)
+	}
+	ops := []txnbuild.Operation{invoke, invoke}
+	for i := 0; i < maxOperationsPerTransaction+1; i++ {
+		ops = append(ops, &txnbuild.BumpSequence{BumpTo: 1})
+	}
+
+	estimate, err := tb.EstimateCost(context.Background(), ops)
+	if err != nil {
+		t.Fatalf("EstimateCost failed: %v", err)
+	}
+
+	if estimate.Transactions != 4 {
+		t.Errorf("expected 2 invoke + 2 classic transactions, got %d", estimate.Transactions)
+	}
+	if sims != 2 {
+		t.Errorf("expected only invoke transactions to be simulated, got %d", sims)
+	}
+	if estimate.ResourceFee != 100000 {
+		t.Errorf("expected resource fee 100000, got %d", estimate.ResourceFee)
+	}
+	wantInclusion := int64(len(ops)) * txnbuild.MinBaseFee
+	if estimate.InclusionFee != wantInclusion {
+		t.Errorf("expected inclusion fee %d, got %d", wantInclusion, estimate.InclusionFee)
+	}
+	if estimate.Total != estimate.ResourceFee+estimate.InclusionFee {
+		t.Errorf("expected total to be the sum of fees, got %d", estimate.Total)
+	}
+	if estimate.TotalXLM != "0.01103 XLM" {
+		t.Errorf("unexpected formatted total %q", estimate.TotalXLM)
+	}
+}
+
+func TestEstimateCost_Empty(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://localhost"})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	if _, err := tb.EstimateCost(context.Background(), nil); err == nil {
+		t.Error("expected an error for an empty operations list")
+	}
+}
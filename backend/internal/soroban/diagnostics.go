@@ -0,0 +1,117 @@
+package soroban
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// DiagnosticEvent is a decoded Soroban diagnostic event. Topics and Data are
+// rendered as strings so the event can be logged or attached to an error.
+type DiagnosticEvent struct {
+	ContractID string   `json:"contract_id,omitempty"`
+	Topics     []string `json:"topics"`
+	Data       string   `json:"data"`
+	// InSuccessfulCall is false for events emitted by the call that failed
+	InSuccessfulCall bool `json:"in_successful_call"`
+}
+
+// String renders the event as "<contract> <topics...>: <data>"
+func (e DiagnosticEvent) String() string {
+	var sb strings.Builder
+	if e.ContractID != "" {
+		sb.WriteString(e.ContractID)
+		sb.WriteString(" ")
+	}
+	sb.WriteString(strings.Join(e.Topics, " "))
+	if e.Data != "" {
+		sb.WriteString(": ")
+		sb.WriteString(e.Data)
+	}
+	return sb.String()
+}
+
+// ExtractDiagnostics decodes the diagnostic events carried by an RPC result.
+// sendTransaction and getTransaction report them as diagnosticEventsXdr,
+// simulateTransaction as events; both hold base64 DiagnosticEvent XDR.
+func ExtractDiagnostics(result map[string]interface{}) ([]DiagnosticEvent, error) {
+	var events []DiagnosticEvent
+	for _, field := range []string{"diagnosticEventsXdr", "events"} {
+		raw, _ := result[field].([]interface{})
+		for i, r := range raw {
+			eventXDR, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry %d", field, i)
+			}
+
+			var ev xdr.DiagnosticEvent
+			if err := xdr.SafeUnmarshalBase64(eventXDR, &ev); err != nil {
+				return nil, fmt.Errorf("failed to decode %s entry %d: %w", field, i, err)
+			}
+			events = append(events, decodeDiagnosticEvent(ev))
+		}
+	}
+	return events, nil
+}
+
+func decodeDiagnosticEvent(ev xdr.DiagnosticEvent) DiagnosticEvent {
+	out := DiagnosticEvent{InSuccessfulCall: ev.InSuccessfulContractCall}
+
+	if id := ev.Event.ContractId; id != nil {
+		if addr, err := strkey.Encode(strkey.VersionByteContract, id[:]); err == nil {
+			out.ContractID = addr
+		}
+	}
+
+	if body, ok := ev.Event.Body.GetV0(); ok {
+		out.Topics = make([]string, len(body.Topics))
+		for i, topic := range body.Topics {
+			out.Topics[i] = topic.String()
+		}
+		out.Data = body.Data.String()
+	}
+
+	return out
+}
+
+// topDiagnostic picks the event most likely to explain a failure: the last
+// "error" event raised by the failing call, falling back to the last event.
+func topDiagnostic(events []DiagnosticEvent) (DiagnosticEvent, bool) {
+	if len(events) == 0 {
+		return DiagnosticEvent{}, false
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if !ev.InSuccessfulCall && len(ev.Topics) > 0 && ev.Topics[0] == "error" {
+			return ev, true
+		}
+	}
+	return events[len(events)-1], true
+}
+
+// missingFunctionMarkers identify a call to a function the contract doesn't
+// export: the host reports Error(WasmVm, MissingValue) with a diagnostic
+// naming the missing function
+var missingFunctionMarkers = []string{
+	"non-existent contract function",
+	"Error(WasmVm, MissingValue)",
+}
+
+// isMissingFunction reports whether a simulation failed because the invoked
+// function isn't implemented by the contract
+func isMissingFunction(simErr string, events []DiagnosticEvent) bool {
+	texts := []string{simErr}
+	for _, ev := range events {
+		texts = append(texts, ev.Data)
+	}
+	for _, text := range texts {
+		for _, marker := range missingFunctionMarkers {
+			if strings.Contains(text, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package soroban
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// failingCallEvents mirrors the events emitted by a lock_funds call that
+// reverted with Error(Contract, #1): the fn_call trace followed by the error.
+func failingCallEvents(t *testing.T) []interface{} {
+	t.Helper()
+
+	contractID := xdr.ContractId{1}
+	sym := func(s string) xdr.ScVal {
+		v, _ := EncodeScValSymbol(s)
+		return v
+	}
+	code := xdr.Uint32(1)
+	contractErr := xdr.ScVal{
+		Type:  xdr.ScValTypeScvError,
+		Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code},
+	}
+	msg := xdr.ScString("DeadlineNotReached")
+
+	events := []xdr.DiagnosticEvent{
+		{
+			InSuccessfulContractCall: false,
+			Event: xdr.ContractEvent{
+				Type: xdr.ContractEventTypeDiagnostic,
+				Body: xdr.ContractEventBody{V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{sym("fn_call"), sym("lock_funds")},
+					Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+				}},
+			},
+		},
+		{
+			InSuccessfulContractCall: false,
+			Event: xdr.ContractEvent{
+				ContractId: &contractID,
+				Type:       xdr.ContractEventTypeDiagnostic,
+				Body: xdr.ContractEventBody{V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{sym("error"), contractErr},
+					Data:   xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &msg},
+				}},
+			},
+		},
+	}
+
+	raw := make([]interface{}, len(events))
+	for i, ev := range events {
+		b64, err := xdr.MarshalBase64(ev)
+		if err != nil {
+			t.Fatalf("failed to encode event %d: %v", i, err)
+		}
+		raw[i] = b64
+	}
+	return raw
+}
+
+func TestExtractDiagnostics(t *testing.T) {
+	events, err := ExtractDiagnostics(map[string]interface{}{
+		"diagnosticEventsXdr": failingCallEvents(t),
+	})
+	if err != nil {
+		t.Fatalf("ExtractDiagnostics failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	top, ok := topDiagnostic(events)
+	if !ok {
+		t.Fatal("expected a top diagnostic")
+	}
+	if !strings.HasPrefix(top.ContractID, "C") {
+		t.Errorf("expected contract strkey, got %q", top.ContractID)
+	}
+	if len(top.Topics) != 2 || top.Topics[0] != "error" {
+		t.Errorf("unexpected topics %v", top.Topics)
+	}
+	if top.Data != "DeadlineNotReached" {
+		t.Errorf("expected revert message, got %q", top.Data)
+	}
+}
+
+func TestExtractDiagnostics_InvalidXDR(t *testing.T) {
+	if _, err := ExtractDiagnostics(map[string]interface{}{"events": []interface{}{"not-xdr"}}); err == nil {
+		t.Error("expected error for invalid event XDR")
+	}
+}
+
+func TestParseSimResult_ErrorIncludesDiagnostic(t *testing.T) {
+	raw := map[string]interface{}{
+		"error":  "HostError: Error(Contract, #1)",
+		"events": failingCallEvents(t),
+	}
+	_, err := parseSimResult(raw, DefaultMaxReturnBytes)
+	if err == nil || !strings.Contains(err.Error(), "DeadlineNotReached") {
+		t.Errorf("expected error to carry the revert reason, got %v", err)
+	}
+}
+
+func TestParseSimResult_MissingFunction(t *testing.T) {
+	raw := map[string]interface{}{
+		"error": "HostError: Error(WasmVm, MissingValue)\n\nEvent log (newest first):\n   0: [Diagnostic Event] topics:[error, Error(WasmVm, MissingValue)], data:[\"trying to invoke non-existent contract function\", get_upgrade_safety_status]",
+	}
+	_, err := parseSimResult(raw, DefaultMaxReturnBytes)
+	if !errors.Is(err, ErrFunctionNotImplemented) {
+		t.Errorf("expected ErrFunctionNotImplemented, got %v", err)
+	}
+
+	// Contract panics are genuine failures, not missing functions
+	raw = map[string]interface{}{"error": "HostError: Error(Contract, #1)", "events": failingCallEvents(t)}
+	if _, err := parseSimResult(raw, DefaultMaxReturnBytes); errors.Is(err, ErrFunctionNotImplemented) {
+		t.Errorf("expected a contract error not to be reported as missing, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package soroban
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultEndpointEjectAfter is the number of consecutive connection-level
+	// failures after which an endpoint is temporarily ejected
+	defaultEndpointEjectAfter = 3
+
+	// defaultEndpointEjectFor is how long an ejected endpoint is skipped
+	defaultEndpointEjectFor = 30 * time.Second
+)
+
+// EndpointHealth is a point-in-time view of a single RPC endpoint
+type EndpointHealth struct {
+	URL                 string    `json:"url"`
+	Healthy             bool      `json:"healthy"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	TotalFailures       uint64    `json:"total_failures"`
+	TotalSuccesses      uint64    `json:"total_successes"`
+	EjectedUntil        time.Time `json:"ejected_until,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// endpointError marks a connection-level failure (transport error, 5xx or
+// 429) after which the request may be tried against another endpoint
+type endpointError struct {
+	err        error
+	status     int           // HTTP status, or 0 for a transport error
+	retryAfter time.Duration // Retry-After of a 429 response, if any
+}
+
+func (e *endpointError) Error() string { return e.err.Error() }
+func (e *endpointError) Unwrap() error { return e.err }
+
+// isEndpointFailure reports whether err is a connection-level failure
+func isEndpointFailure(err error) bool {
+	var epErr *endpointError
+	return errors.As(err, &epErr)
+}
+
+// isDialError reports whether err happened before the request was sent, in
+// which case a submission can safely be retried on another endpoint
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// notSubmitted reports whether a failed submission can't have been
+// processed: it was never sent, or the endpoint rate limited it
+func notSubmitted(err error) bool {
+	var epErr *endpointError
+	return isDialError(err) || errors.As(err, &epErr) && epErr.status == http.StatusTooManyRequests
+}
+
+type endpointState struct {
+	url                 string
+	consecutiveFailures int
+	totalFailures       uint64
+	totalSuccesses      uint64
+	ejectedUntil        time.Time
+	lastError           string
+}
+
+// endpointPool tracks the health of the configured RPC endpoints
+type endpointPool struct {
+	mu         sync.Mutex
+	endpoints  []*endpointState
+	ejectAfter int
+	ejectFor   time.Duration
+	now        func() time.Time
+}
+
+func newEndpointPool(urls []string, ejectAfter int, ejectFor time.Duration) *endpointPool {
+	if ejectAfter <= 0 {
+		ejectAfter = defaultEndpointEjectAfter
+	}
+	if ejectFor <= 0 {
+		ejectFor = defaultEndpointEjectFor
+	}
+
+	p := &endpointPool{ejectAfter: ejectAfter, ejectFor: ejectFor, now: time.Now}
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		p.endpoints = append(p.endpoints, &endpointState{url: u})
+	}
+	return p
+}
+
+// order returns endpoint URLs to try: healthy endpoints in configured order,
+// then ejected ones by soonest re-admission so a request is never refused
+// outright when every endpoint is ejected
+func (p *endpointPool) order() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var healthy []string
+	var ejected []*endpointState
+	for _, ep := range p.endpoints {
+		if now.Before(ep.ejectedUntil) {
+			ejected = append(ejected, ep)
+		} else {
+			healthy = append(healthy, ep.url)
+		}
+	}
+	sort.SliceStable(ejected, func(i, j int) bool {
+		return ejected[i].ejectedUntil.Before(ejected[j].ejectedUntil)
+	})
+	for _, ep := range ejected {
+		healthy = append(healthy, ep.url)
+	}
+	return healthy
+}
+
+func (p *endpointPool) find(url string) *endpointState {
+	for _, ep := range p.endpoints {
+		if ep.url == url {
+			return ep
+		}
+	}
+	return nil
+}
+
+func (p *endpointPool) recordSuccess(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ep := p.find(url); ep != nil {
+		ep.totalSuccesses++
+		ep.consecutiveFailures = 0
+		ep.ejectedUntil = time.Time{}
+	}
+}
+
+func (p *endpointPool) recordFailure(url string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ep := p.find(url); ep != nil {
+		ep.totalFailures++
+		ep.consecutiveFailures++
+		ep.lastError = err.Error()
+		if ep.consecutiveFailures >= p.ejectAfter {
+			ep.ejectedUntil = p.now().Add(p.ejectFor)
+		}
+	}
+}
+
+func (p *endpointPool) health() []EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	out := make([]EndpointHealth, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		out[i] = EndpointHealth{
+			URL:                 ep.url,
+			Healthy:             !now.Before(ep.ejectedUntil),
+			ConsecutiveFailures: ep.consecutiveFailures,
+			TotalFailures:       ep.totalFailures,
+			TotalSuccesses:      ep.totalSuccesses,
+			LastError:           ep.lastError,
+		}
+		if now.Before(ep.ejectedUntil) {
+			out[i].EjectedUntil = ep.ejectedUntil
+		}
+	}
+	return out
+}
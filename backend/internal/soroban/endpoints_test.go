@@ -0,0 +1,267 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+// rpcServer answers every JSON-RPC call with result and counts calls to the
+// methods in calls
+func rpcServer(t *testing.T, result string, calls map[string]*int32) *httptest.Server {
+	t.Helper()
+	f := newFakeRPC(t)
+	f.answerAll(result)
+	for method, n := range calls {
+		f.answerFunc(method, func(json.RawMessage) string {
+			atomic.AddInt32(n, 1)
+			return result
+		})
+	}
+	return f.Server
+}
+
+func failingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCall_FailsOverOnServerError(t *testing.T) {
+	bad := failingServer(t)
+	good := rpcServer(t, `{"sequence":42}`, nil)
+
+	client, err := NewClient(Config{RPCURL: bad.URL, FallbackRPCURLs: []string{good.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if _, err := client.GetLatestLedger(context.Background()); err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+
+	health := client.EndpointHealth()
+	if len(health) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(health))
+	}
+	if health[0].TotalFailures != 1 || health[0].LastError == "" {
+		t.Errorf("expected primary failure to be recorded, got %+v", health[0])
+	}
+	if health[1].TotalSuccesses != 1 {
+		t.Errorf("expected fallback success to be recorded, got %+v", health[1])
+	}
+}
+
+func TestCall_CancellationLeavesEndpointHealthy(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+	var fallbackCalls int32
+	fallback := rpcServer(t, `{"sequence":42}`, map[string]*int32{"sendTransaction": &fallbackCalls, "getTransaction": &fallbackCalls})
+
+	client, err := NewClient(Config{RPCURL: slow.URL, FallbackRPCURLs: []string{fallback.URL}, EndpointEjectAfter: 1})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetLatestLedger(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendTransaction(ctx, "AAAA"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline from sendTransaction, got %v", err)
+	}
+	if n := atomic.LoadInt32(&fallbackCalls); n != 0 {
+		t.Errorf("expected a canceled submission not to be looked up elsewhere, got %d calls", n)
+	}
+
+	primary := client.EndpointHealth()[0]
+	if !primary.Healthy || primary.TotalFailures != 0 {
+		t.Errorf("expected cancellation not to count against the endpoint, got %+v", primary)
+	}
+}
+
+func TestEndpointPool_EjectsAndReadmits(t *testing.T) {
+	now := time.Now()
+	pool := newEndpointPool([]string{"a", "b"}, 2, time.Minute)
+	pool.now = func() time.Time { return now }
+
+	pool.recordFailure("a", errors.New("boom"))
+	if order := pool.order(); order[0] != "a" {
+		t.Fatalf("expected a to stay first below the threshold, got %v", order)
+	}
+
+	pool.recordFailure("a", errors.New("boom"))
+	if order := pool.order(); order[0] != "b" || order[1] != "a" {
+		t.Fatalf("expected ejected a to move last, got %v", order)
+	}
+	if pool.health()[0].Healthy {
+		t.Error("expected a to be reported unhealthy")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if order := pool.order(); order[0] != "a" {
+		t.Errorf("expected a to be re-admitted after the ejection period, got %v", order)
+	}
+}
+
+func TestSendTransaction_AmbiguousSubmitQueriesByHash(t *testing.T) {
+	// The primary drops the connection after reading the request, so the
+	// submission may or may not have reached the network.
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(primary.Close)
+
+	var sends, lookups int32
+	fallback := rpcServer(t, `{"status":"SUCCESS"}`, map[string]*int32{
+		"sendTransaction": &sends,
+		"getTransaction":  &lookups,
+	})
+
+	client, err := NewClient(Config{RPCURL: primary.URL, FallbackRPCURLs: []string{fallback.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	envelope, wantHash := testEnvelope(t)
+	hash, err := client.SendTransaction(context.Background(), envelope)
+	if err != nil {
+		t.Fatalf("expected the transaction to be found on the fallback, got %v", err)
+	}
+	if hash != wantHash {
+		t.Errorf("expected hash %s, got %s", wantHash, hash)
+	}
+	if sends != 0 {
+		t.Errorf("expected no resubmission, got %d sendTransaction calls", sends)
+	}
+	if lookups != 1 {
+		t.Errorf("expected 1 getTransaction lookup, got %d", lookups)
+	}
+}
+
+func TestSendTransaction_AmbiguousSubmitNotFound(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(primary.Close)
+	fallback := rpcServer(t, `{"status":"NOT_FOUND"}`, nil)
+
+	client, _ := NewClient(Config{RPCURL: primary.URL, FallbackRPCURLs: []string{fallback.URL}})
+
+	envelope, wantHash := testEnvelope(t)
+	hash, err := client.SendTransaction(context.Background(), envelope)
+	if !errors.Is(err, ErrSubmissionUnknown) {
+		t.Fatalf("expected ErrSubmissionUnknown, got %v", err)
+	}
+	if hash != wantHash {
+		t.Errorf("expected the hash to be returned for polling, got %q", hash)
+	}
+}
+
+func TestBuildAndSubmit_FailsOverToFallback(t *testing.T) {
+	// Nothing listens on the primary, so the submission never left the client
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	fallback := newFakeRPC(t)
+	fallback.answer("sendTransaction", sentTx)
+
+	client, _ := NewClient(Config{RPCURL: down.URL, FallbackRPCURLs: []string{fallback.URL}})
+	kp := keypair.MustRandom()
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	tb.AutoAuth = false
+	tb.account.prewarm(&txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1})
+
+	result, err := tb.BuildAndSubmit(context.Background(), []txnbuild.Operation{&txnbuild.BumpSequence{}})
+	if err != nil {
+		t.Fatalf("expected the fallback to take the submission, got %v", err)
+	}
+	if result.Hash != "abc123" {
+		t.Errorf("expected the fallback's hash, got %q", result.Hash)
+	}
+	if n := fallback.callCount("sendTransaction"); n != 1 {
+		t.Errorf("expected 1 submission to the fallback, got %d", n)
+	}
+}
+
+func TestBuildAndSubmit_AmbiguousSubmitNotResent(t *testing.T) {
+	var sends int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sends, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(primary.Close)
+	fallback := newFakeRPC(t)
+	fallback.answer("sendTransaction", sentTx)
+	fallback.answer("getTransaction", pendingTx)
+
+	client, _ := NewClient(Config{RPCURL: primary.URL, FallbackRPCURLs: []string{fallback.URL}})
+	kp := keypair.MustRandom()
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	tb.AutoAuth = false
+	tb.account.prewarm(&txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1})
+
+	result, err := tb.BuildAndSubmit(context.Background(), []txnbuild.Operation{&txnbuild.BumpSequence{}})
+	if err != nil {
+		t.Fatalf("expected an unknown outcome to be left to confirmation, got %v", err)
+	}
+	if result.Hash == "" || result.Hash == "abc123" {
+		t.Errorf("expected the envelope's own hash to poll for, got %q", result.Hash)
+	}
+	if n := atomic.LoadInt32(&sends) + int32(fallback.callCount("sendTransaction")); n != 1 {
+		t.Errorf("expected the transaction to be sent once, got %d submissions", n)
+	}
+	if n := fallback.callCount("getTransaction"); n != 1 {
+		t.Errorf("expected 1 lookup on the fallback, got %d", n)
+	}
+}
+
+func testEnvelope(t *testing.T) (string, string) {
+	t.Helper()
+	kp := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	envelope, _ := tx.Base64()
+	hash, _ := tx.HashHex(network.TestNetworkPassphrase)
+	return envelope, hash
+}
@@ -0,0 +1,170 @@
+package soroban
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Sentinel errors returned by contract clients. Callers should match them with
+// errors.Is since they are usually wrapped with additional context.
+var (
+	// ErrNoStuckLock is returned when clearing a reentrancy lock that is not held
+	ErrNoStuckLock = errors.New("reentrancy lock is not held")
+
+	// ErrLockNotStuck is returned when clearing a reentrancy lock that has not
+	// been held long enough to be considered stuck
+	ErrLockNotStuck = errors.New("reentrancy lock has not been held long enough to be considered stuck")
+
+	// ErrResultTooLarge is returned when a simulated return value exceeds the
+	// client's MaxReturnBytes; callers should use a paginated method instead
+	ErrResultTooLarge = errors.New("contract return value exceeds size limit")
+
+	// ErrWasmHashNotAllowed is returned when an upgrade targets a WASM hash
+	// missing from the configured allowlist
+	ErrWasmHashNotAllowed = errors.New("wasm hash is not in the upgrade allowlist")
+
+	// ErrSubmissionUnknown is returned when a submission failed mid-request and
+	// the transaction could not be found on any other endpoint. It may still
+	// land, so callers should poll by hash rather than resubmit.
+	ErrSubmissionUnknown = errors.New("transaction submission outcome unknown")
+
+	// ErrWasmHashMismatch is returned when WASM bytes don't match the hash they
+	// were expected to have
+	ErrWasmHashMismatch = errors.New("wasm hash does not match expected hash")
+
+	// ErrSourceAccountNotFound is returned when a transaction builder's source
+	// account does not exist on the network
+	ErrSourceAccountNotFound = errors.New("source account not found")
+
+	// ErrTxExpired is returned when a transaction's time bound lapsed before it
+	// was accepted; callers should rebuild it rather than resubmit
+	ErrTxExpired = errors.New("transaction time bound expired")
+
+	// ErrWrongNetwork is returned when a privileged operation is attempted on a
+	// client connected to a different network than the one required
+	ErrWrongNetwork = errors.New("client is connected to the wrong network")
+
+	// ErrPayoutNotReflected is returned when a recipient's balance did not
+	// change by the amount paid out
+	ErrPayoutNotReflected = errors.New("payout not reflected in recipient balance")
+
+	// ErrFunctionNotImplemented is returned when a simulated call targets a
+	// function the contract doesn't export, e.g. on an older contract version
+	ErrFunctionNotImplemented = errors.New("contract function not implemented")
+
+	// ErrConfirmationTimeout is returned when a transaction wasn't confirmed
+	// within the configured polling attempts; see ConfirmationTimeoutError
+	ErrConfirmationTimeout = errors.New("timed out waiting for transaction confirmation")
+	// ErrVersionUnknown is returned when a contract or WASM doesn't declare a
+	// version
+	ErrVersionUnknown = errors.New("contract version unknown")
+	// ErrIncompatibleVersion is returned when an upgrade falls outside the
+	// configured version policy
+	ErrIncompatibleVersion = errors.New("incompatible contract version")
+
+	// ErrLedgerUnavailable is returned when the RPC can't serve state at the
+	// requested ledger, e.g. because it only keeps the latest state
+	ErrLedgerUnavailable = errors.New("ledger state unavailable")
+
+	// ErrInvalidWasm is returned when a WASM artifact fails validation before
+	// upload, e.g. because it is truncated or the wrong file
+	ErrInvalidWasm = errors.New("invalid wasm")
+
+	// ErrRefundExceedsLocked is returned when a partial refund asks for more
+	// than the escrow still holds
+	ErrRefundExceedsLocked = errors.New("refund exceeds locked amount")
+
+	// ErrRetryBudgetExceeded is returned when a context's RetryBudget runs out
+	// part way through submitting or confirming a transaction
+	ErrRetryBudgetExceeded = errors.New("retry budget exceeded")
+
+	// ErrFunctionNotAllowed is returned when a TransactionBuilder with
+	// AllowedFunctions is asked to submit a call to any other function
+	ErrFunctionNotAllowed = errors.New("function not allowed")
+
+	// ErrBatchTooLarge is returned when a batch has more than MaxBatchSize
+	// items
+	ErrBatchTooLarge = errors.New("batch too large")
+
+	// ErrNotWasmContract is returned when a contract's executable isn't
+	// WASM, e.g. a built-in Stellar Asset Contract; see NotWasmContractError
+	ErrNotWasmContract = errors.New("contract is not a wasm contract")
+
+	// ErrAlreadyInitialized is returned when initializing a contract that
+	// has already been initialized
+	ErrAlreadyInitialized = errors.New("contract already initialized")
+
+	// ErrUpgradeAborted is returned when an UpgradeSafetyConfig.ConfirmFunc
+	// declines an upgrade after its safety checks passed
+	ErrUpgradeAborted = errors.New("upgrade aborted before submission")
+
+	// ErrTransactionFailed is returned when a submitted transaction was
+	// included in a ledger but failed
+	ErrTransactionFailed = errors.New("transaction failed")
+)
+
+// SubmitError is returned when the RPC doesn't accept a submitted
+// transaction. Status is the sendTransaction status, ERROR or
+// TRY_AGAIN_LATER; Code is the result code of an ERROR, e.g. tx_bad_seq,
+// when the RPC reported one.
+type SubmitError struct {
+	TxHash string
+	Status string
+	Code   string
+}
+
+func (e *SubmitError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("transaction %s rejected: %s", e.TxHash, e.Code)
+	}
+	return fmt.Sprintf("transaction %s not accepted: %s", e.TxHash, e.Status)
+}
+
+// ConfirmationTimeoutError carries the hash of a transaction that was not
+// confirmed in time, so the caller can check on it later
+type ConfirmationTimeoutError struct {
+	TxHash   string
+	Attempts int
+}
+
+func (e *ConfirmationTimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s after %d attempts", ErrConfirmationTimeout, e.TxHash, e.Attempts)
+}
+
+// Is makes errors.Is(err, ErrConfirmationTimeout) match
+func (e *ConfirmationTimeoutError) Is(target error) bool {
+	return target == ErrConfirmationTimeout
+}
+
+// NotWasmContractError is returned when reading the WASM hash of a contract
+// whose executable is something else
+type NotWasmContractError struct {
+	Executable xdr.ContractExecutableType
+}
+
+func (e *NotWasmContractError) Error() string {
+	return fmt.Sprintf("%s: executable is %s", ErrNotWasmContract, e.Executable)
+}
+
+// Is makes errors.Is(err, ErrNotWasmContract) match
+func (e *NotWasmContractError) Is(target error) bool {
+	return target == ErrNotWasmContract
+}
+
+// EventsPrunedError is returned when events are requested from a ledger
+// older than the RPC still retains
+type EventsPrunedError struct {
+	Requested uint32
+	Oldest    uint32
+}
+
+func (e *EventsPrunedError) Error() string {
+	return fmt.Sprintf("%s: events from ledger %d requested, oldest retained is %d", ErrLedgerUnavailable, e.Requested, e.Oldest)
+}
+
+// Is makes errors.Is(err, ErrLedgerUnavailable) match
+func (e *EventsPrunedError) Is(target error) bool {
+	return target == ErrLedgerUnavailable
+}
@@ -2,10 +2,16 @@ package soroban
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 )
@@ -15,15 +21,50 @@ type EscrowContract struct {
 	client          *Client
 	txBuilder       *TransactionBuilder
 	contractAddress string
+
+	// BatchRefundConcurrency bounds the refunds BatchRefund has in flight
+	BatchRefundConcurrency int
+
+	// FunctionNames remaps host function names for contracts that export
+	// them under other names, e.g. {"lock_funds": "lock_funds_v2"}
+	FunctionNames map[string]string
+
+	// VerifyRefunds re-reads the escrow after PartialRefund to confirm the
+	// refunded amount left it
+	VerifyRefunds bool
+
+	// DefaultLockDuration sets the deadline of LockFunds calls that pass 0,
+	// counted from the latest ledger's close time. Zero makes a deadline
+	// required. Pass LockForever to lock without one.
+	DefaultLockDuration time.Duration
+
+	// ReadMode selects whether storage reads use getLedgerEntries or
+	// simulated view calls (default: ReadModeAuto)
+	ReadMode ReadMode
+	reads    readFallback
+
+	// AuditLogger, if set, records each refund and feature flag change
+	// once it is submitted
+	AuditLogger AuditLogger
+
+	tokenMu     sync.Mutex
+	tokenConfig *TokenConfig
 }
 
-// NewEscrowContract creates a new escrow contract client
-func NewEscrowContract(client *Client, txBuilder *TransactionBuilder, contractAddress string) *EscrowContract {
-	return &EscrowContract{
-		client:          client,
-		txBuilder:       txBuilder,
-		contractAddress: contractAddress,
+// NewEscrowContract creates a new escrow contract client. It returns an error
+// if contractAddress is malformed.
+func NewEscrowContract(client *Client, txBuilder *TransactionBuilder, contractAddress string) (*EscrowContract, error) {
+	if err := ValidateContractAddress(contractAddress); err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
 	}
+
+	return &EscrowContract{
+		client:                 client,
+		txBuilder:              txBuilder,
+		contractAddress:        contractAddress,
+		BatchRefundConcurrency: DefaultBatchRefundConcurrency,
+		DefaultLockDuration:    DefaultEscrowLockDuration,
+	}, nil
 }
 
 // Init initializes the escrow contract with admin and token addresses
@@ -67,8 +108,21 @@ func (ec *EscrowContract) Init(ctx context.Context, adminAddress, tokenAddress s
 	return result, nil
 }
 
-// LockFunds locks funds for a specific bounty
+// LockForever is the LockFunds deadline for an escrow that never expires
+const LockForever int64 = math.MaxInt64
+
+// DefaultEscrowLockDuration is the default DefaultLockDuration
+const DefaultEscrowLockDuration = 30 * 24 * time.Hour
+
+// LockFunds locks funds for a specific bounty until deadline, a unix time
+// that must be in the future. A zero deadline uses DefaultLockDuration;
+// LockForever locks without one.
 func (ec *EscrowContract) LockFunds(ctx context.Context, depositorAddress string, bountyID uint64, amount int64, deadline int64) (*TransactionResult, error) {
+	deadline, err := ec.lockDeadline(ctx, deadline)
+	if err != nil {
+		return nil, err
+	}
+
 	ec.client.LogContractInteraction(ec.contractAddress, "lock_funds", map[string]interface{}{
 		"depositor": depositorAddress,
 		"bounty_id": bountyID,
@@ -106,7 +160,7 @@ func (ec *EscrowContract) LockFunds(ctx context.Context, depositorAddress string
 	args := []xdr.ScVal{depositorVal, bountyIDVal, amountVal, deadlineVal}
 
 	// Build InvokeHostFunction operation
-	op, err := BuildInvokeHostFunctionOp(contractAddr, "lock_funds", args)
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(ec.FunctionNames, "lock_funds"), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build operation: %w", err)
 	}
@@ -118,7 +172,7 @@ func (ec *EscrowContract) LockFunds(ctx context.Context, depositorAddress string
 	}
 
 	// Wait for confirmation
-	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
+	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
 	if err != nil {
 		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
 		// Return the initial result even if confirmation times out
@@ -128,6 +182,29 @@ func (ec *EscrowContract) LockFunds(ctx context.Context, depositorAddress string
 	return confirmed, nil
 }
 
+// lockDeadline resolves a LockFunds deadline against the latest ledger's
+// close time
+func (ec *EscrowContract) lockDeadline(ctx context.Context, deadline int64) (int64, error) {
+	if deadline == LockForever {
+		return deadline, nil
+	}
+	if deadline == 0 && ec.DefaultLockDuration <= 0 {
+		return 0, fmt.Errorf("deadline is required: pass LockForever to lock without one")
+	}
+
+	now, err := ec.client.LatestLedgerCloseTime(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get ledger close time: %w", err)
+	}
+	if deadline == 0 {
+		return now.Add(ec.DefaultLockDuration).Unix(), nil
+	}
+	if deadline <= now.Unix() {
+		return 0, fmt.Errorf("deadline %s is not in the future", time.Unix(deadline, 0).UTC())
+	}
+	return deadline, nil
+}
+
 // ReleaseFunds releases funds to a contributor (admin only)
 func (ec *EscrowContract) ReleaseFunds(ctx context.Context, bountyID uint64, contributorAddress string) (*TransactionResult, error) {
 	ec.client.LogContractInteraction(ec.contractAddress, "release_funds", map[string]interface{}{
@@ -155,7 +232,7 @@ func (ec *EscrowContract) ReleaseFunds(ctx context.Context, bountyID uint64, con
 	args := []xdr.ScVal{bountyIDVal, contributorVal}
 
 	// Build InvokeHostFunction operation
-	op, err := BuildInvokeHostFunctionOp(contractAddr, "release_funds", args)
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(ec.FunctionNames, "release_funds"), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build operation: %w", err)
 	}
@@ -167,7 +244,7 @@ func (ec *EscrowContract) ReleaseFunds(ctx context.Context, bountyID uint64, con
 	}
 
 	// Wait for confirmation
-	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
+	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
 	if err != nil {
 		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
 		return result, nil
@@ -182,6 +259,29 @@ func (ec *EscrowContract) Refund(ctx context.Context, bountyID uint64) (*Transac
 		"bounty_id": bountyID,
 	})
 
+	op, err := ec.refundOp(bountyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build and submit transaction
+	result, err := ec.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	// Wait for confirmation
+	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
+	if err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+		return result, nil
+	}
+
+	return confirmed, nil
+}
+
+// refundOp builds the refund invocation for bountyID
+func (ec *EscrowContract) refundOp(bountyID uint64) (txnbuild.Operation, error) {
 	// Encode contract address
 	contractAddr, err := EncodeContractAddress(ec.contractAddress)
 	if err != nil {
@@ -197,25 +297,11 @@ func (ec *EscrowContract) Refund(ctx context.Context, bountyID uint64) (*Transac
 	args := []xdr.ScVal{bountyIDVal}
 
 	// Build InvokeHostFunction operation
-	op, err := BuildInvokeHostFunctionOp(contractAddr, "refund", args)
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(ec.FunctionNames, "refund"), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build operation: %w", err)
 	}
-
-	// Build and submit transaction
-	result, err := ec.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
-	if err != nil {
-		return nil, fmt.Errorf("failed to submit transaction: %w", err)
-	}
-
-	// Wait for confirmation
-	confirmed, err := ec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
-	if err != nil {
-		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
-		return result, nil
-	}
-
-	return confirmed, nil
+	return op, nil
 }
 
 // GetEscrowInfo retrieves escrow information (read-only, uses RPC simulation)
@@ -271,3 +357,774 @@ func (ec *EscrowContract) getBalanceRPC(ctx context.Context) (int64, error) {
 	slog.Warn("GetBalance requires transaction building and XDR decoding")
 	return 0, fmt.Errorf("GetBalance requires transaction building - use RPC simulateTransaction")
 }
+
+// GetFeatureFlags retrieves the contract's feature flags (read-only, uses RPC simulation)
+func (ec *EscrowContract) GetFeatureFlags(ctx context.Context) (map[string]bool, error) {
+	contractAddr, err := EncodeContractAddress(ec.contractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "get_feature_flags", []xdr.ScVal{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := ec.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	ret, err := ParseReturnValue(sim)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeFeatureFlags(ret)
+}
+
+// SetFeatureFlag enables or disables a feature flag (admin only). If adminKey
+// is nil the transaction builder's source account signs.
+func (ec *EscrowContract) SetFeatureFlag(ctx context.Context, name string, enabled bool, adminKey *keypair.Full) error {
+	ec.client.LogContractInteraction(ec.contractAddress, "set_feature_flag", map[string]interface{}{
+		"name":    name,
+		"enabled": enabled,
+	})
+
+	if name == "" {
+		return fmt.Errorf("feature flag name is required")
+	}
+
+	// Encode contract address
+	contractAddr, err := EncodeContractAddress(ec.contractAddress)
+	if err != nil {
+		return fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	// Encode function arguments
+	nameVal, err := EncodeScValSymbol(name)
+	if err != nil {
+		return fmt.Errorf("failed to encode name: %w", err)
+	}
+
+	enabledVal, err := EncodeScValBool(enabled)
+	if err != nil {
+		return fmt.Errorf("failed to encode enabled: %w", err)
+	}
+
+	args := []xdr.ScVal{nameVal, enabledVal}
+
+	// Build InvokeHostFunction operation
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "set_feature_flag", args)
+	if err != nil {
+		return fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	// Build and submit transaction signed by the admin
+	txBuilder := ec.txBuilder.withSigner(adminKey)
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	recordAudit(ctx, ec.AuditLogger, AuditEntry{
+		Operation: "set_feature_flag",
+		Contract:  ec.contractAddress,
+		Signer:    txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"name": name, "enabled": enabled},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
+	// Wait for confirmation
+	if _, err := txBuilder.WaitForConfirmation(ctx, result.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+	}
+
+	return nil
+}
+
+// decodeFeatureFlags converts a Map<Symbol, bool> return value into a Go map
+func decodeFeatureFlags(v xdr.ScVal) (map[string]bool, error) {
+	flags := make(map[string]bool)
+
+	// An unset flag map may be returned as void
+	if v.Type == xdr.ScValTypeScvVoid {
+		return flags, nil
+	}
+
+	scMap, ok := v.GetMap()
+	if !ok || scMap == nil {
+		return nil, fmt.Errorf("expected feature flags map, got %s", v.Type)
+	}
+
+	for _, entry := range *scMap {
+		var name string
+		switch entry.Key.Type {
+		case xdr.ScValTypeScvSymbol:
+			name = string(*entry.Key.Sym)
+		case xdr.ScValTypeScvString:
+			name = string(*entry.Key.Str)
+		default:
+			return nil, fmt.Errorf("unexpected feature flag key type %s", entry.Key.Type)
+		}
+
+		enabled, ok := entry.Val.GetB()
+		if !ok {
+			return nil, fmt.Errorf("feature flag %q: expected bool, got %s", name, entry.Val.Type)
+		}
+		flags[name] = enabled
+	}
+
+	return flags, nil
+}
+
+// defaultListBountiesLimit is the page size used when ListBounties is called with limit 0
+const defaultListBountiesLimit = 100
+
+// ListBounties returns a page of bounty IDs held by the contract. It reads the
+// contract's EscrowIndex storage entry directly, so no host function call is
+// needed. Pass the returned nextCursor to fetch the following page; it is
+// empty once the last page has been returned. Simulated reads (see ReadMode)
+// list the IDs in ascending order instead of index order.
+func (ec *EscrowContract) ListBounties(ctx context.Context, cursor string, limit uint32) ([]uint64, string, error) {
+	if ec.simulatedReads() {
+		return ec.listBountiesSimulated(ctx, cursor, limit)
+	}
+
+	keys, err := NewLedgerKeyBuilder(ec.contractAddress)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entries, err := ec.client.ReadEntries(ctx, []xdr.LedgerKey{keys.Persistent(EnumKey("EscrowIndex"))})
+	if err != nil {
+		if ec.entriesReadFailed(ctx, err) {
+			return ec.listBountiesSimulated(ctx, cursor, limit)
+		}
+		return nil, "", fmt.Errorf("failed to read escrow index: %w", err)
+	}
+	ec.entriesReadSucceeded()
+
+	// A contract that has never locked funds has no index entry
+	index := []uint64{}
+	if len(entries) == 1 && entries[0].Found {
+		if entries[0].Data.ContractData == nil {
+			return nil, "", fmt.Errorf("escrow index is not a contract data entry")
+		}
+		index, err = decodeUint64Vec(entries[0].Data.ContractData.Val)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode escrow index: %w", err)
+		}
+	}
+
+	return paginateIDs(index, cursor, limit)
+}
+
+// listBountiesSimulated is ListBounties through simulated view calls
+func (ec *EscrowContract) listBountiesSimulated(ctx context.Context, cursor string, limit uint32) ([]uint64, string, error) {
+	ids, err := ec.simulateBountyIDs(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return paginateIDs(ids, cursor, limit)
+}
+
+// decodeUint64Vec decodes a Vec<u64> return value
+func decodeUint64Vec(v xdr.ScVal) ([]uint64, error) {
+	vec, ok := v.GetVec()
+	if !ok || vec == nil {
+		return nil, fmt.Errorf("expected vec, got %s", v.Type)
+	}
+
+	ids := make([]uint64, 0, len(*vec))
+	for i, item := range *vec {
+		id, err := DecodeScValUint64(item)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// paginateIDs slices ids using an offset cursor encoded as a decimal string
+func paginateIDs(ids []uint64, cursor string, limit uint32) ([]uint64, string, error) {
+	if limit == 0 {
+		limit = defaultListBountiesLimit
+	}
+
+	var offset uint64
+	if cursor != "" {
+		var err error
+		offset, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor %q: %w", cursor, err)
+		}
+	}
+	if offset >= uint64(len(ids)) {
+		return []uint64{}, "", nil
+	}
+
+	end := offset + uint64(limit)
+	if end >= uint64(len(ids)) {
+		return ids[offset:], "", nil
+	}
+	return ids[offset:end], strconv.FormatUint(end, 10), nil
+}
+
+// Claim is a pending claim on a bounty's escrowed funds
+type Claim struct {
+	BountyID  uint64 `json:"bounty_id"`
+	Claimant  string `json:"claimant"`
+	Amount    int64  `json:"amount"`
+	ExpiresAt uint64 `json:"expires_at"`
+	// ClaimedAtLedger is the ledger in which the claim was last written
+	ClaimedAtLedger uint32 `json:"claimed_at_ledger"`
+}
+
+// maxPendingClaimReaders bounds concurrent getLedgerEntries calls in GetAllPendingClaims
+const maxPendingClaimReaders = 4
+
+// pendingClaimsBatchSize is the number of keys fetched per getLedgerEntries call
+const pendingClaimsBatchSize = 100
+
+// GetPendingClaims returns the unexecuted claims on a bounty. The contract
+// keeps at most one claim per bounty under DataKey::PendingClaim(bounty_id),
+// which is read directly so a bounty without claims yields an empty slice
+// rather than the BountyNotFound error get_pending_claim would raise.
+func (ec *EscrowContract) GetPendingClaims(ctx context.Context, bountyID uint64) ([]Claim, error) {
+	claims, err := ec.readPendingClaims(ctx, []uint64{bountyID})
+	if err != nil {
+		return nil, err
+	}
+	return claims[bountyID], nil
+}
+
+// GetAllPendingClaims returns the pending claims for each of the given
+// bounties, keyed by bounty ID. Bounties without claims map to an empty slice.
+func (ec *EscrowContract) GetAllPendingClaims(ctx context.Context, bountyIDs []uint64) (map[uint64][]Claim, error) {
+	all := make(map[uint64][]Claim, len(bountyIDs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	sem := make(chan struct{}, maxPendingClaimReaders)
+
+	for start := 0; start < len(bountyIDs); start += pendingClaimsBatchSize {
+		end := start + pendingClaimsBatchSize
+		if end > len(bountyIDs) {
+			end = len(bountyIDs)
+		}
+		batch := bountyIDs[start:end]
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			claims, err := ec.readPendingClaims(ctx, batch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for id, c := range claims {
+				all[id] = c
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return all, nil
+}
+
+// readPendingClaims fetches the PendingClaim entries for bountyIDs in one call
+func (ec *EscrowContract) readPendingClaims(ctx context.Context, bountyIDs []uint64) (map[uint64][]Claim, error) {
+	if ec.simulatedReads() {
+		return ec.simulatePendingClaims(ctx, bountyIDs)
+	}
+
+	keys, err := NewLedgerKeyBuilder(ec.contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerKeys := make([]xdr.LedgerKey, len(bountyIDs))
+	for i, id := range bountyIDs {
+		idVal, err := EncodeScValUint64(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode bounty_id: %w", err)
+		}
+		ledgerKeys[i] = keys.Persistent(EnumKey("PendingClaim", idVal))
+	}
+
+	entries, err := ec.client.ReadEntries(ctx, ledgerKeys)
+	if err != nil {
+		if ec.entriesReadFailed(ctx, err) {
+			return ec.simulatePendingClaims(ctx, bountyIDs)
+		}
+		return nil, fmt.Errorf("failed to read pending claims: %w", err)
+	}
+	ec.entriesReadSucceeded()
+
+	claims := make(map[uint64][]Claim, len(bountyIDs))
+	for i, id := range bountyIDs {
+		claims[id] = []Claim{}
+		entry := entries[i]
+		if !entry.Found || entry.Data.ContractData == nil {
+			continue
+		}
+
+		claim, pending, err := decodeClaimRecord(entry.Data.ContractData.Val)
+		if err != nil {
+			return nil, fmt.Errorf("bounty %d: failed to decode claim: %w", id, err)
+		}
+		if !pending {
+			continue
+		}
+		claim.BountyID = id
+		claim.ClaimedAtLedger = entry.LastModifiedLedger
+		claims[id] = append(claims[id], claim)
+	}
+
+	return claims, nil
+}
+
+// decodeClaimRecord decodes the contract's ClaimRecord struct. pending is
+// false once the claim has been executed.
+func decodeClaimRecord(v xdr.ScVal) (claim Claim, pending bool, err error) {
+	fields, err := DecodeScValStruct(v)
+	if err != nil {
+		return Claim{}, false, err
+	}
+
+	for _, name := range []string{"recipient", "amount", "expires_at", "claimed"} {
+		if _, ok := fields[name]; !ok {
+			return Claim{}, false, fmt.Errorf("missing field %q", name)
+		}
+	}
+
+	recipient := fields["recipient"].Address
+	if recipient == nil {
+		return Claim{}, false, fmt.Errorf("recipient: expected address, got %s", fields["recipient"].Type)
+	}
+	if claim.Claimant, err = recipient.String(); err != nil {
+		return Claim{}, false, fmt.Errorf("invalid recipient: %w", err)
+	}
+	if claim.Amount, err = DecodeScValInt64(fields["amount"]); err != nil {
+		return Claim{}, false, fmt.Errorf("amount: %w", err)
+	}
+	if claim.ExpiresAt, err = DecodeScValUint64(fields["expires_at"]); err != nil {
+		return Claim{}, false, fmt.Errorf("expires_at: %w", err)
+	}
+	claimed, err := DecodeScValBool(fields["claimed"])
+	if err != nil {
+		return Claim{}, false, fmt.Errorf("claimed: %w", err)
+	}
+
+	return claim, !claimed, nil
+}
+
+// DefaultBatchRefundConcurrency is the default number of refunds BatchRefund
+// has in flight
+const DefaultBatchRefundConcurrency = 4
+
+// RefundOutcome classifies the result of refunding one bounty
+type RefundOutcome string
+
+const (
+	RefundCommitted       RefundOutcome = "committed"
+	RefundAlreadyRefunded RefundOutcome = "already-refunded"
+	RefundNotFound        RefundOutcome = "not-found"
+	RefundError           RefundOutcome = "error"
+)
+
+// BountyRefundResult is the outcome of refunding a single bounty
+type BountyRefundResult struct {
+	Outcome RefundOutcome `json:"outcome"`
+	TxHash  string        `json:"tx_hash,omitempty"`
+	// Amount is the escrow's remaining amount before the refund
+	Amount int64  `json:"amount,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchRefundResult reports every bounty of a BatchRefund call
+type BatchRefundResult struct {
+	Results       map[uint64]BountyRefundResult `json:"results"`
+	TotalRefunded int64                         `json:"total_refunded"`
+}
+
+// escrowState is the part of a stored Escrow the client reads directly
+type escrowState struct {
+	found     bool
+	status    string
+	remaining int64
+	depositor string
+	deadline  uint64
+	// err is set when the escrow couldn't be read or decoded
+	err error
+}
+
+// BatchRefund refunds each bounty, for operator-initiated mass refunds such
+// as a program cancellation. The client does not check deadlines; the
+// contract still enforces its own refund rules. A failing refund is
+// recorded in its result and doesn't stop the others.
+//
+// Escrows are read up front so refunded and missing bounties are skipped.
+// Submissions are serialized because they share one source account sequence,
+// while confirmation waits overlap up to BatchRefundConcurrency. TotalRefunded
+// sums the remaining amounts of committed refunds, which overstates partial
+// refunds made under an admin approval.
+func (ec *EscrowContract) BatchRefund(ctx context.Context, bountyIDs []uint64) (BatchRefundResult, error) {
+	result := BatchRefundResult{Results: make(map[uint64]BountyRefundResult, len(bountyIDs))}
+	if len(bountyIDs) == 0 {
+		return result, fmt.Errorf("bounty ids cannot be empty")
+	}
+
+	states, err := ec.readEscrowStates(ctx, bountyIDs)
+	if err != nil {
+		return result, err
+	}
+
+	limit := ec.BatchRefundConcurrency
+	if limit <= 0 {
+		limit = DefaultBatchRefundConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	var (
+		mu       sync.Mutex
+		submitMu sync.Mutex
+		wg       sync.WaitGroup
+	)
+	record := func(id uint64, r BountyRefundResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Results[id] = r
+		if r.Outcome == RefundCommitted {
+			result.TotalRefunded += r.Amount
+		}
+	}
+
+	for _, id := range bountyIDs {
+		if _, seen := result.Results[id]; seen {
+			continue
+		}
+		state := states[id]
+		switch {
+		case !state.found:
+			record(id, BountyRefundResult{Outcome: RefundNotFound})
+			continue
+		case state.status == "Refunded" || state.status == "Released":
+			record(id, BountyRefundResult{Outcome: RefundAlreadyRefunded})
+			continue
+		}
+
+		wg.Add(1)
+		go func(id uint64, amount int64) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				record(id, BountyRefundResult{Outcome: RefundError, Error: ctx.Err().Error()})
+				return
+			}
+			record(id, ec.refundOne(ctx, id, amount, &submitMu))
+		}(id, state.remaining)
+	}
+	wg.Wait()
+
+	slog.Info("batch refund completed",
+		"contract", ec.contractAddress,
+		"bounties", len(result.Results),
+		"total_refunded", result.TotalRefunded,
+	)
+	return result, nil
+}
+
+// refundOne submits a single refund, holding submitMu only while submitting
+func (ec *EscrowContract) refundOne(ctx context.Context, bountyID uint64, amount int64, submitMu *sync.Mutex) BountyRefundResult {
+	ec.client.LogContractInteraction(ec.contractAddress, "refund", map[string]interface{}{
+		"bounty_id": bountyID,
+	})
+
+	op, err := ec.refundOp(bountyID)
+	if err != nil {
+		return BountyRefundResult{Outcome: RefundError, Error: err.Error()}
+	}
+
+	submitMu.Lock()
+	submitted, err := ec.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	submitMu.Unlock()
+	if err != nil {
+		return BountyRefundResult{Outcome: classifyRefundError(err), Error: err.Error()}
+	}
+
+	recordAudit(ctx, ec.AuditLogger, AuditEntry{
+		Operation: "refund",
+		Contract:  ec.contractAddress,
+		Signer:    ec.txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"bounty_id": bountyID, "amount": amount, "batch": true},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    submitted.Hash,
+	})
+
+	if _, err := ec.txBuilder.WaitForConfirmation(ctx, submitted.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", submitted.Hash)
+	}
+	return BountyRefundResult{Outcome: RefundCommitted, TxHash: submitted.Hash, Amount: amount}
+}
+
+// classifyRefundError maps the contract's BountyNotFound (#4) and
+// FundsNotLocked (#5) errors to outcomes; anything else is an error
+func classifyRefundError(err error) RefundOutcome {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "Error(Contract, #4)"):
+		return RefundNotFound
+	case strings.Contains(msg, "Error(Contract, #5)"):
+		return RefundAlreadyRefunded
+	default:
+		return RefundError
+	}
+}
+
+// readEscrowStates reads the stored escrow of each bounty in batches
+func (ec *EscrowContract) readEscrowStates(ctx context.Context, bountyIDs []uint64) (map[uint64]escrowState, error) {
+	states, err := ec.readEscrows(ctx, bountyIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range bountyIDs {
+		if states[id].err != nil {
+			return nil, states[id].err
+		}
+	}
+	return states, nil
+}
+
+// readEscrows is readEscrowStates that records read and decode failures on
+// the affected bounties' states instead of failing the whole read
+func (ec *EscrowContract) readEscrows(ctx context.Context, bountyIDs []uint64) (map[uint64]escrowState, error) {
+	keys, err := NewLedgerKeyBuilder(ec.contractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[uint64]escrowState, len(bountyIDs))
+	for start := 0; start < len(bountyIDs); start += pendingClaimsBatchSize {
+		end := start + pendingClaimsBatchSize
+		if end > len(bountyIDs) {
+			end = len(bountyIDs)
+		}
+		batch := bountyIDs[start:end]
+		if ec.simulatedReads() {
+			ec.simulateEscrows(ctx, batch, states)
+			continue
+		}
+
+		ledgerKeys := make([]xdr.LedgerKey, len(batch))
+		for i, id := range batch {
+			idVal, err := EncodeScValUint64(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode bounty_id: %w", err)
+			}
+			ledgerKeys[i] = keys.Persistent(EnumKey("Escrow", idVal))
+		}
+
+		entries, err := ec.client.ReadEntries(ctx, ledgerKeys)
+		if err != nil {
+			if ec.entriesReadFailed(ctx, err) {
+				ec.simulateEscrows(ctx, batch, states)
+				continue
+			}
+			for _, id := range batch {
+				states[id] = escrowState{err: fmt.Errorf("failed to read escrows: %w", err)}
+			}
+			continue
+		}
+		ec.entriesReadSucceeded()
+		for i, id := range batch {
+			entry := entries[i]
+			if !entry.Found || entry.Data.ContractData == nil {
+				states[id] = escrowState{}
+				continue
+			}
+			state, err := decodeEscrowState(entry.Data.ContractData.Val)
+			if err != nil {
+				state = escrowState{err: fmt.Errorf("bounty %d: failed to decode escrow: %w", id, err)}
+			}
+			states[id] = state
+		}
+	}
+	return states, nil
+}
+
+// decodeEscrowState decodes the status and remaining amount of an Escrow
+func decodeEscrowState(v xdr.ScVal) (escrowState, error) {
+	fields, err := DecodeScValStruct(v)
+	if err != nil {
+		return escrowState{}, err
+	}
+	statusVal, ok := fields["status"]
+	if !ok {
+		return escrowState{}, fmt.Errorf("missing escrow field status")
+	}
+	remainingVal, ok := fields["remaining_amount"]
+	if !ok {
+		return escrowState{}, fmt.Errorf("missing escrow field remaining_amount")
+	}
+
+	status, err := DecodeScValEnumVariant(statusVal)
+	if err != nil {
+		return escrowState{}, fmt.Errorf("invalid status: %w", err)
+	}
+	remaining, err := DecodeScValInt64(remainingVal)
+	if err != nil {
+		return escrowState{}, fmt.Errorf("invalid remaining_amount: %w", err)
+	}
+	state := escrowState{found: true, status: status, remaining: remaining}
+	if depositorVal, ok := fields["depositor"]; ok {
+		state.depositor, _ = decodeScValAddressString(depositorVal)
+	}
+	if deadlineVal, ok := fields["deadline"]; ok {
+		state.deadline, _ = DecodeScValUint64(deadlineVal)
+	}
+	return state, nil
+}
+
+// PartialRefund refunds amount of a bounty to its depositor and leaves the
+// rest locked (admin only). The amount is checked against the escrow's
+// remaining amount first, returning ErrRefundExceedsLocked. If adminKey is
+// nil the transaction builder's source account signs.
+//
+// Contracts without partial_refund are refunded through an approve_refund
+// for the amount followed by refund, which takes two transactions. With
+// VerifyRefunds set the escrow is re-read afterwards to confirm the
+// remaining amount dropped by amount.
+func (ec *EscrowContract) PartialRefund(ctx context.Context, bountyID uint64, amount int64, adminKey *keypair.Full) error {
+	ec.client.LogContractInteraction(ec.contractAddress, "partial_refund", map[string]interface{}{
+		"bounty_id": bountyID,
+		"amount":    amount,
+	})
+
+	if amount <= 0 {
+		return fmt.Errorf("refund amount must be positive")
+	}
+
+	before, err := ec.lockedEscrow(ctx, bountyID)
+	if err != nil {
+		return err
+	}
+	if amount > before.remaining {
+		return fmt.Errorf("%w: refund of %d exceeds the %d locked in bounty %d",
+			ErrRefundExceedsLocked, amount, before.remaining, bountyID)
+	}
+
+	bountyIDVal, err := EncodeScValUint64(bountyID)
+	if err != nil {
+		return fmt.Errorf("failed to encode bounty_id: %w", err)
+	}
+	amountVal, err := EncodeScValInt64(amount)
+	if err != nil {
+		return fmt.Errorf("failed to encode amount: %w", err)
+	}
+
+	txBuilder := ec.txBuilder.withSigner(adminKey)
+	auditArgs := map[string]interface{}{"bounty_id": bountyID, "amount": amount}
+	err = ec.submitAndWait(ctx, txBuilder, "partial_refund", []xdr.ScVal{bountyIDVal, amountVal}, auditArgs)
+	if errors.Is(err, ErrFunctionNotImplemented) {
+		err = ec.approvedPartialRefund(ctx, txBuilder, bountyID, amountVal, before.depositor, auditArgs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !ec.VerifyRefunds {
+		return nil
+	}
+	after, err := ec.readEscrowStates(ctx, []uint64{bountyID})
+	if err != nil {
+		return fmt.Errorf("failed to verify partial refund: %w", err)
+	}
+	if want := before.remaining - amount; after[bountyID].remaining != want {
+		return fmt.Errorf("partial refund not reflected: bounty %d has %d remaining, expected %d",
+			bountyID, after[bountyID].remaining, want)
+	}
+	return nil
+}
+
+// approvedPartialRefund refunds through approve_refund in Partial mode to the
+// depositor followed by refund, for contracts without partial_refund. Both
+// transactions are audited with auditArgs.
+func (ec *EscrowContract) approvedPartialRefund(ctx context.Context, txBuilder *TransactionBuilder, bountyID uint64, amountVal xdr.ScVal, depositor string, auditArgs map[string]interface{}) error {
+	if depositor == "" {
+		return fmt.Errorf("escrow for bounty %d has no depositor", bountyID)
+	}
+	bountyIDVal, err := EncodeScValUint64(bountyID)
+	if err != nil {
+		return fmt.Errorf("failed to encode bounty_id: %w", err)
+	}
+	recipientVal, err := EncodeScValAddress(depositor)
+	if err != nil {
+		return fmt.Errorf("failed to encode depositor address: %w", err)
+	}
+
+	args := []xdr.ScVal{bountyIDVal, amountVal, recipientVal, EnumKey("Partial")}
+	if err := ec.submitAndWait(ctx, txBuilder, "approve_refund", args, auditArgs); err != nil {
+		return err
+	}
+	return ec.submitAndWait(ctx, txBuilder, "refund", []xdr.ScVal{bountyIDVal}, auditArgs)
+}
+
+// lockedEscrow reads a bounty's escrow, requiring it to still hold funds
+func (ec *EscrowContract) lockedEscrow(ctx context.Context, bountyID uint64) (escrowState, error) {
+	states, err := ec.readEscrowStates(ctx, []uint64{bountyID})
+	if err != nil {
+		return escrowState{}, err
+	}
+	state := states[bountyID]
+	if !state.found {
+		return escrowState{}, fmt.Errorf("bounty %d not found", bountyID)
+	}
+	if state.status != string(EscrowStatusLocked) && state.status != "PartiallyRefunded" {
+		return escrowState{}, fmt.Errorf("bounty %d is %s, not locked", bountyID, state.status)
+	}
+	return state, nil
+}
+
+// submitAndWait invokes fn with txBuilder and waits for confirmation, only
+// warning if confirmation times out. The submission is audited as fn with
+// auditArgs.
+func (ec *EscrowContract) submitAndWait(ctx context.Context, txBuilder *TransactionBuilder, fn string, args []xdr.ScVal, auditArgs map[string]interface{}) error {
+	op, err := buildContractOp(ec.contractAddress, hostFunction(ec.FunctionNames, fn), args)
+	if err != nil {
+		return err
+	}
+
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return fmt.Errorf("failed to submit %s: %w", fn, err)
+	}
+
+	recordAudit(ctx, ec.AuditLogger, AuditEntry{
+		Operation: fn,
+		Contract:  ec.contractAddress,
+		Signer:    txBuilder.signer.PublicKey(),
+		Args:      auditArgs,
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
+	if _, err := txBuilder.WaitForConfirmation(ctx, result.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+	}
+	return nil
+}
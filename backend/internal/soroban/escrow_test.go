@@ -0,0 +1,374 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestDecodeFeatureFlags(t *testing.T) {
+	keyA, _ := EncodeScValSymbol("batch_payouts")
+	keyB, _ := EncodeScValSymbol("partial_refunds")
+	on, _ := EncodeScValBool(true)
+	off, _ := EncodeScValBool(false)
+
+	m := xdr.ScMap{{Key: keyA, Val: on}, {Key: keyB, Val: off}}
+	mPtr := &m
+	v := xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+
+	flags, err := decodeFeatureFlags(v)
+	if err != nil {
+		t.Fatalf("decodeFeatureFlags failed: %v", err)
+	}
+	if len(flags) != 2 {
+		t.Fatalf("expected 2 flags, got %d", len(flags))
+	}
+	if !flags["batch_payouts"] {
+		t.Error("expected batch_payouts to be enabled")
+	}
+	if flags["partial_refunds"] {
+		t.Error("expected partial_refunds to be disabled")
+	}
+}
+
+func TestDecodeFeatureFlags_Void(t *testing.T) {
+	flags, err := decodeFeatureFlags(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(flags) != 0 {
+		t.Errorf("expected no flags, got %d", len(flags))
+	}
+}
+
+func TestDecodeFeatureFlags_InvalidValue(t *testing.T) {
+	key, _ := EncodeScValSymbol("batch_payouts")
+	val, _ := EncodeScValInt64(1)
+
+	m := xdr.ScMap{{Key: key, Val: val}}
+	mPtr := &m
+	v := xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+
+	if _, err := decodeFeatureFlags(v); err == nil {
+		t.Error("expected error for non-bool flag value")
+	}
+}
+
+func TestPaginateIDs(t *testing.T) {
+	ids := []uint64{10, 11, 12, 13, 14}
+
+	page, next, err := paginateIDs(ids, "", 2)
+	if err != nil || len(page) != 2 || page[0] != 10 || next != "2" {
+		t.Fatalf("unexpected first page %v, cursor %q, err %v", page, next, err)
+	}
+
+	page, next, err = paginateIDs(ids, next, 10)
+	if err != nil || len(page) != 3 || page[0] != 12 || next != "" {
+		t.Fatalf("unexpected last page %v, cursor %q, err %v", page, next, err)
+	}
+
+	page, next, err = paginateIDs([]uint64{}, "", 0)
+	if err != nil || len(page) != 0 || next != "" {
+		t.Errorf("expected empty page for empty index, got %v, %q, %v", page, next, err)
+	}
+
+	if _, _, err := paginateIDs(ids, "abc", 2); err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+}
+
+func TestDecodeUint64Vec(t *testing.T) {
+	a, _ := EncodeScValUint64(1)
+	b, _ := EncodeScValUint64(2)
+	v, _ := EncodeScValVec([]xdr.ScVal{a, b})
+
+	ids, err := decodeUint64Vec(v)
+	if err != nil || len(ids) != 2 || ids[1] != 2 {
+		t.Errorf("unexpected ids %v, err %v", ids, err)
+	}
+
+	bad, _ := EncodeScValVec([]xdr.ScVal{{Type: xdr.ScValTypeScvVoid}})
+	if _, err := decodeUint64Vec(bad); err == nil {
+		t.Error("expected error for non-u64 element")
+	}
+}
+
+func claimRecordVal(t *testing.T, claimed bool) xdr.ScVal {
+	t.Helper()
+	recipient, err := EncodeScValAddress(keypair.MustRandom().Address())
+	if err != nil {
+		t.Fatalf("failed to encode recipient: %v", err)
+	}
+	amount, _ := EncodeScValInt64(2500)
+	expires, _ := EncodeScValUint64(1700000000)
+	bountyID, _ := EncodeScValUint64(7)
+	claimedVal, _ := EncodeScValBool(claimed)
+
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	m := xdr.ScMap{
+		field("amount", amount),
+		field("bounty_id", bountyID),
+		field("claimed", claimedVal),
+		field("expires_at", expires),
+		field("reason", xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
+		field("recipient", recipient),
+	}
+	mPtr := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+}
+
+func TestDecodeClaimRecord(t *testing.T) {
+	claim, pending, err := decodeClaimRecord(claimRecordVal(t, false))
+	if err != nil {
+		t.Fatalf("decodeClaimRecord failed: %v", err)
+	}
+	if !pending {
+		t.Error("expected unexecuted claim to be pending")
+	}
+	if claim.Amount != 2500 || claim.ExpiresAt != 1700000000 || claim.Claimant == "" {
+		t.Errorf("unexpected claim %+v", claim)
+	}
+
+	if _, pending, _ := decodeClaimRecord(claimRecordVal(t, true)); pending {
+		t.Error("expected executed claim not to be pending")
+	}
+
+	empty := xdr.ScMap{}
+	emptyPtr := &empty
+	if _, _, err := decodeClaimRecord(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &emptyPtr}); err == nil {
+		t.Error("expected error for missing fields")
+	}
+}
+
+func escrowVal(t *testing.T, status string, remaining int64) xdr.ScVal {
+	t.Helper()
+	sym, _ := EncodeScValSymbol(status)
+	statusVal, _ := EncodeScValVec([]xdr.ScVal{sym})
+	amount, _ := EncodeScValInt64(remaining)
+
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	m := xdr.ScMap{
+		field("remaining_amount", amount),
+		field("status", statusVal),
+	}
+	mPtr := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+}
+
+func TestDecodeEscrowState(t *testing.T) {
+	state, err := decodeEscrowState(escrowVal(t, "PartiallyRefunded", 400))
+	if err != nil {
+		t.Fatalf("decodeEscrowState failed: %v", err)
+	}
+	if !state.found || state.status != "PartiallyRefunded" || state.remaining != 400 {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	empty := xdr.ScMap{}
+	emptyPtr := &empty
+	if _, err := decodeEscrowState(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &emptyPtr}); err == nil {
+		t.Error("expected error for missing fields")
+	}
+}
+
+func TestClassifyRefundError(t *testing.T) {
+	cases := map[string]RefundOutcome{
+		"simulation failed: HostError: Error(Contract, #4)": RefundNotFound,
+		"simulation failed: HostError: Error(Contract, #5)": RefundAlreadyRefunded,
+		"simulation failed: HostError: Error(Contract, #6)": RefundError,
+		"connection refused": RefundError,
+	}
+	for msg, want := range cases {
+		if got := classifyRefundError(errors.New(msg)); got != want {
+			t.Errorf("%q: expected %s, got %s", msg, want, got)
+		}
+	}
+}
+
+func TestBatchRefund_SkipsSettledAndMissing(t *testing.T) {
+	b, err := NewLedgerKeyBuilder(testContractHex)
+	if err != nil {
+		t.Fatalf("NewLedgerKeyBuilder failed: %v", err)
+	}
+	refundedID, _ := EncodeScValUint64(1)
+	srv := newFakeRPC(t)
+	srv.putEntry(b.Persistent(EnumKey("Escrow", refundedID)), escrowVal(t, "Refunded", 0))
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+
+	// No transaction builder is needed: neither bounty reaches submission.
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+	result, err := ec.BatchRefund(context.Background(), []uint64{1, 2})
+	if err != nil {
+		t.Fatalf("BatchRefund failed: %v", err)
+	}
+	if got := result.Results[1].Outcome; got != RefundAlreadyRefunded {
+		t.Errorf("expected bounty 1 already refunded, got %s", got)
+	}
+	if got := result.Results[2].Outcome; got != RefundNotFound {
+		t.Errorf("expected bounty 2 not found, got %s", got)
+	}
+	if result.TotalRefunded != 0 {
+		t.Errorf("expected nothing refunded, got %d", result.TotalRefunded)
+	}
+}
+
+func TestBatchRefund_EmptyInput(t *testing.T) {
+	ec, _ := NewEscrowContract(nil, nil, testContractHex)
+	if _, err := ec.BatchRefund(context.Background(), nil); err == nil {
+		t.Error("expected error for empty bounty ids")
+	}
+}
+
+func TestContractConstructors_RejectMalformedAddress(t *testing.T) {
+	for _, addr := range []string{"", "not-a-contract", "CABC", testContractHex[:62]} {
+		if _, err := NewEscrowContract(nil, nil, addr); err == nil {
+			t.Errorf("NewEscrowContract(%q): expected error", addr)
+		}
+		if _, err := NewProgramEscrowContract(nil, nil, addr); err == nil {
+			t.Errorf("NewProgramEscrowContract(%q): expected error", addr)
+		}
+		if _, err := NewUpgradeSafetyClient(nil, nil, addr); err == nil {
+			t.Errorf("NewUpgradeSafetyClient(%q): expected error", addr)
+		}
+	}
+
+	if _, err := NewEscrowContract(nil, nil, testContractHex); err != nil {
+		t.Errorf("expected valid address to be accepted, got %v", err)
+	}
+}
+
+// escrowEntryServer serves a single stored Escrow for bountyID
+func escrowEntryServer(t *testing.T, bountyID uint64, val xdr.ScVal) *fakeRPC {
+	t.Helper()
+	b, err := NewLedgerKeyBuilder(testContractHex)
+	if err != nil {
+		t.Fatalf("NewLedgerKeyBuilder failed: %v", err)
+	}
+	id, _ := EncodeScValUint64(bountyID)
+	srv := newFakeRPC(t)
+	srv.putEntry(b.Persistent(EnumKey("Escrow", id)), val)
+	return srv
+}
+
+func TestPartialRefund_RejectsOverRefund(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: escrowEntryServer(t, 1, escrowVal(t, "Locked", 400)).URL})
+
+	// No transaction builder is needed: invalid refunds fail before submission.
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+	if err := ec.PartialRefund(context.Background(), 1, 500, nil); !errors.Is(err, ErrRefundExceedsLocked) {
+		t.Errorf("expected ErrRefundExceedsLocked, got %v", err)
+	}
+	if err := ec.PartialRefund(context.Background(), 1, 0, nil); err == nil {
+		t.Error("expected an error for a zero refund")
+	}
+	if err := ec.PartialRefund(context.Background(), 2, 100, nil); err == nil {
+		t.Error("expected an error for a missing bounty")
+	}
+}
+
+func TestPartialRefund_RejectsSettledEscrow(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: escrowEntryServer(t, 1, escrowVal(t, "Released", 0)).URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+	if err := ec.PartialRefund(context.Background(), 1, 100, nil); err == nil || !strings.Contains(err.Error(), "not locked") {
+		t.Errorf("expected a not-locked error, got %v", err)
+	}
+}
+
+// memoryAuditLogger keeps the entries it records
+type memoryAuditLogger struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (l *memoryAuditLogger) Record(ctx context.Context, entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func TestEscrowContract_AuditsRefundsAndFlags(t *testing.T) {
+	void, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	srv := escrowEntryServer(t, 1, escrowVal(t, "Locked", 400))
+	srv.answer("simulateTransaction", fmt.Sprintf(`{"latestLedger":5,"results":[{"xdr":%q}]}`, void))
+	srv.answer("sendTransaction", sentTx)
+	srv.answer("getTransaction", confirmedTx(t, 6, 100, ""))
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = time.Millisecond
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), rc)
+	tb.account.prewarm(&txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey(), Sequence: 1})
+	ec, _ := NewEscrowContract(client, tb, testContractHex)
+	audit := &memoryAuditLogger{}
+	ec.AuditLogger = audit
+
+	ctx := context.Background()
+	if err := ec.SetFeatureFlag(ctx, "refunds", true, nil); err != nil {
+		t.Fatalf("SetFeatureFlag failed: %v", err)
+	}
+	if err := ec.PartialRefund(ctx, 1, 100, nil); err != nil {
+		t.Fatalf("PartialRefund failed: %v", err)
+	}
+	if _, err := ec.BatchRefund(ctx, []uint64{1}); err != nil {
+		t.Fatalf("BatchRefund failed: %v", err)
+	}
+
+	var ops []string
+	for _, e := range audit.entries {
+		ops = append(ops, e.Operation)
+		if e.TxHash != "abc123" || e.Signer != tb.signer.PublicKey() || e.Contract != testContractHex {
+			t.Errorf("unexpected audit entry %+v", e)
+		}
+	}
+	if strings.Join(ops, ",") != "set_feature_flag,partial_refund,refund" {
+		t.Errorf("expected each privileged call audited, got %v", ops)
+	}
+	if amount := audit.entries[1].Args["amount"]; amount != int64(100) {
+		t.Errorf("expected the partial refund amount audited, got %v", amount)
+	}
+}
+
+func TestLockDeadline(t *testing.T) {
+	srv := rpcServer(t, `{"sequence":100,"closeTime":"1700000000"}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+	ctx := context.Background()
+
+	deadline, err := ec.lockDeadline(ctx, 0)
+	if err != nil {
+		t.Fatalf("lockDeadline failed: %v", err)
+	}
+	if want := int64(1700000000) + int64(DefaultEscrowLockDuration.Seconds()); deadline != want {
+		t.Errorf("expected the default duration from the ledger close time (%d), got %d", want, deadline)
+	}
+
+	if deadline, err := ec.lockDeadline(ctx, LockForever); err != nil || deadline != LockForever {
+		t.Errorf("expected LockForever to pass through, got %d, %v", deadline, err)
+	}
+	if _, err := ec.lockDeadline(ctx, 1699999999); err == nil {
+		t.Error("expected a past deadline to be rejected")
+	}
+	if deadline, err := ec.lockDeadline(ctx, 1800000000); err != nil || deadline != 1800000000 {
+		t.Errorf("expected a future deadline to pass through, got %d, %v", deadline, err)
+	}
+
+	ec.DefaultLockDuration = 0
+	if _, err := ec.lockDeadline(ctx, 0); err == nil {
+		t.Error("expected a zero deadline to be rejected without a default duration")
+	}
+}
@@ -0,0 +1,218 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultEventPollInterval is how often StreamEvents polls for new events
+const DefaultEventPollInterval = 5 * time.Second
+
+// eventPageLimit is the number of events requested per getEvents call
+const eventPageLimit = 100
+
+// ContractEvent is a contract event returned by getEvents
+type ContractEvent struct {
+	ID         string
+	Ledger     uint32
+	ContractID string
+	Topics     []xdr.ScVal
+	Value      xdr.ScVal
+}
+
+// EventPage is one page of getEvents results
+type EventPage struct {
+	Events       []ContractEvent
+	Cursor       string
+	LatestLedger uint32
+
+	// fetched counts the events the RPC returned, including undecodable
+	// ones left out of Events
+	fetched int
+}
+
+type getEventsResponse struct {
+	Events []struct {
+		ID         string   `json:"id"`
+		Ledger     uint32   `json:"ledger"`
+		ContractID string   `json:"contractId"`
+		Topic      []string `json:"topic"`
+		Value      string   `json:"value"`
+	} `json:"events"`
+	Cursor       string `json:"cursor"`
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+// GetEvents returns a page of contractID's events, starting at startLedger or,
+// when cursor is set, after cursor
+func (c *Client) GetEvents(ctx context.Context, contractID string, startLedger uint32, cursor string) (*EventPage, error) {
+	return c.getEvents(ctx, contractID, nil, startLedger, cursor)
+}
+
+// getEvents returns a page of contractID's events matching any of topics,
+// each a list of base64 XDR segments. No topics matches every event.
+func (c *Client) getEvents(ctx context.Context, contractID string, topics [][]string, startLedger uint32, cursor string) (*EventPage, error) {
+	addr, err := EncodeContractAddress(contractID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+	strkeyID, err := addr.String()
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	filter := map[string]interface{}{"type": "contract", "contractIds": []string{strkeyID}}
+	if len(topics) > 0 {
+		filter["topics"] = topics
+	}
+	pagination := map[string]interface{}{"limit": eventPageLimit}
+	params := map[string]interface{}{
+		"filters":    []map[string]interface{}{filter},
+		"pagination": pagination,
+	}
+	// startLedger and cursor are mutually exclusive
+	if cursor != "" {
+		pagination["cursor"] = cursor
+	} else {
+		params["startLedger"] = startLedger
+	}
+
+	resp, err := c.Call(ctx, "getEvents", params)
+	if err != nil {
+		return nil, err
+	}
+	return parseEventPage(resp.Result)
+}
+
+// parseEventPage decodes a getEvents result. Events whose XDR can't be
+// decoded are logged and left out.
+func parseEventPage(raw json.RawMessage) (*EventPage, error) {
+	var resp getEventsResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+
+	page := &EventPage{Cursor: resp.Cursor, LatestLedger: resp.LatestLedger, fetched: len(resp.Events)}
+	for _, e := range resp.Events {
+		event := ContractEvent{ID: e.ID, Ledger: e.Ledger, ContractID: e.ContractID, Topics: make([]xdr.ScVal, len(e.Topic))}
+		err := xdr.SafeUnmarshalBase64(e.Value, &event.Value)
+		for i, topic := range e.Topic {
+			if err == nil {
+				err = xdr.SafeUnmarshalBase64(topic, &event.Topics[i])
+			}
+		}
+		if err != nil {
+			slog.Warn("skipping undecodable contract event", "event_id", e.ID, "error", err)
+			continue
+		}
+		page.Events = append(page.Events, event)
+	}
+	return page, nil
+}
+
+// OldestLedger returns the oldest ledger the RPC still retains events for
+func (c *Client) OldestLedger(ctx context.Context) (uint32, error) {
+	resp, err := c.Call(ctx, "getHealth", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		OldestLedger uint32 `json:"oldestLedger"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	if result.OldestLedger == 0 {
+		return 0, fmt.Errorf("getHealth returned no oldest ledger")
+	}
+	return result.OldestLedger, nil
+}
+
+// EventStream is a subscription started by StreamEvents
+type EventStream struct {
+	// Events delivers events in order and is closed when the stream's
+	// context is done
+	Events <-chan ContractEvent
+	// CaughtUp is closed once every event up to the tip has been delivered,
+	// marking the switch from backfilled history to live events
+	CaughtUp <-chan struct{}
+}
+
+// StreamEvents delivers contractID's events from startLedger, or from the
+// latest ledger if it is zero. History is backfilled page by page until
+// caught up with the tip, then getEvents is polled every poll interval
+// (default: DefaultEventPollInterval). Failed polls are logged and retried.
+// A startLedger the RPC has already pruned returns an *EventsPrunedError.
+func (c *Client) StreamEvents(ctx context.Context, contractID string, startLedger uint32, poll time.Duration) (*EventStream, error) {
+	if err := ValidateContractAddress(contractID); err != nil {
+		return nil, err
+	}
+	if poll <= 0 {
+		poll = DefaultEventPollInterval
+	}
+	if startLedger == 0 {
+		latest, err := c.LatestLedgerSequence(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get start ledger: %w", err)
+		}
+		startLedger = latest
+	} else {
+		oldest, err := c.OldestLedger(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get oldest retained ledger: %w", err)
+		}
+		if startLedger < oldest {
+			return nil, &EventsPrunedError{Requested: startLedger, Oldest: oldest}
+		}
+	}
+
+	events := make(chan ContractEvent)
+	caughtUp := make(chan struct{})
+	go func() {
+		defer close(events)
+		var cursor string
+		backfilling := true
+		for {
+			page, err := c.GetEvents(ctx, contractID, startLedger, cursor)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.Warn("failed to poll contract events", "contract_id", contractID, "error", err)
+			} else {
+				for _, event := range page.Events {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if page.Cursor != "" {
+					cursor = page.Cursor
+				}
+				// A full page means more events are waiting, even if some
+				// of it could not be decoded
+				if page.fetched == eventPageLimit {
+					continue
+				}
+				if backfilling {
+					backfilling = false
+					close(caughtUp)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(poll):
+			}
+		}
+	}()
+	return &EventStream{Events: events, CaughtUp: caughtUp}, nil
+}
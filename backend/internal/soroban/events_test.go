@@ -0,0 +1,137 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestParseEventPage_SkipsUndecodable(t *testing.T) {
+	topic, _ := EncodeScValSymbol("f_ref")
+	topicB64, _ := xdr.MarshalBase64(topic)
+	value, _ := EncodeScValUint64(7)
+	valueB64, _ := xdr.MarshalBase64(value)
+
+	raw := `{"events":[` +
+		`{"id":"1","ledger":10,"topic":["` + topicB64 + `"],"value":"` + valueB64 + `"},` +
+		`{"id":"2","ledger":11,"topic":["not-xdr"],"value":"` + valueB64 + `"}` +
+		`],"cursor":"c1","latestLedger":12}`
+
+	page, err := parseEventPage([]byte(raw))
+	if err != nil {
+		t.Fatalf("parseEventPage failed: %v", err)
+	}
+	if len(page.Events) != 1 || page.Events[0].ID != "1" {
+		t.Fatalf("expected only the decodable event, got %+v", page.Events)
+	}
+	if page.fetched != 2 {
+		t.Errorf("expected both returned events to be counted, got %d", page.fetched)
+	}
+	if page.Cursor != "c1" || page.LatestLedger != 12 {
+		t.Errorf("unexpected page metadata %+v", page)
+	}
+	if sym := page.Events[0].Topics[0].Sym; sym == nil || string(*sym) != "f_ref" {
+		t.Errorf("expected the f_ref topic, got %+v", page.Events[0].Topics)
+	}
+}
+
+func TestGetEvents_InvalidContract(t *testing.T) {
+	srv := rpcServer(t, `{"events":[],"latestLedger":1}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+
+	if _, err := client.GetEvents(context.Background(), "not-a-contract", 1, ""); err == nil {
+		t.Error("expected an error for an invalid contract address")
+	}
+
+	page, err := client.GetEvents(context.Background(), testContractHex, 1, "")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(page.Events) != 0 || page.LatestLedger != 1 {
+		t.Errorf("unexpected page %+v", page)
+	}
+}
+
+func TestStreamEvents_PrunedStart(t *testing.T) {
+	srv := rpcServer(t, `{"status":"healthy","oldestLedger":500,"latestLedger":600}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+
+	_, err := client.StreamEvents(context.Background(), testContractHex, 100, time.Millisecond)
+	var pruned *EventsPrunedError
+	if !errors.As(err, &pruned) || pruned.Requested != 100 || pruned.Oldest != 500 {
+		t.Fatalf("expected an EventsPrunedError, got %v", err)
+	}
+	if !errors.Is(err, ErrLedgerUnavailable) {
+		t.Error("expected the error to match ErrLedgerUnavailable")
+	}
+}
+
+func TestStreamEvents_BackfillsThenCatchesUp(t *testing.T) {
+	value, _ := EncodeScValUint64(1)
+	valueB64, _ := xdr.MarshalBase64(value)
+	page := func(first, n int, cursor string) string {
+		events := make([]string, n)
+		for i := range events {
+			events[i] = fmt.Sprintf(`{"id":"%d","ledger":10,"topic":[],"value":%q}`, first+i, valueB64)
+		}
+		return fmt.Sprintf(`{"events":[%s],"cursor":%q,"latestLedger":20}`, strings.Join(events, ","), cursor)
+	}
+	// The first page is full but its first event can't be decoded, which
+	// must not end the backfill early
+	firstPage := strings.Replace(page(0, eventPageLimit, "c1"), valueB64, "not-xdr", 1)
+
+	srv := newFakeRPC(t)
+	srv.answer("getHealth", `{"oldestLedger":1}`)
+	srv.answerFunc("getEvents", func(params json.RawMessage) string {
+		var p struct {
+			Pagination struct {
+				Cursor string `json:"cursor"`
+			} `json:"pagination"`
+		}
+		_ = json.Unmarshal(params, &p)
+		switch p.Pagination.Cursor {
+		case "":
+			return firstPage
+		case "c1":
+			return page(eventPageLimit, 1, "c2")
+		default:
+			return page(0, 0, "c2")
+		}
+	})
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamEvents(ctx, testContractHex, 5, time.Millisecond)
+	if err != nil {
+		t.Fatalf("StreamEvents failed: %v", err)
+	}
+
+	for i := 1; i <= eventPageLimit; i++ {
+		select {
+		case event := <-stream.Events:
+			if event.ID != fmt.Sprint(i) {
+				t.Fatalf("expected event %d, got %s", i, event.ID)
+			}
+		case <-stream.CaughtUp:
+			t.Fatalf("caught up after only %d events", i)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+	select {
+	case <-stream.CaughtUp:
+	case <-time.After(time.Second):
+		t.Fatal("expected the stream to catch up after the backfill")
+	}
+
+	cancel()
+	for range stream.Events {
+	}
+}
@@ -0,0 +1,205 @@
+package soroban
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// fakeRPC is an in-process Soroban RPC for tests, like sorobantest.StubClient
+// but usable from inside the package. Each method is answered by its handler,
+// or failed with its error; getLedgerEntries otherwise serves the contract
+// data stored with putEntry. Methods with no answer get the fallback result
+// if one is set, else a method-not-found error. Calls are counted per method.
+type fakeRPC struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	handlers map[string]func(params json.RawMessage) string
+	failures map[string]string
+	entries  map[string]string // base64 ledger key to base64 entry data
+	fallback string
+	calls    map[string]int
+}
+
+func newFakeRPC(t *testing.T) *fakeRPC {
+	t.Helper()
+	f := &fakeRPC{
+		t:        t,
+		handlers: make(map[string]func(json.RawMessage) string),
+		failures: make(map[string]string),
+		calls:    make(map[string]int),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Server.Close)
+	return f
+}
+
+func (f *fakeRPC) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls[req.Method]++
+	handler, handled := f.handlers[req.Method]
+	rpcErr, failed := f.failures[req.Method]
+	result := f.fallback
+	if req.Method == "getLedgerEntries" && f.entries != nil {
+		result = f.storedEntries(req.Params)
+	}
+	f.mu.Unlock()
+
+	// Handlers run unlocked so they may block or call back into the fake
+	if handled {
+		result = handler(req.Params)
+	}
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+	switch {
+	case failed:
+		body = fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"error":%s}`, rpcErr)
+	case result == "":
+		body = fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found: %s"}}`, req.Method)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+}
+
+// storedEntries answers a getLedgerEntries request with whichever of the
+// requested keys have stored entries. f.mu must be held.
+func (f *fakeRPC) storedEntries(params json.RawMessage) string {
+	var p struct {
+		Keys []string `json:"keys"`
+	}
+	_ = json.Unmarshal(params, &p)
+	var found []string
+	for _, k := range p.Keys {
+		if data, ok := f.entries[k]; ok {
+			found = append(found, fmt.Sprintf(`{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":5}`, k, data))
+		}
+	}
+	return `{"entries":[` + strings.Join(found, ",") + `],"latestLedger":100}`
+}
+
+// answer answers method with result, a JSON document
+func (f *fakeRPC) answer(method, result string) {
+	f.answerFunc(method, func(json.RawMessage) string { return result })
+}
+
+// answerFunc answers method with the JSON document fn returns for the
+// request's params
+func (f *fakeRPC) answerFunc(method string, fn func(params json.RawMessage) string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = fn
+}
+
+// answerAll answers every method without a handler of its own with result
+func (f *fakeRPC) answerAll(result string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = result
+}
+
+// fail answers method with rpcErr, a JSON-RPC error object. An empty
+// rpcErr stops failing method.
+func (f *fakeRPC) fail(method, rpcErr string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rpcErr == "" {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = rpcErr
+}
+
+// putEntry stores val as the contract data entry under key, for
+// getLedgerEntries to serve
+func (f *fakeRPC) putEntry(key xdr.LedgerKey, val xdr.ScVal) {
+	f.t.Helper()
+	k, err := xdr.MarshalBase64(key)
+	if err != nil {
+		f.t.Fatalf("failed to encode ledger key: %v", err)
+	}
+	data, err := xdr.MarshalBase64(contractDataEntry(key, val))
+	if err != nil {
+		f.t.Fatalf("failed to encode ledger entry: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.entries == nil {
+		f.entries = make(map[string]string)
+	}
+	f.entries[k] = data
+}
+
+// callCount returns how many times method was called
+func (f *fakeRPC) callCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// sentTx is a sendTransaction result accepting a transaction as pending
+const sentTx = `{"status":"PENDING","hash":"abc123","latestLedger":100}`
+
+// pendingTx is a getTransaction result for a transaction not yet in a ledger
+const pendingTx = `{"status":"NOT_FOUND","latestLedger":100}`
+
+// rejectedTx is a sendTransaction result rejecting a transaction with code
+func rejectedTx(t *testing.T, code xdr.TransactionResultCode) string {
+	t.Helper()
+	res, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: code},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode transaction result: %v", err)
+	}
+	return fmt.Sprintf(`{"status":"ERROR","hash":"abc123","latestLedger":100,"errorResultXdr":%q}`, res)
+}
+
+// confirmedTx is a getTransaction result for a transaction that succeeded in
+// ledger, charging fee
+func confirmedTx(t *testing.T, ledger uint32, fee int64, envelopeXDR string) string {
+	t.Helper()
+	res, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: xdr.Int64(fee),
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode transaction result: %v", err)
+	}
+	return fmt.Sprintf(`{"status":"SUCCESS","latestLedger":%d,"ledger":%d,"envelopeXdr":%q,"resultXdr":%q}`,
+		ledger, ledger, envelopeXDR, res)
+}
+
+// contractDataEntry is the contract data entry holding val under key, a
+// contract data ledger key
+func contractDataEntry(key xdr.LedgerKey, val xdr.ScVal) xdr.LedgerEntryData {
+	return xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   key.ContractData.Contract,
+			Key:        key.ContractData.Key,
+			Durability: key.ContractData.Durability,
+			Val:        val,
+		},
+	}
+}
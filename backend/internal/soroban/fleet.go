@@ -0,0 +1,266 @@
+package soroban
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultFleetCacheSize is the number of simulation reports kept
+	DefaultFleetCacheSize = 256
+	// DefaultFleetCacheTTL is how long a simulation report stays fresh
+	DefaultFleetCacheTTL = 30 * time.Second
+	// DefaultFleetConcurrency bounds parallel simulations in one batch
+	DefaultFleetConcurrency = 8
+	// DefaultFleetSimulationTimeout bounds one shared contract simulation
+	DefaultFleetSimulationTimeout = 30 * time.Second
+)
+
+// FleetCacheConfig sizes the simulation cache of a FleetUpgradeClient.
+// Zero values use the defaults.
+type FleetCacheConfig struct {
+	Size int
+	TTL  time.Duration
+}
+
+// FleetResult is the upgrade simulation outcome for one contract
+type FleetResult struct {
+	Contract string
+	Report   *UpgradeSafetyReport
+	Err      error
+	// Cached is true when Report was served from the cache
+	Cached bool
+}
+
+// FleetCacheStats reports simulation cache effectiveness
+type FleetCacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+// FleetUpgradeClient runs upgrade safety simulations across many contracts.
+// Reports are cached per contract so repeated dashboard refreshes only
+// re-simulate contracts whose entries expired or were evicted.
+type FleetUpgradeClient struct {
+	client    *Client
+	txBuilder *TransactionBuilder
+
+	// MaxConcurrency bounds the simulations run in parallel by one batch
+	MaxConcurrency int
+	// SimulationTimeout bounds each contract's simulation. Simulations are
+	// shared between overlapping batches, so they run detached from any one
+	// batch's context and stop at this timeout instead.
+	SimulationTimeout time.Duration
+
+	cache    *simulationCache
+	inflight singleflight.Group
+	simulate func(ctx context.Context, contract string) (*UpgradeSafetyReport, error)
+}
+
+// NewFleetUpgradeClient creates a fleet client with a simulation cache
+func NewFleetUpgradeClient(client *Client, txBuilder *TransactionBuilder, cacheConfig FleetCacheConfig) *FleetUpgradeClient {
+	f := &FleetUpgradeClient{
+		client:            client,
+		txBuilder:         txBuilder,
+		MaxConcurrency:    DefaultFleetConcurrency,
+		SimulationTimeout: DefaultFleetSimulationTimeout,
+		cache:             newSimulationCache(cacheConfig),
+	}
+	f.simulate = func(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+		u, err := NewUpgradeSafetyClient(f.client, f.txBuilder, contract)
+		if err != nil {
+			return nil, err
+		}
+		return u.SimulateUpgrade(ctx)
+	}
+	return f
+}
+
+// SimulateFleet returns an upgrade safety report for each contract, in input
+// order. Fresh cached reports are reused and only missing contracts are
+// simulated; concurrent calls share a single simulation per contract.
+// Failed simulations are reported per contract and are not cached.
+func (f *FleetUpgradeClient) SimulateFleet(ctx context.Context, contracts []string) []FleetResult {
+	results := make([]FleetResult, len(contracts))
+
+	limit := f.MaxConcurrency
+	if limit <= 0 {
+		limit = DefaultFleetConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, contract := range contracts {
+		results[i].Contract = contract
+		if report, ok := f.cache.get(contract); ok {
+			results[i].Report = report
+			results[i].Cached = true
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, contract string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			results[i].Report, results[i].Err = f.simulateOnce(ctx, contract)
+		}(i, contract)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// simulateOnce simulates contract, sharing the result with any concurrent
+// caller already simulating it, and caches successful reports. The shared
+// simulation doesn't stop when the caller that started it gives up, so one
+// canceled batch can't fail the others waiting on it; each caller stops
+// waiting when its own ctx is done.
+func (f *FleetUpgradeClient) simulateOnce(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+	timeout := f.SimulationTimeout
+	if timeout <= 0 {
+		timeout = DefaultFleetSimulationTimeout
+	}
+	shared := context.WithoutCancel(ctx)
+
+	ch := f.inflight.DoChan(contract, func() (interface{}, error) {
+		// Another batch may have filled the cache while this one waited
+		if report, ok := f.cache.peek(contract); ok {
+			return report, nil
+		}
+		simCtx, cancel := context.WithTimeout(shared, timeout)
+		defer cancel()
+		report, err := f.simulate(simCtx, contract)
+		if err != nil {
+			return nil, err
+		}
+		f.cache.put(contract, report)
+		return report, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*UpgradeSafetyReport), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CacheStats returns the simulation cache counters
+func (f *FleetUpgradeClient) CacheStats() FleetCacheStats {
+	return f.cache.stats()
+}
+
+// simulationCache is an LRU cache of simulation reports whose entries also
+// expire after a TTL
+type simulationCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+
+	hits, misses, evictions atomic.Uint64
+}
+
+type cacheEntry struct {
+	contract string
+	report   *UpgradeSafetyReport
+	expires  time.Time
+}
+
+func newSimulationCache(cfg FleetCacheConfig) *simulationCache {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultFleetCacheSize
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultFleetCacheTTL
+	}
+	return &simulationCache{
+		size:  cfg.Size,
+		ttl:   cfg.TTL,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// get returns a fresh report and counts the lookup as a hit or miss
+func (c *simulationCache) get(contract string) (*UpgradeSafetyReport, bool) {
+	report, ok := c.peek(contract)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return report, ok
+}
+
+// peek returns a fresh report without touching the hit/miss counters
+func (c *simulationCache) peek(contract string) (*UpgradeSafetyReport, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[contract]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.report, true
+}
+
+func (c *simulationCache) put(contract string, report *UpgradeSafetyReport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.items[contract]; ok {
+		el.Value = &cacheEntry{contract: contract, report: report, expires: expires}
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[contract] = c.ll.PushFront(&cacheEntry{contract: contract, report: report, expires: expires})
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+// remove drops an entry; the caller holds c.mu
+func (c *simulationCache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry).contract)
+	c.evictions.Add(1)
+}
+
+func (c *simulationCache) stats() FleetCacheStats {
+	c.mu.Lock()
+	size := c.ll.Len()
+	c.mu.Unlock()
+	return FleetCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
@@ -0,0 +1,142 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testFleet(cfg FleetCacheConfig, simulate func(ctx context.Context, contract string) (*UpgradeSafetyReport, error)) *FleetUpgradeClient {
+	f := NewFleetUpgradeClient(nil, nil, cfg)
+	f.simulate = simulate
+	return f
+}
+
+func TestSimulateFleet_CachesReports(t *testing.T) {
+	var calls int32
+	f := testFleet(FleetCacheConfig{}, func(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+		atomic.AddInt32(&calls, 1)
+		return &UpgradeSafetyReport{IsSafe: true}, nil
+	})
+
+	f.SimulateFleet(context.Background(), []string{"a", "b"})
+	results := f.SimulateFleet(context.Background(), []string{"a", "b", "c"})
+
+	if calls != 3 {
+		t.Errorf("expected only the new contract to be simulated, got %d simulations", calls)
+	}
+	if !results[0].Cached || !results[1].Cached || results[2].Cached {
+		t.Errorf("unexpected cached flags: %+v", results)
+	}
+	stats := f.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 3 || stats.Size != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestSimulateFleet_ErrorsNotCached(t *testing.T) {
+	var calls int32
+	f := testFleet(FleetCacheConfig{}, func(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("rpc down")
+	})
+
+	for i := 0; i < 2; i++ {
+		if results := f.SimulateFleet(context.Background(), []string{"a"}); results[0].Err == nil {
+			t.Fatal("expected the simulation error to be reported")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected failures to be retried, got %d simulations", calls)
+	}
+}
+
+func TestSimulateFleet_SingleFlight(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	f := testFleet(FleetCacheConfig{}, func(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &UpgradeSafetyReport{}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.SimulateFleet(context.Background(), []string{"shared"})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected overlapping batches to share one simulation, got %d", calls)
+	}
+}
+
+func TestSimulateFleet_CanceledBatchDoesNotFailSharers(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f := testFleet(FleetCacheConfig{}, func(ctx context.Context, contract string) (*UpgradeSafetyReport, error) {
+		close(started)
+		select {
+		case <-release:
+			return &UpgradeSafetyReport{IsSafe: true}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan []FleetResult)
+	go func() { first <- f.SimulateFleet(ctx, []string{"shared"}) }()
+	<-started
+
+	second := make(chan []FleetResult)
+	go func() { second <- f.SimulateFleet(context.Background(), []string{"shared"}) }()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if results := <-first; !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("expected the canceled batch to get its own error, got %+v", results[0])
+	}
+	close(release)
+	if results := <-second; results[0].Err != nil || results[0].Report == nil {
+		t.Errorf("expected the other batch to get the shared report, got %+v", results[0])
+	}
+	if stats := f.CacheStats(); stats.Size != 1 {
+		t.Errorf("expected the shared report to be cached, got %+v", stats)
+	}
+}
+
+func TestSimulationCache_TTLAndLRU(t *testing.T) {
+	c := newSimulationCache(FleetCacheConfig{Size: 2, TTL: time.Minute})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.put("a", &UpgradeSafetyReport{})
+	c.put("b", &UpgradeSafetyReport{})
+	c.get("a") // a is now most recently used
+	c.put("c", &UpgradeSafetyReport{})
+
+	if _, ok := c.peek("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.peek("a"); !ok {
+		t.Error("expected recently used entry to survive")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.peek("a"); ok {
+		t.Error("expected expired entry to be evicted")
+	}
+	if stats := c.stats(); stats.Evictions != 2 {
+		t.Errorf("expected 2 evictions, got %d", stats.Evictions)
+	}
+}
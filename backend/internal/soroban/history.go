@@ -0,0 +1,131 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// BountyEvent is one lock, release or refund in a bounty's history
+type BountyEvent struct {
+	// Type is the event topic: f_lock, f_rel or f_ref
+	Type     string
+	BountyID uint64
+	Amount   int64
+	// Address is the depositor of a lock, the recipient of a release or
+	// where a refund went
+	Address string
+	Ledger  uint32
+	EventID string
+}
+
+// BountyHistory is a bounty's events in chronological order
+type BountyHistory struct {
+	Events []BountyEvent
+	// Incomplete is set when the bounty's opening lock is missing, which
+	// means the RPC has pruned at least the start of its history
+	Incomplete bool
+}
+
+// bountyEventAddressFields maps each history topic to the event field
+// holding its counterparty
+var bountyEventAddressFields = map[string]string{
+	eventFundsLocked:   "depositor",
+	eventFundsReleased: "recipient",
+	eventFundsRefunded: "refund_to",
+}
+
+// GetBountyHistory returns the lock, release and refund events for bountyID
+// that the RPC still retains. RPCs only keep recent events, so history
+// older than the retention window is missing and reported as Incomplete.
+func (ec *EscrowContract) GetBountyHistory(ctx context.Context, bountyID uint64) (*BountyHistory, error) {
+	topics, err := bountyEventTopics(bountyID)
+	if err != nil {
+		return nil, err
+	}
+	start, err := ec.client.OldestLedger(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oldest retained ledger: %w", err)
+	}
+
+	history := &BountyHistory{}
+	var cursor string
+	for {
+		page, err := ec.client.getEvents(ctx, ec.contractAddress, topics, start, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bounty events: %w", err)
+		}
+		for _, event := range page.Events {
+			decoded, err := decodeBountyEvent(event)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode event %s: %w", event.ID, err)
+			}
+			history.Events = append(history.Events, decoded)
+		}
+		// A full page means more events are waiting, even if some of it
+		// could not be decoded
+		if page.fetched < eventPageLimit || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	history.Incomplete = len(history.Events) == 0 || history.Events[0].Type != eventFundsLocked
+	return history, nil
+}
+
+// bountyEventTopics builds the getEvents topic filters matching a bounty's
+// lock, release and refund events, published as (symbol, bounty_id)
+func bountyEventTopics(bountyID uint64) ([][]string, error) {
+	id, err := EncodeScValUint64(bountyID)
+	if err != nil {
+		return nil, err
+	}
+	idB64, err := xdr.MarshalBase64(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bounty id: %w", err)
+	}
+
+	topics := make([][]string, 0, 3)
+	for _, name := range []string{eventFundsLocked, eventFundsReleased, eventFundsRefunded} {
+		sym, err := EncodeScValSymbol(name)
+		if err != nil {
+			return nil, err
+		}
+		symB64, err := xdr.MarshalBase64(sym)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode topic %s: %w", name, err)
+		}
+		topics = append(topics, []string{symB64, idB64})
+	}
+	return topics, nil
+}
+
+// decodeBountyEvent decodes a lock, release or refund event
+func decodeBountyEvent(event ContractEvent) (BountyEvent, error) {
+	if len(event.Topics) == 0 || event.Topics[0].Type != xdr.ScValTypeScvSymbol {
+		return BountyEvent{}, fmt.Errorf("event has no topic symbol")
+	}
+	topic := string(*event.Topics[0].Sym)
+	addressField, ok := bountyEventAddressFields[topic]
+	if !ok {
+		return BountyEvent{}, fmt.Errorf("unexpected topic %s", topic)
+	}
+
+	fields, err := DecodeScValStruct(event.Value)
+	if err != nil {
+		return BountyEvent{}, err
+	}
+	decoded := BountyEvent{Type: topic, Ledger: event.Ledger, EventID: event.ID}
+	if decoded.BountyID, err = structField(fields, "bounty_id", DecodeScValUint64); err != nil {
+		return BountyEvent{}, err
+	}
+	if decoded.Amount, err = structField(fields, "amount", DecodeScValInt64); err != nil {
+		return BountyEvent{}, err
+	}
+	if decoded.Address, err = structField(fields, addressField, decodeScValAddressString); err != nil {
+		return BountyEvent{}, err
+	}
+	return decoded, nil
+}
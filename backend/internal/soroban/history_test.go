@@ -0,0 +1,149 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+// historyServer serves getHealth and a single getEvents page of events,
+// each given as [topic symbol, value]
+func historyServer(t *testing.T, bountyID uint64, events [][2]xdr.ScVal) (*fakeRPC, *json.RawMessage) {
+	t.Helper()
+	id, _ := EncodeScValUint64(bountyID)
+	idB64, _ := xdr.MarshalBase64(id)
+
+	var entries []string
+	for i, e := range events {
+		topic, _ := xdr.MarshalBase64(e[0])
+		value, _ := xdr.MarshalBase64(e[1])
+		entries = append(entries, fmt.Sprintf(`{"id":"%d","ledger":%d,"topic":[%q,%q],"value":%q}`, i, 50+i, topic, idB64, value))
+	}
+
+	params := new(json.RawMessage)
+	srv := newFakeRPC(t)
+	srv.answer("getHealth", `{"oldestLedger":40,"latestLedger":60}`)
+	srv.answerFunc("getEvents", func(p json.RawMessage) string {
+		*params = p
+		return `{"events":[` + strings.Join(entries, ",") + `],"latestLedger":60}`
+	})
+	return srv, params
+}
+
+func bountyEventVal(t *testing.T, bountyID uint64, amount int64, addressField, address string) xdr.ScVal {
+	t.Helper()
+	id, _ := EncodeScValUint64(bountyID)
+	amt, _ := EncodeScValInt64(amount)
+	addr, _ := EncodeScValAddress(address)
+
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	m := xdr.ScMap{field("amount", amt), field("bounty_id", id), field(addressField, addr)}
+	mPtr := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+}
+
+func TestGetBountyHistory(t *testing.T) {
+	depositor, recipient := keypair.MustRandom().Address(), keypair.MustRandom().Address()
+	lock, _ := EncodeScValSymbol("f_lock")
+	release, _ := EncodeScValSymbol("f_rel")
+
+	srv, params := historyServer(t, 7, [][2]xdr.ScVal{
+		{lock, bountyEventVal(t, 7, 1000, "depositor", depositor)},
+		{release, bountyEventVal(t, 7, 1000, "recipient", recipient)},
+	})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	history, err := ec.GetBountyHistory(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetBountyHistory failed: %v", err)
+	}
+	if history.Incomplete {
+		t.Error("expected a history starting with its lock to be complete")
+	}
+	if len(history.Events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", history.Events)
+	}
+	if e := history.Events[0]; e.Type != "f_lock" || e.Amount != 1000 || e.Address != depositor || e.Ledger != 50 {
+		t.Errorf("unexpected lock event %+v", e)
+	}
+	if e := history.Events[1]; e.Type != "f_rel" || e.Address != recipient || e.BountyID != 7 {
+		t.Errorf("unexpected release event %+v", e)
+	}
+
+	var sent struct {
+		StartLedger uint32 `json:"startLedger"`
+		Filters     []struct {
+			Topics [][]string `json:"topics"`
+		} `json:"filters"`
+	}
+	_ = json.Unmarshal(*params, &sent)
+	if sent.StartLedger != 40 || len(sent.Filters) != 1 || len(sent.Filters[0].Topics) != 3 {
+		t.Errorf("expected a topic-filtered query from the oldest ledger, got %s", *params)
+	}
+}
+
+func TestGetBountyHistory_PagesPastUndecodable(t *testing.T) {
+	id, _ := EncodeScValUint64(7)
+	idB64, _ := xdr.MarshalBase64(id)
+	event := func(i int, name string, val xdr.ScVal) string {
+		sym, _ := EncodeScValSymbol(name)
+		topic, _ := xdr.MarshalBase64(sym)
+		value, _ := xdr.MarshalBase64(val)
+		return fmt.Sprintf(`{"id":"%d","ledger":%d,"topic":[%q,%q],"value":%q}`, i, 50+i, topic, idB64, value)
+	}
+
+	// The first page is full, but all except its lock fail to decode
+	first := []string{event(0, "f_lock", bountyEventVal(t, 7, 1000, "depositor", keypair.MustRandom().Address()))}
+	for i := 1; i < eventPageLimit; i++ {
+		first = append(first, fmt.Sprintf(`{"id":"%d","ledger":%d,"topic":["AAAA"],"value":"AAAA"}`, i, 50+i))
+	}
+	release := event(eventPageLimit, "f_rel", bountyEventVal(t, 7, 1000, "recipient", keypair.MustRandom().Address()))
+
+	srv := newFakeRPC(t)
+	srv.answer("getHealth", `{"oldestLedger":40,"latestLedger":200}`)
+	srv.answerFunc("getEvents", func(params json.RawMessage) string {
+		if strings.Contains(string(params), `"cursor":"p2"`) {
+			return `{"events":[` + release + `],"cursor":"p3","latestLedger":200}`
+		}
+		return `{"events":[` + strings.Join(first, ",") + `],"cursor":"p2","latestLedger":200}`
+	})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	history, err := ec.GetBountyHistory(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetBountyHistory failed: %v", err)
+	}
+	if len(history.Events) != 2 || history.Events[1].Type != "f_rel" {
+		t.Errorf("expected the release on the next page, got %+v", history.Events)
+	}
+	if n := srv.callCount("getEvents"); n != 2 {
+		t.Errorf("expected 2 pages, got %d", n)
+	}
+}
+
+func TestGetBountyHistory_PrunedLock(t *testing.T) {
+	refund, _ := EncodeScValSymbol("f_ref")
+	srv, _ := historyServer(t, 7, [][2]xdr.ScVal{
+		{refund, bountyEventVal(t, 7, 400, "refund_to", keypair.MustRandom().Address())},
+	})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	history, err := ec.GetBountyHistory(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetBountyHistory failed: %v", err)
+	}
+	if !history.Incomplete || len(history.Events) != 1 {
+		t.Errorf("expected the retained refund flagged incomplete, got %+v", history)
+	}
+}
@@ -52,7 +52,10 @@ func TestEscrowContract_Integration(t *testing.T) {
 	}
 
 	// Create escrow contract client
-	escrow := NewEscrowContract(client, txBuilder, contractID)
+	escrow, err := NewEscrowContract(client, txBuilder, contractID)
+	if err != nil {
+		t.Fatalf("failed to create escrow contract client: %v", err)
+	}
 
 	ctx := context.Background()
 
@@ -114,7 +117,10 @@ func TestProgramEscrowContract_Integration(t *testing.T) {
 	}
 
 	// Create program escrow contract client
-	programEscrow := NewProgramEscrowContract(client, txBuilder, contractID)
+	programEscrow, err := NewProgramEscrowContract(client, txBuilder, contractID)
+	if err != nil {
+		t.Fatalf("failed to create program escrow contract client: %v", err)
+	}
 
 	ctx := context.Background()
 
@@ -0,0 +1,211 @@
+package soroban
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Codec selects how a JSON-lines file sink encodes its file
+type Codec string
+
+const (
+	// CodecJSONL writes plain JSON lines. It is the default.
+	CodecJSONL Codec = "jsonl"
+	// CodecGzip writes gzip-compressed JSON lines. Each time the file is
+	// opened a new gzip member is appended, which gzip, zcat and OpenJSONL
+	// all read as one stream. A member a crash left unfinished is completed
+	// when the file is next opened for writing, see repairGzip.
+	CodecGzip Codec = "gzip"
+)
+
+// validate reports an unknown codec. The zero value means CodecJSONL.
+func (c Codec) validate() error {
+	switch c {
+	case "", CodecJSONL, CodecGzip:
+		return nil
+	default:
+		return fmt.Errorf("unknown codec %q", c)
+	}
+}
+
+// jsonlWriter buffers lines for a file sink. Flush pushes everything written
+// so far to the file, readable even if the process then crashes; Close
+// flushes and ends the encoding but leaves the file open.
+type jsonlWriter interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+// openJSONLFile opens path for appending with codec, creating it if needed.
+// A gzip file is repaired first so the member appended next stays readable.
+func openJSONLFile(path string, codec Codec) (*os.File, error) {
+	if codec == CodecGzip {
+		if err := repairGzip(path); err != nil {
+			return nil, fmt.Errorf("failed to repair gzip file: %w", err)
+		}
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+}
+
+// repairGzip rewrites an unfinished last gzip member at path as a complete
+// one. Readers stop at the first broken member, so a member appended after
+// one a crash cut short would be unreadable. The broken member's flushed
+// lines are kept; a partial last line is dropped. A file that doesn't start
+// with a gzip member is left alone.
+func repairGzip(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := &countingReader{r: bufio.NewReader(file)}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		// Empty, or not gzip at all
+		return nil
+	}
+
+	var complete int64 // end of the last complete member
+	var salvaged bytes.Buffer
+	for {
+		gz.Multistream(false)
+		salvaged.Reset()
+		if _, err := io.Copy(&salvaged, gz); err != nil {
+			break
+		}
+		complete = r.n
+		if err := gz.Reset(r); err == io.EOF {
+			return nil
+		} else if err != nil {
+			// A header cut short: nothing of the member was flushed
+			salvaged.Reset()
+			break
+		}
+	}
+
+	lines := salvaged.Bytes()
+	lines = lines[:bytes.LastIndexByte(lines, '\n')+1]
+	slog.Warn("repairing unfinished gzip member",
+		"path", path,
+		"offset", complete,
+		"salvaged_bytes", len(lines),
+	)
+	if err := file.Truncate(complete); err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if _, err := file.Seek(complete, io.SeekStart); err != nil {
+		return err
+	}
+	w := gzip.NewWriter(file)
+	if _, err := w.Write(lines); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// countingReader counts the bytes read through it. It is an io.ByteReader,
+// so a gzip.Reader reads from it without buffering past a member's end.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// newJSONLWriter returns a writer encoding to file with codec
+func newJSONLWriter(file *os.File, codec Codec) jsonlWriter {
+	if codec == CodecGzip {
+		return gzip.NewWriter(file)
+	}
+	return plainWriter{bufio.NewWriter(file)}
+}
+
+// plainWriter is a bufio.Writer with nothing to end on Close
+type plainWriter struct {
+	*bufio.Writer
+}
+
+func (w plainWriter) Close() error {
+	return w.Flush()
+}
+
+// gzipMagic starts every gzip member
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// OpenJSONL opens a JSON-lines file written by one of this package's file
+// sinks, e.g. a dead-letter file for replay, decompressing it if it was
+// written with CodecGzip. A gzip file cut short by a crash reads up to its
+// last flush instead of failing.
+func OpenJSONL(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(file)
+	magic, err := r.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		// Too short to be gzip, or plain JSON lines
+		return readCloser{r, file}, nil
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	return readCloser{truncatedGzipReader{gz}, file}, nil
+}
+
+// readCloser reads from Reader and closes file
+type readCloser struct {
+	io.Reader
+	file *os.File
+}
+
+func (r readCloser) Close() error {
+	return r.file.Close()
+}
+
+// truncatedGzipReader ends the stream cleanly at a missing gzip footer,
+// which is all a crash between flushes leaves behind
+type truncatedGzipReader struct {
+	gz *gzip.Reader
+}
+
+func (r truncatedGzipReader) Read(p []byte) (int, error) {
+	n, err := r.gz.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,186 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerKeyBuilder derives contract-data ledger keys for a single contract so
+// read paths don't each reinvent key construction
+type LedgerKeyBuilder struct {
+	contract xdr.ScAddress
+}
+
+// NewLedgerKeyBuilder creates a key builder for the given contract address
+func NewLedgerKeyBuilder(contractAddress string) (*LedgerKeyBuilder, error) {
+	contract, err := EncodeContractAddress(contractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+	return &LedgerKeyBuilder{contract: contract}, nil
+}
+
+// Persistent returns the ledger key for a persistent storage entry
+func (b *LedgerKeyBuilder) Persistent(key xdr.ScVal) xdr.LedgerKey {
+	return b.contractDataKey(key, xdr.ContractDataDurabilityPersistent)
+}
+
+// Temporary returns the ledger key for a temporary storage entry
+func (b *LedgerKeyBuilder) Temporary(key xdr.ScVal) xdr.LedgerKey {
+	return b.contractDataKey(key, xdr.ContractDataDurabilityTemporary)
+}
+
+// Instance returns the ledger key of the contract instance, which holds
+// instance storage and the contract's executable
+func (b *LedgerKeyBuilder) Instance() xdr.LedgerKey {
+	return b.contractDataKey(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent)
+}
+
+func (b *LedgerKeyBuilder) contractDataKey(key xdr.ScVal, durability xdr.ContractDataDurability) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   b.contract,
+			Key:        key,
+			Durability: durability,
+		},
+	}
+}
+
+// EnumKey encodes a #[contracttype] enum variant storage key such as
+// DataKey::Escrow(bounty_id), which Soroban serializes as Vec[Symbol, ...fields]
+func EnumKey(variant string, fields ...xdr.ScVal) xdr.ScVal {
+	sym := xdr.ScSymbol(variant)
+	vals := make([]xdr.ScVal, 0, len(fields)+1)
+	vals = append(vals, xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	vals = append(vals, fields...)
+	return NewScValVec(vals...)
+}
+
+// LedgerEntryResult represents a single entry returned by getLedgerEntries
+type LedgerEntryResult struct {
+	Key                xdr.LedgerKey
+	Found              bool
+	Data               xdr.LedgerEntryData
+	LastModifiedLedger uint32
+	// LiveUntilLedger is the ledger after which the entry is archived. It is
+	// zero for entries without a TTL (e.g. accounts).
+	LiveUntilLedger uint32
+}
+
+// getLedgerEntriesResponse is the JSON result of getLedgerEntries
+type getLedgerEntriesResponse struct {
+	Entries []struct {
+		Key                string  `json:"key"`
+		XDR                string  `json:"xdr"`
+		LastModifiedLedger uint32  `json:"lastModifiedLedgerSeq"`
+		LiveUntilLedger    *uint32 `json:"liveUntilLedgerSeq,omitempty"`
+	} `json:"entries"`
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+// maxLedgerEntryKeys is the most keys Soroban RPC accepts in one
+// getLedgerEntries call
+const maxLedgerEntryKeys = 200
+
+// ReadEntries fetches the given ledger keys, each distinct key once, in as
+// few getLedgerEntries calls as the RPC's per-call key limit allows. Results
+// are returned in the same order as keys, a repeated key filling each of its
+// positions; keys with no live entry have Found set to false.
+func (c *Client) ReadEntries(ctx context.Context, keys []xdr.LedgerKey) ([]LedgerEntryResult, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	encoded := make([]string, len(keys))
+	var uniqueKeys []xdr.LedgerKey
+	var uniqueEncoded []string
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		k, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger key %d: %w", i, err)
+		}
+		encoded[i] = k
+		if !seen[k] {
+			seen[k] = true
+			uniqueKeys = append(uniqueKeys, key)
+			uniqueEncoded = append(uniqueEncoded, k)
+		}
+	}
+
+	found := make(map[string]LedgerEntryResult, len(uniqueKeys))
+	for start := 0; start < len(uniqueKeys); start += maxLedgerEntryKeys {
+		end := start + maxLedgerEntryKeys
+		if end > len(uniqueKeys) {
+			end = len(uniqueKeys)
+		}
+
+		params := map[string]interface{}{
+			"keys": uniqueEncoded[start:end],
+		}
+
+		resp, err := c.Call(ctx, "getLedgerEntries", params)
+		if err != nil {
+			return nil, err
+		}
+
+		chunk, err := parseLedgerEntries(resp.Result, uniqueKeys[start:end], uniqueEncoded[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range chunk {
+			found[uniqueEncoded[start+i]] = result
+		}
+	}
+
+	results := make([]LedgerEntryResult, len(keys))
+	for i, k := range encoded {
+		results[i] = found[k]
+		results[i].Key = keys[i]
+	}
+	return results, nil
+}
+
+// parseLedgerEntries matches the entries in a getLedgerEntries result back to
+// the requested keys by their base64 encoding. A key requested twice gets
+// its entry at both positions.
+func parseLedgerEntries(raw json.RawMessage, keys []xdr.LedgerKey, encodedKeys []string) ([]LedgerEntryResult, error) {
+	var resp getLedgerEntriesResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+
+	index := make(map[string][]int, len(encodedKeys))
+	results := make([]LedgerEntryResult, len(keys))
+	for i, k := range encodedKeys {
+		index[k] = append(index[k], i)
+		results[i].Key = keys[i]
+	}
+
+	for _, entry := range resp.Entries {
+		positions, ok := index[entry.Key]
+		if !ok {
+			return nil, fmt.Errorf("getLedgerEntries returned unrequested key %s", entry.Key)
+		}
+
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(entry.XDR, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode ledger entry %d: %w", positions[0], err)
+		}
+
+		for _, i := range positions {
+			results[i].Found = true
+			results[i].Data = data
+			results[i].LastModifiedLedger = entry.LastModifiedLedger
+			if entry.LiveUntilLedger != nil {
+				results[i].LiveUntilLedger = *entry.LiveUntilLedger
+			}
+		}
+	}
+
+	return results, nil
+}
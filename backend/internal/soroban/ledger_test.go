@@ -0,0 +1,145 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+const testContractHex = "0000000000000000000000000000000000000000000000000000000000000000"
+
+func TestLedgerKeyBuilder(t *testing.T) {
+	b, err := NewLedgerKeyBuilder(testContractHex)
+	if err != nil {
+		t.Fatalf("NewLedgerKeyBuilder failed: %v", err)
+	}
+
+	bountyID, _ := EncodeScValUint64(42)
+	key := b.Persistent(EnumKey("Escrow", bountyID))
+
+	if key.Type != xdr.LedgerEntryTypeContractData {
+		t.Fatalf("expected contract data key, got %v", key.Type)
+	}
+	if key.ContractData.Durability != xdr.ContractDataDurabilityPersistent {
+		t.Errorf("expected persistent durability, got %v", key.ContractData.Durability)
+	}
+
+	vec, ok := key.ContractData.Key.GetVec()
+	if !ok || vec == nil || len(*vec) != 2 {
+		t.Fatalf("expected 2-element enum key vector")
+	}
+	if sym := (*vec)[0].Sym; sym == nil || *sym != "Escrow" {
+		t.Errorf("expected Escrow variant symbol, got %v", sym)
+	}
+
+	instance := b.Instance()
+	if instance.ContractData.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance {
+		t.Errorf("expected instance key, got %v", instance.ContractData.Key.Type)
+	}
+
+	if temp := b.Temporary(bountyID); temp.ContractData.Durability != xdr.ContractDataDurabilityTemporary {
+		t.Errorf("expected temporary durability, got %v", temp.ContractData.Durability)
+	}
+}
+
+func TestParseLedgerEntries(t *testing.T) {
+	b, _ := NewLedgerKeyBuilder(testContractHex)
+	id1, _ := EncodeScValUint64(1)
+	id2, _ := EncodeScValUint64(2)
+	keys := []xdr.LedgerKey{b.Persistent(id1), b.Persistent(id2)}
+
+	encoded := make([]string, len(keys))
+	for i, k := range keys {
+		encoded[i], _ = xdr.MarshalBase64(k)
+	}
+
+	amount, _ := EncodeScValInt64(500)
+	dataXDR, _ := xdr.MarshalBase64(contractDataEntry(keys[1], amount))
+
+	// Only the second key has a live entry.
+	raw := json.RawMessage(fmt.Sprintf(`{
+		"entries": [{"key": %q, "xdr": %q, "lastModifiedLedgerSeq": 90, "liveUntilLedgerSeq": 5000}],
+		"latestLedger": 100
+	}`, encoded[1], dataXDR))
+
+	results, err := parseLedgerEntries(raw, keys, encoded)
+	if err != nil {
+		t.Fatalf("parseLedgerEntries failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Found {
+		t.Error("expected first key to be missing")
+	}
+	if !results[1].Found {
+		t.Fatal("expected second key to be found")
+	}
+	if results[1].LiveUntilLedger != 5000 {
+		t.Errorf("expected live-until 5000, got %d", results[1].LiveUntilLedger)
+	}
+	if results[1].LastModifiedLedger != 90 {
+		t.Errorf("expected last-modified 90, got %d", results[1].LastModifiedLedger)
+	}
+	if v := results[1].Data.ContractData.Val.I64; v == nil || *v != 500 {
+		t.Errorf("expected value 500, got %v", v)
+	}
+}
+
+func TestParseLedgerEntries_DuplicateKey(t *testing.T) {
+	b, _ := NewLedgerKeyBuilder(testContractHex)
+	id, _ := EncodeScValUint64(1)
+	key := b.Persistent(id)
+	encoded, _ := xdr.MarshalBase64(key)
+	amount, _ := EncodeScValInt64(500)
+	dataXDR, _ := xdr.MarshalBase64(contractDataEntry(key, amount))
+
+	raw := json.RawMessage(fmt.Sprintf(`{"entries":[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":90}],"latestLedger":100}`, encoded, dataXDR))
+	results, err := parseLedgerEntries(raw, []xdr.LedgerKey{key, key}, []string{encoded, encoded})
+	if err != nil {
+		t.Fatalf("parseLedgerEntries failed: %v", err)
+	}
+	if !results[0].Found || !results[1].Found || results[1].LastModifiedLedger != 90 {
+		t.Errorf("expected both positions filled, got %+v", results)
+	}
+}
+
+func TestReadEntries_ChunksAtKeyLimit(t *testing.T) {
+	b, _ := NewLedgerKeyBuilder(testContractHex)
+	srv := newFakeRPC(t)
+	keys := make([]xdr.LedgerKey, 2*maxLedgerEntryKeys)
+	for i := range keys {
+		id, _ := EncodeScValUint64(uint64(i))
+		keys[i] = b.Persistent(id)
+		if i%2 == 0 {
+			amount, _ := EncodeScValInt64(int64(i))
+			srv.putEntry(keys[i], amount)
+		}
+	}
+	// A repeated key is requested once but fills both positions
+	last := len(keys) - 2
+	keys = append(keys, keys[last])
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	results, err := client.ReadEntries(context.Background(), keys)
+	if err != nil {
+		t.Fatalf("ReadEntries failed: %v", err)
+	}
+	if n := srv.callCount("getLedgerEntries"); n != 2 {
+		t.Errorf("expected 2 calls of %d keys, got %d", maxLedgerEntryKeys, n)
+	}
+	if len(results) != len(keys) {
+		t.Fatalf("expected %d results, got %d", len(keys), len(results))
+	}
+	for i, r := range results {
+		if r.Found != (i%2 == 0 || i == len(keys)-1) {
+			t.Errorf("result %d: unexpected Found %v", i, r.Found)
+		}
+	}
+	if v := results[len(keys)-1].Data.ContractData.Val.I64; v == nil || int(*v) != last {
+		t.Errorf("expected the repeated key's entry, got %v", v)
+	}
+}
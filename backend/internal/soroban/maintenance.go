@@ -0,0 +1,245 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultStuckLockThreshold is the number of ledgers (~1 minute) a reentrancy
+// lock must be held before ClearReentrancyLock will release it
+const DefaultStuckLockThreshold uint32 = 12
+
+// MaintenanceClient provides operator methods for inspecting and recovering
+// contract state outside the normal escrow flows
+type MaintenanceClient struct {
+	client          *Client
+	txBuilder       *TransactionBuilder
+	contractAddress string
+
+	// StuckLockThreshold is the minimum number of ledgers a reentrancy lock
+	// must have been held before it may be cleared. This avoids racing a
+	// legitimately in-progress call.
+	StuckLockThreshold uint32
+
+	// RequireNetwork is the network passphrase mutating methods must run
+	// against. When set, they return ErrWrongNetwork before submitting
+	// anything if the client is connected elsewhere.
+	RequireNetwork string
+
+	// AuditLogger, if set, records each privileged operation after it is
+	// submitted
+	AuditLogger AuditLogger
+}
+
+// NewMaintenanceClient creates a new maintenance client
+func NewMaintenanceClient(client *Client, txBuilder *TransactionBuilder, contractAddress string) *MaintenanceClient {
+	return &MaintenanceClient{
+		client:             client,
+		txBuilder:          txBuilder,
+		contractAddress:    contractAddress,
+		StuckLockThreshold: DefaultStuckLockThreshold,
+	}
+}
+
+// GetReentrancyLockStatus reports whether the contract's reentrancy guard is
+// held and the ledger it was acquired at (read-only, uses RPC simulation)
+func (mc *MaintenanceClient) GetReentrancyLockStatus(ctx context.Context) (locked bool, sinceLedger uint32, err error) {
+	locked, sinceLedger, _, err = mc.reentrancyLockStatus(ctx)
+	return locked, sinceLedger, err
+}
+
+// reentrancyLockStatus simulates get_reentrancy_lock, which returns
+// Option<u32> holding the ledger the lock was acquired at. The latest ledger
+// seen by the simulation is returned alongside.
+func (mc *MaintenanceClient) reentrancyLockStatus(ctx context.Context) (locked bool, sinceLedger uint32, latestLedger uint32, err error) {
+	contractAddr, err := EncodeContractAddress(mc.contractAddress)
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "get_reentrancy_lock", []xdr.ScVal{})
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := mc.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("failed to get reentrancy lock status: %w", err)
+	}
+
+	ret, err := ParseReturnValue(sim)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	if ret.Type == xdr.ScValTypeScvVoid {
+		return false, 0, sim.LatestLedger, nil
+	}
+
+	sinceLedger, err = DecodeScValUint32(ret)
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("invalid reentrancy lock value: %w", err)
+	}
+
+	return true, sinceLedger, sim.LatestLedger, nil
+}
+
+// ClearReentrancyLock releases a stuck reentrancy guard (admin only). It
+// returns ErrNoStuckLock if the lock is not held and ErrLockNotStuck if it has
+// been held for fewer than StuckLockThreshold ledgers. If adminKey is nil the
+// transaction builder's source account signs.
+func (mc *MaintenanceClient) ClearReentrancyLock(ctx context.Context, adminKey *keypair.Full) error {
+	if err := mc.client.requireNetwork(mc.RequireNetwork); err != nil {
+		return err
+	}
+
+	locked, sinceLedger, latestLedger, err := mc.reentrancyLockStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkStuckLock(locked, sinceLedger, latestLedger, mc.StuckLockThreshold); err != nil {
+		return err
+	}
+
+	mc.client.LogContractInteraction(mc.contractAddress, "clear_reentrancy_lock", map[string]interface{}{
+		"since_ledger":  sinceLedger,
+		"latest_ledger": latestLedger,
+	})
+
+	contractAddr, err := EncodeContractAddress(mc.contractAddress)
+	if err != nil {
+		return fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "clear_reentrancy_lock", []xdr.ScVal{})
+	if err != nil {
+		return fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	txBuilder := mc.txBuilder.withSigner(adminKey)
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return fmt.Errorf("failed to clear reentrancy lock: %w", err)
+	}
+
+	recordAudit(ctx, mc.AuditLogger, AuditEntry{
+		Operation: "clear_reentrancy_lock",
+		Contract:  mc.contractAddress,
+		Signer:    txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"since_ledger": sinceLedger},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
+	if _, err := txBuilder.WaitForConfirmation(ctx, result.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+	}
+
+	return nil
+}
+
+// checkStuckLock returns nil only if the lock is held and has been held for at
+// least threshold ledgers
+func checkStuckLock(locked bool, sinceLedger, latestLedger, threshold uint32) error {
+	if !locked {
+		return ErrNoStuckLock
+	}
+
+	heldFor := uint32(0)
+	if latestLedger > sinceLedger {
+		heldFor = latestLedger - sinceLedger
+	}
+	if heldFor < threshold {
+		return fmt.Errorf("%w: held for %d ledgers, threshold is %d", ErrLockNotStuck, heldFor, threshold)
+	}
+	return nil
+}
+
+// GetAdmin retrieves the contract's admin address (read-only, uses RPC simulation)
+func (mc *MaintenanceClient) GetAdmin(ctx context.Context) (string, error) {
+	contractAddr, err := EncodeContractAddress(mc.contractAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "get_admin", []xdr.ScVal{})
+	if err != nil {
+		return "", fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := mc.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return "", fmt.Errorf("failed to get admin: %w", err)
+	}
+
+	ret, err := ParseReturnValue(sim)
+	if err != nil {
+		return "", err
+	}
+
+	admin, err := DecodeScValAddress(ret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode admin address: %w", err)
+	}
+
+	return admin.String(), nil
+}
+
+// TransferAdmin sets a new contract admin via set_admin. The transaction must
+// be signed by the current admin; if currentAdminKey is nil the transaction
+// builder's source account signs.
+func (mc *MaintenanceClient) TransferAdmin(ctx context.Context, newAdmin string, currentAdminKey *keypair.Full) error {
+	if err := mc.client.requireNetwork(mc.RequireNetwork); err != nil {
+		return err
+	}
+
+	mc.client.LogContractInteraction(mc.contractAddress, "set_admin", map[string]interface{}{
+		"new_admin": newAdmin,
+	})
+
+	// Validate the new admin before building anything
+	newAdminVal, err := EncodeScValAddress(newAdmin)
+	if err != nil {
+		return fmt.Errorf("invalid new admin address: %w", err)
+	}
+
+	txBuilder := mc.txBuilder.withSigner(currentAdminKey)
+	if newAdmin == txBuilder.signer.PublicKey() {
+		return fmt.Errorf("new admin %s is already the signing admin", newAdmin)
+	}
+
+	contractAddr, err := EncodeContractAddress(mc.contractAddress)
+	if err != nil {
+		return fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "set_admin", []xdr.ScVal{newAdminVal})
+	if err != nil {
+		return fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return fmt.Errorf("failed to transfer admin: %w", err)
+	}
+
+	recordAudit(ctx, mc.AuditLogger, AuditEntry{
+		Operation: "set_admin",
+		Contract:  mc.contractAddress,
+		Signer:    txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"new_admin": newAdmin},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
+	if _, err := txBuilder.WaitForConfirmation(ctx, result.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+func TestCheckStuckLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  bool
+		since   uint32
+		latest  uint32
+		wantErr error
+	}{
+		{"not locked", false, 0, 100, ErrNoStuckLock},
+		{"recently acquired", true, 95, 100, ErrLockNotStuck},
+		{"at threshold", true, 88, 100, nil},
+		{"well past threshold", true, 10, 100, nil},
+		{"acquired after latest", true, 105, 100, ErrLockNotStuck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStuckLock(tt.locked, tt.since, tt.latest, DefaultStuckLockThreshold)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTransferAdmin_InvalidAddress(t *testing.T) {
+	mc := NewMaintenanceClient(&Client{}, nil, "0000000000000000000000000000000000000000000000000000000000000000")
+
+	if err := mc.TransferAdmin(context.Background(), "not-an-address", nil); err == nil {
+		t.Error("expected error for malformed admin address")
+	}
+}
+
+func TestTransferAdmin_WrongNetwork(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://localhost", Network: NetworkTestnet})
+	mc := NewMaintenanceClient(client, nil, "0000000000000000000000000000000000000000000000000000000000000000")
+	mc.RequireNetwork = network.PublicNetworkPassphrase
+
+	err := mc.TransferAdmin(context.Background(), keypair.MustRandom().Address(), nil)
+	if !errors.Is(err, ErrWrongNetwork) {
+		t.Fatalf("expected ErrWrongNetwork, got %v", err)
+	}
+	if err := mc.ClearReentrancyLock(context.Background(), nil); !errors.Is(err, ErrWrongNetwork) {
+		t.Fatalf("expected ErrWrongNetwork, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package soroban
+
+// MetricLabelOther is the label reported for operations outside
+// metricOperations
+const MetricLabelOther = "other"
+
+// metricOperations is the closed set of contract operations that may appear
+// as a metric label. Keep it in sync with the functions the contract clients
+// invoke.
+var metricOperations = map[string]bool{
+	// Escrow
+	"lock_funds":      true,
+	"release_funds":   true,
+	"refund":          true,
+	"get_escrow_info": true,
+
+	// Program escrow
+	"init_program":       true,
+	"lock_program_funds": true,
+	"single_payout":      true,
+	"batch_payout":       true,
+
+	// Administration and upgrades
+	"init":                        true,
+	"get_admin":                   true,
+	"set_admin":                   true,
+	"get_feature_flags":           true,
+	"set_feature_flag":            true,
+	"get_reentrancy_lock":         true,
+	"clear_reentrancy_lock":       true,
+	"get_upgrade_safety_status":   true,
+	"set_upgrade_safety":          true,
+	"simulate_upgrade":            true,
+	"upgrade":                     true,
+	"get_version":                 true,
+	"get_version_numeric_encoded": true,
+
+	// Token
+	"balance": true,
+}
+
+// MetricLabel maps an operation name to a bounded metric label. Unknown
+// names collapse to MetricLabelOther so a caller passing an identifier by
+// mistake can't grow label cardinality. Bounty ids, addresses and
+// transaction hashes belong in logs or exemplars, never in labels.
+func MetricLabel(op string) string {
+	if metricOperations[op] {
+		return op
+	}
+	return MetricLabelOther
+}
@@ -0,0 +1,26 @@
+package soroban
+
+import "testing"
+
+func TestMetricLabel(t *testing.T) {
+	cases := map[string]string{
+		"lock_funds":    "lock_funds",
+		"upgrade":       "upgrade",
+		"":              MetricLabelOther,
+		"lock_funds:42": MetricLabelOther,
+		"GABC":          MetricLabelOther,
+	}
+	for op, want := range cases {
+		if got := MetricLabel(op); got != want {
+			t.Errorf("MetricLabel(%q) = %q, want %q", op, got, want)
+		}
+	}
+}
+
+func TestMetricLabel_CoversShadowOperations(t *testing.T) {
+	for op := range KnownShadowOperations {
+		if MetricLabel(op) != op {
+			t.Errorf("shadowed operation %q has no metric label", op)
+		}
+	}
+}
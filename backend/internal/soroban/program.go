@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
@@ -15,15 +14,43 @@ type ProgramEscrowContract struct {
 	client          *Client
 	txBuilder       *TransactionBuilder
 	contractAddress string
+
+	// BatchChunkSize is the number of payouts BatchPayoutChunked sends per
+	// transaction, and the fallback when adaptive sizing can't simulate
+	BatchChunkSize int
+	// AdaptiveChunking sizes chunks from the simulated resource cost of a
+	// small probe batch instead of using BatchChunkSize
+	AdaptiveChunking bool
+	// ResourceLimits are the per-transaction limits adaptive chunks must fit
+	ResourceLimits ResourceLimits
+	// ChunkSafetyMargin is the fraction of ResourceLimits an adaptive chunk
+	// may use, leaving headroom for per-item cost variance
+	ChunkSafetyMargin float64
+
+	// TokenAddress is the token contract the program pays out in. It is
+	// only needed by SinglePayoutVerified.
+	TokenAddress string
+
+	// FunctionNames remaps host function names for contracts that export
+	// them under other names, e.g. {"batch_payout": "batch_payout_v2"}
+	FunctionNames map[string]string
 }
 
-// NewProgramEscrowContract creates a new program escrow contract client
-func NewProgramEscrowContract(client *Client, txBuilder *TransactionBuilder, contractAddress string) *ProgramEscrowContract {
-	return &ProgramEscrowContract{
-		client:          client,
-		txBuilder:       txBuilder,
-		contractAddress: contractAddress,
+// NewProgramEscrowContract creates a new program escrow contract client. It
+// returns an error if contractAddress is malformed.
+func NewProgramEscrowContract(client *Client, txBuilder *TransactionBuilder, contractAddress string) (*ProgramEscrowContract, error) {
+	if err := ValidateContractAddress(contractAddress); err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
 	}
+
+	return &ProgramEscrowContract{
+		client:            client,
+		txBuilder:         txBuilder,
+		contractAddress:   contractAddress,
+		BatchChunkSize:    DefaultBatchChunkSize,
+		ResourceLimits:    DefaultResourceLimits,
+		ChunkSafetyMargin: DefaultChunkSafetyMargin,
+	}, nil
 }
 
 // InitProgram initializes a new program escrow
@@ -106,7 +133,7 @@ func (pec *ProgramEscrowContract) LockProgramFunds(ctx context.Context, amount i
 	}
 
 	// Wait for confirmation
-	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
+	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
 	if err != nil {
 		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
 		return result, nil
@@ -142,7 +169,7 @@ func (pec *ProgramEscrowContract) SinglePayout(ctx context.Context, recipientAdd
 	args := []xdr.ScVal{recipientVal, amountVal}
 
 	// Build InvokeHostFunction operation
-	op, err := BuildInvokeHostFunctionOp(contractAddr, "single_payout", args)
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(pec.FunctionNames, "single_payout"), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build operation: %w", err)
 	}
@@ -154,7 +181,7 @@ func (pec *ProgramEscrowContract) SinglePayout(ctx context.Context, recipientAdd
 	}
 
 	// Wait for confirmation
-	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
+	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
 	if err != nil {
 		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
 		return result, nil
@@ -163,10 +190,58 @@ func (pec *ProgramEscrowContract) SinglePayout(ctx context.Context, recipientAdd
 	return confirmed, nil
 }
 
+// SinglePayoutVerified executes SinglePayout and then checks that the
+// recipient's TokenAddress balance grew by exactly amount, returning
+// ErrPayoutNotReflected otherwise. It costs two extra balance reads.
+//
+// Verification is best-effort: a concurrent transfer to or from the recipient
+// between the two reads shows up as a mismatch.
+func (pec *ProgramEscrowContract) SinglePayoutVerified(ctx context.Context, recipientAddress string, amount int64) (*TransactionResult, error) {
+	if pec.TokenAddress == "" {
+		return nil, fmt.Errorf("token address is required to verify payouts")
+	}
+	token := NewTokenContract(pec.client, pec.txBuilder, pec.TokenAddress)
+
+	before, err := token.Balance(ctx, recipientAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balance before payout: %w", err)
+	}
+
+	result, err := pec.SinglePayout(ctx, recipientAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := token.Balance(ctx, recipientAddress)
+	if err != nil {
+		return result, fmt.Errorf("failed to read balance after payout: %w", err)
+	}
+
+	if err := checkBalanceDelta(before, after, amount); err != nil {
+		slog.Error("payout not reflected in recipient balance",
+			"recipient", recipientAddress,
+			"amount", amount,
+			"balance_before", before,
+			"balance_after", after,
+			"tx_hash", result.Hash,
+		)
+		return result, err
+	}
+	return result, nil
+}
+
+// checkBalanceDelta returns ErrPayoutNotReflected unless after-before == amount
+func checkBalanceDelta(before, after, amount int64) error {
+	if delta := after - before; delta != amount {
+		return fmt.Errorf("%w: expected +%d, balance changed by %d", ErrPayoutNotReflected, amount, delta)
+	}
+	return nil
+}
+
 // BatchPayout executes payouts to multiple recipients
 type PayoutItem struct {
-	Recipient string
-	Amount    int64
+	Recipient string `json:"recipient"`
+	Amount    int64  `json:"amount"`
 }
 
 func (pec *ProgramEscrowContract) BatchPayout(ctx context.Context, payouts []PayoutItem) (*TransactionResult, error) {
@@ -174,6 +249,99 @@ func (pec *ProgramEscrowContract) BatchPayout(ctx context.Context, payouts []Pay
 		"payout_count": len(payouts),
 	})
 
+	if len(payouts) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d payouts, limit is %d", ErrBatchTooLarge, len(payouts), MaxBatchSize)
+	}
+
+	op, err := pec.batchPayoutOp(payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build and submit transaction
+	result, err := pec.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	// Wait for confirmation
+	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 0)
+	if err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+		return result, nil
+	}
+
+	return confirmed, nil
+}
+
+// BatchPayoutChunked splits payouts across as many batch_payout transactions
+// as needed and submits them in order. On failure it returns the results of
+// the chunks already submitted along with the error.
+func (pec *ProgramEscrowContract) BatchPayoutChunked(ctx context.Context, payouts []PayoutItem) ([]*TransactionResult, error) {
+	if len(payouts) == 0 {
+		return nil, fmt.Errorf("payouts list cannot be empty")
+	}
+
+	size := pec.chunkSize(ctx, payouts)
+	chunks := (len(payouts) + size - 1) / size
+	results := make([]*TransactionResult, 0, chunks)
+	for i := 0; i < chunks; i++ {
+		end := (i + 1) * size
+		if end > len(payouts) {
+			end = len(payouts)
+		}
+		result, err := pec.BatchPayout(ctx, payouts[i*size:end])
+		if err != nil {
+			return results, fmt.Errorf("batch payout chunk %d of %d failed: %w", i+1, chunks, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// chunkSize returns how many payouts to send per transaction. In adaptive
+// mode the per-item resource cost is measured once per call from a probe
+// simulation and reused for every chunk.
+func (pec *ProgramEscrowContract) chunkSize(ctx context.Context, payouts []PayoutItem) int {
+	fixed := pec.BatchChunkSize
+	if fixed <= 0 {
+		fixed = DefaultBatchChunkSize
+	}
+	fixed = min(fixed, MaxBatchSize)
+	if !pec.AdaptiveChunking {
+		return fixed
+	}
+
+	probe := payouts
+	if len(probe) > chunkProbeSize {
+		probe = probe[:chunkProbeSize]
+	}
+	op, err := pec.batchPayoutOp(probe)
+	if err != nil {
+		slog.Warn("adaptive chunking unavailable, using fixed chunk size", "error", err, "chunk_size", fixed)
+		return fixed
+	}
+	sim, err := pec.txBuilder.simulatePreview(ctx, []txnbuild.Operation{op})
+	if err != nil || sim.Resources == nil {
+		slog.Warn("adaptive chunking unavailable, using fixed chunk size", "error", err, "chunk_size", fixed)
+		return fixed
+	}
+
+	perItem := perItemUsage(*sim.Resources, len(probe))
+	size := min(pec.ResourceLimits.maxItems(perItem, pec.ChunkSafetyMargin), MaxBatchSize)
+	if size < 1 {
+		size = 1
+	}
+	slog.Info("adaptive chunk size computed",
+		"chunk_size", size,
+		"probe_items", len(probe),
+		"instructions_per_item", perItem.Instructions,
+	)
+	return size
+}
+
+// batchPayoutOp builds the batch_payout invocation for payouts
+func (pec *ProgramEscrowContract) batchPayoutOp(payouts []PayoutItem) (txnbuild.Operation, error) {
 	if len(payouts) == 0 {
 		return nil, fmt.Errorf("payouts list cannot be empty")
 	}
@@ -215,25 +383,12 @@ func (pec *ProgramEscrowContract) BatchPayout(ctx context.Context, payouts []Pay
 	args := []xdr.ScVal{recipientsVec, amountsVec}
 
 	// Build InvokeHostFunction operation
-	op, err := BuildInvokeHostFunctionOp(contractAddr, "batch_payout", args)
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(pec.FunctionNames, "batch_payout"), args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build operation: %w", err)
 	}
 
-	// Build and submit transaction
-	result, err := pec.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
-	if err != nil {
-		return nil, fmt.Errorf("failed to submit transaction: %w", err)
-	}
-
-	// Wait for confirmation
-	confirmed, err := pec.txBuilder.WaitForConfirmation(ctx, result.Hash, 60*time.Second)
-	if err != nil {
-		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
-		return result, nil
-	}
-
-	return confirmed, nil
+	return op, nil
 }
 
 // GetProgramInfo retrieves program information (read-only)
@@ -0,0 +1,50 @@
+package soroban
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+)
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP date. Dates in the past yield a zero delay.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if d := at.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// RetryAfter returns the backoff a rate-limited (HTTP 429) RPC or Horizon
+// response asked for. Call and transaction submission already wait for it
+// when they can; this reports the delay of a rate-limit error they returned
+// instead, e.g. because the caller's deadline was too close.
+func RetryAfter(err error) (time.Duration, bool) {
+	var epErr *endpointError
+	if errors.As(err, &epErr) && epErr.retryAfter > 0 {
+		return epErr.retryAfter, true
+	}
+	var herr *horizonclient.Error
+	if errors.As(err, &herr) && herr.Response != nil && herr.Response.StatusCode == http.StatusTooManyRequests {
+		return parseRetryAfter(herr.Response.Header.Get("Retry-After"), time.Now())
+	}
+	return 0, false
+}
@@ -0,0 +1,121 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"3", 3 * time.Second, true},
+		{" 0 ", 0, true},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := parseRetryAfter(c.header, now)
+		if got != c.want || ok != c.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", c.header, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestRetryAfter_RPCRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	// The deadline is shorter than the Retry-After, so the call doesn't wait
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	_, err := client.GetLatestLedger(ctx)
+	if err == nil {
+		t.Fatal("expected rate-limited call to fail")
+	}
+	if wait, ok := RetryAfter(err); !ok || wait != 3*time.Second {
+		t.Errorf("expected a 3s retry-after, got %v, %v", wait, ok)
+	}
+}
+
+func TestCall_WaitsForRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":42}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	start := time.Now()
+	if _, err := client.GetLatestLedger(context.Background()); err != nil {
+		t.Fatalf("expected the read to succeed after the Retry-After, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the read to wait out the 1s Retry-After, took %v", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("expected one retry, got %d calls", calls)
+	}
+}
+
+func TestSubmitWithRetry_HonorsRetryAfter(t *testing.T) {
+	var submissions int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&submissions, 1)
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.InitialDelay = time.Millisecond
+	rc.DisableJitter = true
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	kp := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	// Without the header the retries would all run within the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := tb.submitWithRetry(ctx, tx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected to still be backing off at the deadline, got %v", err)
+	}
+	if n := atomic.LoadInt32(&submissions); n != 1 {
+		t.Errorf("expected a single submission within the retry-after window, got %d", n)
+	}
+}
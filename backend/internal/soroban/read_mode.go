@@ -0,0 +1,217 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ReadMode selects how EscrowContract reads contract storage
+type ReadMode int
+
+const (
+	// ReadModeAuto reads ledger entries directly. It switches to simulated
+	// view calls for good once the RPC rejects getLedgerEntries as
+	// unsupported, and for a minute at a time while getLedgerEntries keeps
+	// failing for other reasons, e.g. 5xx responses or timeouts
+	ReadModeAuto ReadMode = iota
+	// ReadModeLedgerEntries always reads ledger entries directly
+	ReadModeLedgerEntries
+	// ReadModeSimulate always reads through simulated view calls, for RPCs
+	// that don't serve getLedgerEntries
+	ReadModeSimulate
+)
+
+// ledgerEntriesFailureLimit is the number of consecutive failed
+// getLedgerEntries calls after which ReadModeAuto falls back to simulation
+const ledgerEntriesFailureLimit = 3
+
+// ledgerEntriesCooldown is how long ReadModeAuto simulates reads after
+// repeated getLedgerEntries failures before trying getLedgerEntries again
+const ledgerEntriesCooldown = time.Minute
+
+// escrowStatuses are the contract's EscrowStatus variants
+var escrowStatuses = []string{"Locked", "Released", "Refunded", "PartiallyRefunded"}
+
+// readFallback remembers whether ReadModeAuto has given up on getLedgerEntries
+type readFallback struct {
+	simulate atomic.Bool  // the RPC doesn't serve getLedgerEntries
+	failures atomic.Int32 // consecutive failed calls
+	until    atomic.Int64 // Unix nanoseconds until which reads are simulated
+}
+
+// simulatedReads reports whether storage reads should go through simulation
+func (ec *EscrowContract) simulatedReads() bool {
+	switch ec.ReadMode {
+	case ReadModeSimulate:
+		return true
+	case ReadModeAuto:
+		return ec.reads.simulate.Load() || time.Now().UnixNano() < ec.reads.until.Load()
+	default:
+		return false
+	}
+}
+
+// entriesReadFailed records a failed getLedgerEntries call and reports
+// whether the read should be retried through simulation. In ReadModeAuto an
+// unsupported method switches every later read to simulation, while
+// ledgerEntriesFailureLimit other failures in a row only do so for
+// ledgerEntriesCooldown: those may be transient, so getLedgerEntries is
+// tried again afterwards, and one more failure restarts the cooldown.
+func (ec *EscrowContract) entriesReadFailed(ctx context.Context, err error) bool {
+	if ec.ReadMode != ReadModeAuto || ctx.Err() != nil {
+		return false
+	}
+	if isMethodUnsupported(err) {
+		if ec.reads.simulate.CompareAndSwap(false, true) {
+			slog.Warn("getLedgerEntries unsupported, falling back to simulated reads",
+				"contract", ec.contractAddress,
+				"error", err,
+			)
+		}
+		return true
+	}
+	if ec.reads.failures.Add(1) < ledgerEntriesFailureLimit {
+		return false
+	}
+	ec.reads.until.Store(time.Now().Add(ledgerEntriesCooldown).UnixNano())
+	slog.Warn("getLedgerEntries failing, simulating reads until it is retried",
+		"contract", ec.contractAddress,
+		"retry_in", ledgerEntriesCooldown,
+		"error", err,
+	)
+	return true
+}
+
+// entriesReadSucceeded resets the consecutive failure count
+func (ec *EscrowContract) entriesReadSucceeded() {
+	ec.reads.failures.Store(0)
+}
+
+// isMethodUnsupported reports whether err is the RPC rejecting the method
+// itself rather than the request
+func isMethodUnsupported(err error) bool {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == -32601 {
+		return true
+	}
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "method not found")
+}
+
+// isBountyNotFound reports whether err is the contract's BountyNotFound (#4)
+func isBountyNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Error(Contract, #4)")
+}
+
+// simulateView simulates a read-only call to fn and returns its value
+func (ec *EscrowContract) simulateView(ctx context.Context, fn string, args ...xdr.ScVal) (xdr.ScVal, error) {
+	contractAddr, err := EncodeContractAddress(ec.contractAddress)
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, hostFunction(ec.FunctionNames, fn), args)
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := ec.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	return ParseReturnValue(sim)
+}
+
+// simulateBountyIDs lists every bounty through get_escrow_ids_by_status. The
+// contract has no view of the whole index, so the IDs are sorted rather than
+// in index order.
+func (ec *EscrowContract) simulateBountyIDs(ctx context.Context) ([]uint64, error) {
+	offset, err := EncodeScValUint32(0)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := EncodeScValUint32(^uint32(0))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uint64{}
+	for _, status := range escrowStatuses {
+		ret, err := ec.simulateView(ctx, "get_escrow_ids_by_status", EnumKey(status), offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s escrows: %w", status, err)
+		}
+		batch, err := decodeUint64Vec(ret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s escrow ids: %w", status, err)
+		}
+		ids = append(ids, batch...)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// simulateEscrows reads each bounty's escrow through get_escrow_info,
+// recording failures on the affected bounties' states like readEscrows
+func (ec *EscrowContract) simulateEscrows(ctx context.Context, bountyIDs []uint64, states map[uint64]escrowState) {
+	for _, id := range bountyIDs {
+		idVal, err := EncodeScValUint64(id)
+		if err != nil {
+			states[id] = escrowState{err: fmt.Errorf("failed to encode bounty_id: %w", err)}
+			continue
+		}
+		ret, err := ec.simulateView(ctx, "get_escrow_info", idVal)
+		switch {
+		case isBountyNotFound(err):
+			states[id] = escrowState{}
+			continue
+		case err != nil:
+			states[id] = escrowState{err: fmt.Errorf("bounty %d: failed to read escrow: %w", id, err)}
+			continue
+		}
+		state, err := decodeEscrowState(ret)
+		if err != nil {
+			state = escrowState{err: fmt.Errorf("bounty %d: failed to decode escrow: %w", id, err)}
+		}
+		states[id] = state
+	}
+}
+
+// simulatePendingClaims reads each bounty's claim through get_pending_claim.
+// Simulation doesn't say when a claim was written, so ClaimedAtLedger is left
+// zero.
+func (ec *EscrowContract) simulatePendingClaims(ctx context.Context, bountyIDs []uint64) (map[uint64][]Claim, error) {
+	claims := make(map[uint64][]Claim, len(bountyIDs))
+	for _, id := range bountyIDs {
+		claims[id] = []Claim{}
+		idVal, err := EncodeScValUint64(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode bounty_id: %w", err)
+		}
+		ret, err := ec.simulateView(ctx, "get_pending_claim", idVal)
+		if isBountyNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pending claims: %w", err)
+		}
+
+		claim, pending, err := decodeClaimRecord(ret)
+		if err != nil {
+			return nil, fmt.Errorf("bounty %d: failed to decode claim: %w", id, err)
+		}
+		if pending {
+			claim.BountyID = id
+			claims[id] = append(claims[id], claim)
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,125 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// noEntriesServer rejects getLedgerEntries with entriesError and simulates
+// every call as returning ret
+func noEntriesServer(t *testing.T, entriesError string, ret xdr.ScVal) *fakeRPC {
+	t.Helper()
+	retXDR, _ := xdr.MarshalBase64(ret)
+	srv := newFakeRPC(t)
+	srv.fail("getLedgerEntries", entriesError)
+	srv.answerAll(fmt.Sprintf(`{"latestLedger":5,"results":[{"xdr":%q}]}`, retXDR))
+	return srv
+}
+
+func testReadModeEscrow(t *testing.T, rpcURL string, mode ReadMode) *EscrowContract {
+	t.Helper()
+	client, _ := NewClient(Config{RPCURL: rpcURL})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	tb.account.prewarm(&txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey(), Sequence: 1})
+	ec, err := NewEscrowContract(client, tb, testContractHex)
+	if err != nil {
+		t.Fatalf("NewEscrowContract failed: %v", err)
+	}
+	ec.ReadMode = mode
+	return ec
+}
+
+func TestReadModeAuto_FallsBackWhenMethodUnsupported(t *testing.T) {
+	srv := noEntriesServer(t, `{"code":-32601,"message":"method not found"}`, escrowVal(t, "Locked", 400))
+	ec := testReadModeEscrow(t, srv.URL, ReadModeAuto)
+
+	for i := 0; i < 2; i++ {
+		states, err := ec.readEscrowStates(context.Background(), []uint64{1})
+		if err != nil {
+			t.Fatalf("readEscrowStates failed: %v", err)
+		}
+		if s := states[1]; !s.found || s.status != "Locked" || s.remaining != 400 {
+			t.Errorf("unexpected state %+v", s)
+		}
+	}
+	// The fallback is remembered, so only the first read tries getLedgerEntries
+	if n := srv.callCount("getLedgerEntries"); n != 1 {
+		t.Errorf("expected 1 getLedgerEntries call, got %d", n)
+	}
+}
+
+func TestReadModeAuto_FallsBackAfterRepeatedFailures(t *testing.T) {
+	srv := noEntriesServer(t, `{"code":-32000,"message":"internal error"}`, NewScValVec())
+	ec := testReadModeEscrow(t, srv.URL, ReadModeAuto)
+
+	for i := 1; i < ledgerEntriesFailureLimit; i++ {
+		if _, _, err := ec.ListBounties(context.Background(), "", 0); err == nil {
+			t.Fatalf("read %d: expected the getLedgerEntries error", i)
+		}
+	}
+	ids, _, err := ec.ListBounties(context.Background(), "", 0)
+	if err != nil {
+		t.Fatalf("expected a simulated read, got %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no bounties, got %v", ids)
+	}
+	if !ec.simulatedReads() {
+		t.Error("expected later reads to be simulated")
+	}
+}
+
+func TestReadModeAuto_TransientFailuresDontLatch(t *testing.T) {
+	srv := noEntriesServer(t, `{"code":-32000,"message":"internal error"}`, NewScValVec())
+	ec := testReadModeEscrow(t, srv.URL, ReadModeAuto)
+
+	for i := 0; i < ledgerEntriesFailureLimit; i++ {
+		_, _, _ = ec.ListBounties(context.Background(), "", 0)
+	}
+	if !ec.simulatedReads() || ec.reads.simulate.Load() {
+		t.Fatal("expected repeated failures to simulate reads for the cooldown only")
+	}
+
+	// getLedgerEntries recovers and the cooldown runs out
+	srv.fail("getLedgerEntries", "")
+	srv.answer("getLedgerEntries", `{"entries":[],"latestLedger":5}`)
+	ec.reads.until.Store(time.Now().Add(-time.Second).UnixNano())
+	before := srv.callCount("getLedgerEntries")
+
+	if _, _, err := ec.ListBounties(context.Background(), "", 0); err != nil {
+		t.Fatalf("ListBounties failed: %v", err)
+	}
+	if srv.callCount("getLedgerEntries") == before {
+		t.Error("expected getLedgerEntries to be tried again after the cooldown")
+	}
+	if ec.simulatedReads() {
+		t.Error("expected a successful read to stay on getLedgerEntries")
+	}
+}
+
+func TestReadModeLedgerEntries_DoesNotFallBack(t *testing.T) {
+	srv := noEntriesServer(t, `{"code":-32601,"message":"method not found"}`, escrowVal(t, "Locked", 400))
+	ec := testReadModeEscrow(t, srv.URL, ReadModeLedgerEntries)
+
+	if _, err := ec.readEscrowStates(context.Background(), []uint64{1}); err == nil {
+		t.Error("expected the getLedgerEntries error")
+	}
+	if ec.simulatedReads() {
+		t.Error("ReadModeLedgerEntries should never simulate")
+	}
+}
+
+func TestIsMethodUnsupported(t *testing.T) {
+	if !isMethodUnsupported(fmt.Errorf("RPC error: %w", &RPCError{Code: -32601, Message: "nope"})) {
+		t.Error("expected code -32601 to be unsupported")
+	}
+	if isMethodUnsupported(fmt.Errorf("RPC error: %w", &RPCError{Code: -32602, Message: "invalid params"})) {
+		t.Error("expected invalid params to be supported")
+	}
+}
@@ -0,0 +1,79 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ReconcileMismatch is a bounty whose on-chain amount differs from the
+// expected one
+type ReconcileMismatch struct {
+	BountyID uint64 `json:"bounty_id"`
+	Expected int64  `json:"expected"`
+	// Actual is the escrow's remaining amount, zero if it wasn't found
+	Actual int64 `json:"actual"`
+	Found  bool  `json:"found"`
+}
+
+// ReconcileReport compares expected escrow balances with the chain
+type ReconcileReport struct {
+	Matched int `json:"matched"`
+	// OverFunded lists bounties holding more than expected
+	OverFunded []ReconcileMismatch `json:"over_funded"`
+	// UnderFunded lists bounties holding less than expected, including
+	// bounties missing on chain
+	UnderFunded []ReconcileMismatch `json:"under_funded"`
+	// Errors holds bounties whose escrow couldn't be read or decoded
+	Errors map[uint64]string `json:"errors,omitempty"`
+}
+
+// Consistent reports whether every bounty was read and matched
+func (r ReconcileReport) Consistent() bool {
+	return len(r.OverFunded) == 0 && len(r.UnderFunded) == 0 && len(r.Errors) == 0
+}
+
+// Reconcile compares the remaining amount of each bounty's escrow with
+// expected, e.g. balances recorded in the database. Escrows are read in
+// batches through getLedgerEntries; a bounty that can't be read is listed
+// in Errors rather than failing the reconcile. Mismatches are sorted by
+// bounty ID.
+func (ec *EscrowContract) Reconcile(ctx context.Context, expected map[uint64]int64) (ReconcileReport, error) {
+	report := ReconcileReport{}
+	if len(expected) == 0 {
+		return report, fmt.Errorf("expected balances cannot be empty")
+	}
+
+	ids := make([]uint64, 0, len(expected))
+	for id := range expected {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	states, err := ec.readEscrows(ctx, ids)
+	if err != nil {
+		return report, err
+	}
+
+	for _, id := range ids {
+		state := states[id]
+		if state.err != nil {
+			if report.Errors == nil {
+				report.Errors = make(map[uint64]string)
+			}
+			report.Errors[id] = state.err.Error()
+			continue
+		}
+
+		m := ReconcileMismatch{BountyID: id, Expected: expected[id], Actual: state.remaining, Found: state.found}
+		switch {
+		case m.Actual > m.Expected:
+			report.OverFunded = append(report.OverFunded, m)
+		case m.Actual < m.Expected:
+			report.UnderFunded = append(report.UnderFunded, m)
+		default:
+			report.Matched++
+		}
+	}
+	return report, nil
+}
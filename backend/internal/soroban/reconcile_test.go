@@ -0,0 +1,43 @@
+package soroban
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: escrowEntryServer(t, 1, escrowVal(t, "Locked", 400)).URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	report, err := ec.Reconcile(context.Background(), map[uint64]int64{1: 300, 2: 50})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(report.OverFunded) != 1 || report.OverFunded[0].BountyID != 1 || report.OverFunded[0].Actual != 400 {
+		t.Errorf("expected bounty 1 over-funded by 100, got %+v", report.OverFunded)
+	}
+	if len(report.UnderFunded) != 1 || report.UnderFunded[0].BountyID != 2 || report.UnderFunded[0].Found {
+		t.Errorf("expected missing bounty 2 under-funded, got %+v", report.UnderFunded)
+	}
+	if report.Consistent() {
+		t.Error("expected the report to be inconsistent")
+	}
+
+	report, err = ec.Reconcile(context.Background(), map[uint64]int64{1: 400})
+	if err != nil || !report.Consistent() || report.Matched != 1 {
+		t.Errorf("expected a match, got %+v, %v", report, err)
+	}
+}
+
+func TestReconcile_RecordsReadErrors(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: failingServer(t).URL})
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+
+	report, err := ec.Reconcile(context.Background(), map[uint64]int64{1: 100, 2: 200})
+	if err != nil {
+		t.Fatalf("expected per-bounty errors, got %v", err)
+	}
+	if len(report.Errors) != 2 || report.Matched != 0 {
+		t.Errorf("expected both bounties to be listed as errors, got %+v", report)
+	}
+}
@@ -0,0 +1,180 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// RefundPreview is what Refund would do for a bounty, for showing an operator
+// before they sign
+type RefundPreview struct {
+	BountyID uint64 `json:"bounty_id"`
+	// Destination is the address the refund would pay: the depositor, or the
+	// recipient of an admin refund approval
+	Destination string `json:"destination"`
+	// Amount is what would be refunded: the escrow's remaining amount, or
+	// the approved amount
+	Amount int64        `json:"amount"`
+	Status EscrowStatus `json:"status"`
+	// Deadline is the escrow's refund deadline in unix seconds
+	Deadline       int64 `json:"deadline"`
+	DeadlinePassed bool  `json:"deadline_passed"`
+	// Approved is true when an admin refund approval sets the destination
+	// and amount, allowing a refund before the deadline
+	Approved bool `json:"approved"`
+	// Eligible is false when the contract would reject the refund; Reason
+	// says why
+	Eligible bool   `json:"eligible"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// refundApproval is the part of a stored RefundApproval the client reads
+type refundApproval struct {
+	amount    int64
+	recipient string
+}
+
+// PreviewRefund reports where a Refund of bountyID would send funds and how
+// much, and whether the contract would accept it, without submitting
+// anything. Eligibility follows the contract's refund rules: the escrow must
+// still hold funds, have no pending claim, and either be past its deadline
+// or carry an admin approval. The deadline is compared with the latest
+// ledger's close time, as the contract does. Pauses are not checked, so an
+// eligible refund can still fail while refunds are paused.
+func (ec *EscrowContract) PreviewRefund(ctx context.Context, bountyID uint64) (RefundPreview, error) {
+	states, err := ec.readEscrowStates(ctx, []uint64{bountyID})
+	if err != nil {
+		return RefundPreview{}, err
+	}
+	state := states[bountyID]
+	if !state.found {
+		return RefundPreview{}, fmt.Errorf("bounty %d not found", bountyID)
+	}
+
+	claims, err := ec.readPendingClaims(ctx, []uint64{bountyID})
+	if err != nil {
+		return RefundPreview{}, err
+	}
+	approval, err := ec.readRefundApproval(ctx, bountyID)
+	if err != nil {
+		return RefundPreview{}, err
+	}
+	now, err := ec.client.LatestLedgerCloseTime(ctx)
+	if err != nil {
+		return RefundPreview{}, fmt.Errorf("failed to get ledger close time: %w", err)
+	}
+
+	return newRefundPreview(bountyID, state, len(claims[bountyID]) > 0, approval, now), nil
+}
+
+func newRefundPreview(bountyID uint64, state escrowState, claimPending bool, approval *refundApproval, now time.Time) RefundPreview {
+	p := RefundPreview{
+		BountyID:       bountyID,
+		Destination:    state.depositor,
+		Amount:         state.remaining,
+		Status:         EscrowStatus(state.status),
+		Deadline:       LockForever,
+		DeadlinePassed: uint64(now.Unix()) >= state.deadline,
+		Approved:       approval != nil,
+	}
+	if state.deadline <= math.MaxInt64 {
+		p.Deadline = int64(state.deadline)
+	}
+	if approval != nil {
+		p.Destination = approval.recipient
+		p.Amount = approval.amount
+	}
+
+	switch {
+	case state.status != string(EscrowStatusLocked) && state.status != "PartiallyRefunded":
+		p.Reason = fmt.Sprintf("escrow is %s, not locked", state.status)
+	case claimPending:
+		p.Reason = "a claim is pending"
+	case !p.DeadlinePassed && approval == nil:
+		p.Reason = fmt.Sprintf("deadline %s has not passed", time.Unix(p.Deadline, 0).UTC())
+	case p.Amount <= 0 || p.Amount > state.remaining:
+		p.Reason = fmt.Sprintf("approved amount %d is outside the %d remaining", p.Amount, state.remaining)
+	default:
+		p.Eligible = true
+	}
+	return p
+}
+
+// readRefundApproval reads a bounty's RefundApproval, returning nil if there
+// is none
+func (ec *EscrowContract) readRefundApproval(ctx context.Context, bountyID uint64) (*refundApproval, error) {
+	idVal, err := EncodeScValUint64(bountyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bounty_id: %w", err)
+	}
+	if ec.simulatedReads() {
+		return ec.simulateRefundApproval(ctx, idVal)
+	}
+
+	keys, err := NewLedgerKeyBuilder(ec.contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ec.client.ReadEntries(ctx, []xdr.LedgerKey{keys.Persistent(EnumKey("RefundApproval", idVal))})
+	if err != nil {
+		if ec.entriesReadFailed(ctx, err) {
+			return ec.simulateRefundApproval(ctx, idVal)
+		}
+		return nil, fmt.Errorf("failed to read refund approval: %w", err)
+	}
+	ec.entriesReadSucceeded()
+
+	entry := entries[0]
+	if !entry.Found || entry.Data.ContractData == nil {
+		return nil, nil
+	}
+	return decodeRefundApproval(entry.Data.ContractData.Val)
+}
+
+// simulateRefundApproval reads the approval returned by
+// get_refund_eligibility, whose last element is Option<RefundApproval>
+func (ec *EscrowContract) simulateRefundApproval(ctx context.Context, idVal xdr.ScVal) (*refundApproval, error) {
+	ret, err := ec.simulateView(ctx, "get_refund_eligibility", idVal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read refund approval: %w", err)
+	}
+	vec, ok := ret.GetVec()
+	if !ok || vec == nil || len(*vec) != 4 {
+		return nil, fmt.Errorf("unexpected get_refund_eligibility result %s", ret.Type)
+	}
+	approval := (*vec)[3]
+	if approval.Type == xdr.ScValTypeScvVoid {
+		return nil, nil
+	}
+	return decodeRefundApproval(approval)
+}
+
+// decodeRefundApproval decodes the amount and recipient of a RefundApproval
+func decodeRefundApproval(v xdr.ScVal) (*refundApproval, error) {
+	fields, err := DecodeScValStruct(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode refund approval: %w", err)
+	}
+	amountVal, ok := fields["amount"]
+	if !ok {
+		return nil, fmt.Errorf("missing refund approval field amount")
+	}
+	recipientVal, ok := fields["recipient"]
+	if !ok {
+		return nil, fmt.Errorf("missing refund approval field recipient")
+	}
+
+	amount, err := DecodeScValInt64(amountVal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refund approval amount: %w", err)
+	}
+	recipient, err := decodeScValAddressString(recipientVal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refund approval recipient: %w", err)
+	}
+	return &refundApproval{amount: amount, recipient: recipient}, nil
+}
@@ -0,0 +1,88 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPreviewRefund(t *testing.T) {
+	keys, _ := NewLedgerKeyBuilder(testContractHex)
+	id, _ := EncodeScValUint64(1)
+	depositor := keypair.MustRandom().Address()
+
+	escrow := escrowVal(t, "Locked", 400)
+	depositorVal, _ := EncodeScValAddress(depositor)
+	deadlineVal, _ := EncodeScValUint64(1700000000)
+	depositorKey, _ := EncodeScValSymbol("depositor")
+	deadlineKey, _ := EncodeScValSymbol("deadline")
+	m := *escrow.Map
+	*m = append(*m,
+		xdr.ScMapEntry{Key: depositorKey, Val: depositorVal},
+		xdr.ScMapEntry{Key: deadlineKey, Val: deadlineVal},
+	)
+
+	preview := func(closeTime int64) RefundPreview {
+		t.Helper()
+		srv := newFakeRPC(t)
+		srv.putEntry(keys.Persistent(EnumKey("Escrow", id)), escrow)
+		srv.answer("getLatestLedger", fmt.Sprintf(`{"sequence":100,"closeTime":"%d"}`, closeTime))
+		client, _ := NewClient(Config{RPCURL: srv.URL})
+		ec, _ := NewEscrowContract(client, nil, testContractHex)
+		p, err := ec.PreviewRefund(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("PreviewRefund failed: %v", err)
+		}
+		return p
+	}
+
+	p := preview(1700000000)
+	if !p.Eligible || p.Destination != depositor || p.Amount != 400 || p.Approved {
+		t.Errorf("expected an eligible refund of 400 to the depositor, got %+v", p)
+	}
+
+	p = preview(1699999999)
+	if p.Eligible || p.DeadlinePassed || !strings.Contains(p.Reason, "deadline") {
+		t.Errorf("expected the refund to be ineligible before the deadline, got %+v", p)
+	}
+	if p.Destination != depositor || p.Amount != 400 {
+		t.Errorf("expected an ineligible preview to still show the refund, got %+v", p)
+	}
+}
+
+func TestNewRefundPreview(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	locked := escrowState{found: true, status: "Locked", remaining: 400, depositor: "GDEPOSITOR", deadline: 1800000000}
+	approval := &refundApproval{amount: 150, recipient: "GRECIPIENT"}
+
+	p := newRefundPreview(1, locked, false, approval, now)
+	if !p.Eligible || !p.Approved || p.Destination != "GRECIPIENT" || p.Amount != 150 {
+		t.Errorf("expected an approval to allow an early partial refund, got %+v", p)
+	}
+
+	if p := newRefundPreview(1, locked, true, approval, now); p.Eligible || !strings.Contains(p.Reason, "claim") {
+		t.Errorf("expected a pending claim to block the refund, got %+v", p)
+	}
+
+	refunded := locked
+	refunded.status = "Refunded"
+	if p := newRefundPreview(1, refunded, false, approval, now); p.Eligible || !strings.Contains(p.Reason, "not locked") {
+		t.Errorf("expected a refunded escrow to be ineligible, got %+v", p)
+	}
+
+	over := &refundApproval{amount: 500, recipient: "GRECIPIENT"}
+	if p := newRefundPreview(1, locked, false, over, now); p.Eligible {
+		t.Errorf("expected an approval above the remaining amount to be ineligible, got %+v", p)
+	}
+
+	forever := locked
+	forever.deadline = uint64(LockForever)
+	if p := newRefundPreview(1, forever, false, nil, now); p.Eligible || p.Deadline != LockForever {
+		t.Errorf("expected an escrow locked forever to be ineligible, got %+v", p)
+	}
+}
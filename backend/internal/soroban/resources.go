@@ -0,0 +1,63 @@
+package soroban
+
+import (
+	"strconv"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ResourceUsage breaks down the resources a simulated transaction declares,
+// which determine its resource fee
+type ResourceUsage struct {
+	Instructions  uint32 `json:"instructions"`
+	DiskReadBytes uint32 `json:"disk_read_bytes"`
+	WriteBytes    uint32 `json:"write_bytes"`
+	ReadEntries   uint32 `json:"read_entries"` // Every footprint entry is read
+	WriteEntries  uint32 `json:"write_entries"`
+	// MemoryBytes is the memory the host reported using; zero when the RPC
+	// doesn't return the cost field
+	MemoryBytes uint64 `json:"memory_bytes,omitempty"`
+	ResourceFee int64  `json:"resource_fee"`
+}
+
+// newResourceUsage reads the declared resources from transaction data
+func newResourceUsage(data xdr.SorobanTransactionData) ResourceUsage {
+	res := data.Resources
+	return ResourceUsage{
+		Instructions:  uint32(res.Instructions),
+		DiskReadBytes: uint32(res.DiskReadBytes),
+		WriteBytes:    uint32(res.WriteBytes),
+		ReadEntries:   uint32(len(res.Footprint.ReadOnly) + len(res.Footprint.ReadWrite)),
+		WriteEntries:  uint32(len(res.Footprint.ReadWrite)),
+		ResourceFee:   int64(data.ResourceFee),
+	}
+}
+
+// applyCost fills in fields from the simulateTransaction cost object, which
+// older RPC versions return as stringified numbers
+func (r *ResourceUsage) applyCost(raw map[string]interface{}) {
+	cost, ok := raw["cost"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if s, ok := cost["memBytes"].(string); ok {
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			r.MemoryBytes = n
+		}
+	}
+}
+
+// WithinLimits reports whether the usage fits the network's
+// DefaultResourceLimits
+func (r ResourceUsage) WithinLimits() bool {
+	return r.WithinLimitsOf(DefaultResourceLimits)
+}
+
+// WithinLimitsOf reports whether every resource is within limits
+func (r ResourceUsage) WithinLimitsOf(limits ResourceLimits) bool {
+	return r.Instructions <= limits.Instructions &&
+		r.DiskReadBytes <= limits.DiskReadBytes &&
+		r.WriteBytes <= limits.WriteBytes &&
+		r.ReadEntries <= limits.ReadEntries &&
+		r.WriteEntries <= limits.WriteEntries
+}
@@ -0,0 +1,23 @@
+package soroban
+
+import "testing"
+
+func TestResourceUsage_WithinLimits(t *testing.T) {
+	usage := ResourceUsage{Instructions: 1_000_000, WriteEntries: 3}
+	if !usage.WithinLimits() {
+		t.Error("expected a small transaction to fit the network limits")
+	}
+
+	usage.Instructions = DefaultResourceLimits.Instructions + 1
+	if usage.WithinLimits() {
+		t.Error("expected too many instructions to exceed the limits")
+	}
+
+	limits := ResourceLimits{Instructions: 10, DiskReadBytes: 10, WriteBytes: 10, ReadEntries: 2, WriteEntries: 2}
+	if (ResourceUsage{Instructions: 10, WriteEntries: 3}).WithinLimitsOf(limits) {
+		t.Error("expected too many write entries to exceed the limits")
+	}
+	if !(ResourceUsage{Instructions: 10, ReadEntries: 2}).WithinLimitsOf(limits) {
+		t.Error("expected usage equal to the limits to fit")
+	}
+}
@@ -0,0 +1,70 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudget bounds the retries of a whole submission lifecycle, so the
+// submit and confirm phases share one limit instead of each retrying up to
+// its own. Attach it to a context with WithRetryBudget; a zero field is not
+// enforced.
+type RetryBudget struct {
+	// MaxAttempts caps submission attempts and confirmation polls combined
+	MaxAttempts int
+	// MaxDuration caps the time from WithRetryBudget to the last attempt
+	MaxDuration time.Duration
+}
+
+type retryBudgetKey struct{}
+
+// retryBudget tracks a RetryBudget's use; it is shared by every call made
+// with the context it was attached to
+type retryBudget struct {
+	maxAttempts int32
+	deadline    time.Time
+	attempts    atomic.Int32
+}
+
+// WithRetryBudget returns a context whose BuildAndSubmit and
+// WaitForConfirmation calls draw from b, failing with ErrRetryBudgetExceeded
+// once it is spent. The duration starts counting now.
+func WithRetryBudget(ctx context.Context, b RetryBudget) context.Context {
+	rb := &retryBudget{maxAttempts: int32(b.MaxAttempts)}
+	if b.MaxDuration > 0 {
+		rb.deadline = time.Now().Add(b.MaxDuration)
+	}
+	return context.WithValue(ctx, retryBudgetKey{}, rb)
+}
+
+// spendAttempt takes one attempt for phase from ctx's budget, if it has one
+func spendAttempt(ctx context.Context, phase string) error {
+	rb, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if rb == nil {
+		return nil
+	}
+	if !rb.deadline.IsZero() && !time.Now().Before(rb.deadline) {
+		return fmt.Errorf("%w: out of time before %s", ErrRetryBudgetExceeded, phase)
+	}
+	if rb.maxAttempts > 0 {
+		if n := rb.attempts.Add(1); n > rb.maxAttempts {
+			return fmt.Errorf("%w: %s would be attempt %d of %d", ErrRetryBudgetExceeded, phase, n, rb.maxAttempts)
+		}
+	}
+	return nil
+}
+
+// budgetAllowsWait reports an error if waiting d would outlast ctx's budget,
+// so a backoff that can't be followed by another attempt isn't slept through
+func budgetAllowsWait(ctx context.Context, d time.Duration, phase string) error {
+	rb, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if rb == nil || rb.deadline.IsZero() {
+		return nil
+	}
+	if time.Now().Add(d).After(rb.deadline) {
+		return fmt.Errorf("%w: waiting %s to retry %s would pass the deadline", ErrRetryBudgetExceeded, d, phase)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSpendAttempt(t *testing.T) {
+	if err := spendAttempt(context.Background(), "submission"); err != nil {
+		t.Errorf("expected no limit without a budget, got %v", err)
+	}
+
+	ctx := WithRetryBudget(context.Background(), RetryBudget{MaxAttempts: 2})
+	for i := 0; i < 2; i++ {
+		if err := spendAttempt(ctx, "submission"); err != nil {
+			t.Fatalf("attempt %d: unexpected error %v", i+1, err)
+		}
+	}
+	if err := spendAttempt(ctx, "confirmation"); !errors.Is(err, ErrRetryBudgetExceeded) {
+		t.Errorf("expected ErrRetryBudgetExceeded on the third attempt, got %v", err)
+	}
+}
+
+func TestRetryBudget_Deadline(t *testing.T) {
+	ctx := WithRetryBudget(context.Background(), RetryBudget{MaxDuration: 50 * time.Millisecond})
+	if err := budgetAllowsWait(ctx, time.Second, "submission"); !errors.Is(err, ErrRetryBudgetExceeded) {
+		t.Errorf("expected a wait past the deadline to be refused, got %v", err)
+	}
+	if err := budgetAllowsWait(ctx, time.Millisecond, "submission"); err != nil {
+		t.Errorf("expected a short wait to be allowed, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := spendAttempt(ctx, "submission"); !errors.Is(err, ErrRetryBudgetExceeded) {
+		t.Errorf("expected ErrRetryBudgetExceeded after the deadline, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_RetryBudget(t *testing.T) {
+	srv := newFakeRPC(t)
+	srv.answer("getTransaction", pendingTx)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = 5 * time.Millisecond
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	// One attempt was already spent elsewhere in the lifecycle
+	ctx := WithRetryBudget(context.Background(), RetryBudget{MaxAttempts: 3})
+	_ = spendAttempt(ctx, "submission")
+
+	_, err := tb.WaitForConfirmation(ctx, "abc123", time.Minute)
+	if !errors.Is(err, ErrRetryBudgetExceeded) {
+		t.Fatalf("expected ErrRetryBudgetExceeded, got %v", err)
+	}
+	if n := srv.callCount("getTransaction"); n != 2 {
+		t.Errorf("expected 2 lookups within the budget, got %d", n)
+	}
+}
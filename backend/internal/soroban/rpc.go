@@ -8,7 +8,15 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+
+	"github.com/jagadeesh/grainlify/backend/internal/backoff"
 )
 
 // RPCRequest represents a Soroban RPC JSON-RPC request
@@ -34,8 +42,109 @@ type RPCError struct {
 	Data    string `json:"data,omitempty"`
 }
 
-// Call makes a JSON-RPC call to the Soroban RPC endpoint
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (code: %d)", e.Message, e.Code)
+}
+
+// maxRateLimitedRetries is how many times Call waits out a Retry-After
+// before returning the rate-limit error
+const maxRateLimitedRetries = 2
+
+// maxReadRetryAfter is the longest Retry-After Call waits for; a longer one
+// is returned to the caller instead
+const maxReadRetryAfter = 30 * time.Second
+
+// Call makes a JSON-RPC call to the Soroban RPC endpoint. Connection-level
+// failures fail over to the next configured endpoint. When every endpoint
+// was tried and the last one rate limited the call (HTTP 429), Call waits for
+// its Retry-After and tries again, up to maxRateLimitedRetries times. It
+// doesn't wait if ctx's deadline or retry budget would pass first, or if the
+// delay exceeds maxReadRetryAfter; the error then carries the delay for
+// RetryAfter.
 func (c *Client) Call(ctx context.Context, method string, params interface{}) (*RPCResponse, error) {
+	for retry := 0; ; retry++ {
+		resp, err := c.callEndpoints(ctx, method, params)
+		wait, limited := RetryAfter(err)
+		if !limited || retry >= maxRateLimitedRetries || !waitFits(ctx, wait) {
+			return resp, err
+		}
+		slog.Info("RPC rate limited, waiting for Retry-After",
+			"method", method,
+			"retry_after", wait,
+		)
+		if err := backoff.Wait(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitFits reports whether waiting d still leaves time within ctx's deadline,
+// its retry budget and maxReadRetryAfter
+func waitFits(ctx context.Context, d time.Duration) bool {
+	if d > maxReadRetryAfter {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return false
+	}
+	return budgetAllowsWait(ctx, d, "rpc call") == nil
+}
+
+// callEndpoints tries the call on each endpoint in health order until one
+// answers
+func (c *Client) callEndpoints(ctx context.Context, method string, params interface{}) (*RPCResponse, error) {
+	var lastErr error
+	for _, url := range c.endpointURLs() {
+		resp, err := c.callEndpoint(ctx, url, method, params)
+		if err == nil || !isEndpointFailure(err) {
+			c.recordEndpointSuccess(url)
+			return resp, err
+		}
+
+		// The caller giving up says nothing about the endpoint's health
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.recordEndpointFailure(url, err)
+		lastErr = err
+		slog.Warn("RPC endpoint failed, trying next",
+			"endpoint", url,
+			"method", method,
+			"error", err,
+		)
+	}
+	return nil, lastErr
+}
+
+func (c *Client) recordEndpointSuccess(url string) {
+	if c.endpoints != nil {
+		c.endpoints.recordSuccess(url)
+	}
+}
+
+func (c *Client) recordEndpointFailure(url string, err error) {
+	if c.endpoints != nil {
+		c.endpoints.recordFailure(url, err)
+	}
+}
+
+// callEndpoint makes a JSON-RPC call to a single endpoint, traced and
+// reported to the observer when they are configured
+func (c *Client) callEndpoint(ctx context.Context, url, method string, params interface{}) (*RPCResponse, error) {
+	ctx, span := c.startSpan(ctx, "soroban.rpc "+method,
+		attrRPCMethod.String(method),
+		attrRPCEndpoint.String(url),
+	)
+	start := time.Now()
+	resp, err := c.doCall(ctx, url, method, params)
+	endSpan(span, err)
+	if c.observer != nil {
+		c.observer.OnCall(method, time.Since(start), err)
+	}
+	return resp, err
+}
+
+func (c *Client) doCall(ctx context.Context, url, method string, params interface{}) (*RPCResponse, error) {
 	req := RPCRequest{
 		JSONRPC: "2.0",
 		ID:      1,
@@ -48,7 +157,7 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}) (*
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.rpcURL, bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -57,13 +166,21 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}) (*
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("RPC call failed: %w", err)
+		return nil, &endpointError{err: fmt.Errorf("RPC call failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("RPC call failed with status %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("RPC call failed with status %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			return nil, &endpointError{err: err, status: resp.StatusCode, retryAfter: wait}
+		}
+		if resp.StatusCode >= 500 {
+			return nil, &endpointError{err: err, status: resp.StatusCode}
+		}
+		return nil, err
 	}
 
 	var rpcResp RPCResponse
@@ -72,7 +189,7 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}) (*
 	}
 
 	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("RPC error: %s (code: %d)", rpcResp.Error.Message, rpcResp.Error.Code)
+		return nil, fmt.Errorf("RPC error: %w", rpcResp.Error)
 	}
 
 	return &rpcResp, nil
@@ -97,28 +214,177 @@ func (c *Client) SimulateTransaction(ctx context.Context, txEnvelopeXDR string)
 	return result, nil
 }
 
-// SendTransaction sends a transaction using Soroban RPC
+// SendTransaction sends a transaction using Soroban RPC and returns its hash
+// once the RPC has accepted it as pending. To avoid double-submission it only
+// moves to another endpoint when the submission can't have been processed:
+// the connection was never made or the endpoint rate limited it. If a
+// submission may have reached an endpoint it instead looks the transaction up
+// by hash on the remaining endpoints. A transaction the RPC refuses returns a
+// *SubmitError.
 func (c *Client) SendTransaction(ctx context.Context, txEnvelopeXDR string) (string, error) {
 	params := map[string]interface{}{
 		"transaction": txEnvelopeXDR,
 	}
 
-	resp, err := c.Call(ctx, "sendTransaction", params)
+	urls := c.endpointURLs()
+	var resp *RPCResponse
+	var err error
+	for i, url := range urls {
+		resp, err = c.callEndpoint(ctx, url, "sendTransaction", params)
+		if err == nil || !isEndpointFailure(err) {
+			c.recordEndpointSuccess(url)
+			break
+		}
+
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		c.recordEndpointFailure(url, err)
+		if !notSubmitted(err) {
+			return c.confirmAmbiguousSubmit(ctx, txEnvelopeXDR, urls[i+1:], err)
+		}
+		slog.Warn("RPC endpoint unreachable, submitting to next",
+			"endpoint", url,
+			"error", err,
+		)
+	}
 	if err != nil {
 		return "", err
 	}
 
-	var result map[string]interface{}
+	var result struct {
+		Status         string `json:"status"`
+		Hash           string `json:"hash"`
+		ErrorResultXDR string `json:"errorResultXdr"`
+	}
 	if err := json.Unmarshal(resp.Result, &result); err != nil {
 		return "", fmt.Errorf("failed to unmarshal result: %w", err)
 	}
+	if result.Hash == "" {
+		return "", fmt.Errorf("invalid response: missing hash")
+	}
+
+	switch result.Status {
+	case "PENDING", "DUPLICATE":
+		return result.Hash, nil
+	case "ERROR":
+		submitErr := &SubmitError{TxHash: result.Hash, Status: result.Status}
+		if _, code, err := transactionOutcome(result.ErrorResultXDR); err == nil {
+			submitErr.Code = code
+		}
+		return result.Hash, submitErr
+	default:
+		return result.Hash, &SubmitError{TxHash: result.Hash, Status: result.Status}
+	}
+}
+
+// confirmAmbiguousSubmit checks whether a submission that failed mid-request
+// reached the network by querying the other endpoints for its hash. It never
+// resubmits; if the transaction can't be found ErrSubmissionUnknown is
+// returned together with the hash so the caller can keep polling.
+func (c *Client) confirmAmbiguousSubmit(ctx context.Context, txEnvelopeXDR string, others []string, submitErr error) (string, error) {
+	hash, err := envelopeHash(txEnvelopeXDR, c.networkPassphrase)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSubmissionUnknown, submitErr)
+	}
+
+	for _, url := range others {
+		resp, err := c.callEndpoint(ctx, url, "getTransaction", map[string]interface{}{"hash": hash})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if isEndpointFailure(err) {
+				c.recordEndpointFailure(url, err)
+			}
+			continue
+		}
+		c.recordEndpointSuccess(url)
+
+		var result struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err == nil && result.Status != "" && result.Status != "NOT_FOUND" {
+			slog.Info("ambiguous submission found on another endpoint",
+				"tx_hash", hash,
+				"endpoint", url,
+				"status", result.Status,
+			)
+			return hash, nil
+		}
+	}
 
-	hash, ok := result["transactionHash"].(string)
+	return hash, fmt.Errorf("%w (tx %s): %w", ErrSubmissionUnknown, hash, submitErr)
+}
+
+// envelopeHash computes the hex hash of a base64 transaction envelope
+func envelopeHash(txEnvelopeXDR, passphrase string) (string, error) {
+	generic, err := txnbuild.TransactionFromXDR(txEnvelopeXDR)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	if feeBump, ok := generic.FeeBump(); ok {
+		return feeBump.HashHex(passphrase)
+	}
+	tx, ok := generic.Transaction()
 	if !ok {
-		return "", fmt.Errorf("invalid response: missing transactionHash")
+		return "", fmt.Errorf("unsupported envelope type")
+	}
+	return tx.HashHex(passphrase)
+}
+
+// transactionOutcome decodes a base64 TransactionResult into the fee it
+// charged and its result code, named as Horizon names them, e.g.
+// tx_bad_seq. A failed fee bump reports its inner transaction's code.
+func transactionOutcome(resultXDR string) (int64, string, error) {
+	var res xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXDR, &res); err != nil {
+		return 0, "", fmt.Errorf("failed to decode transaction result: %w", err)
+	}
+	code := res.Result.Code
+	if inner, ok := res.Result.GetInnerResultPair(); ok {
+		code = inner.Result.Result.Code
+	}
+	return int64(res.FeeCharged), resultCodeName(code), nil
+}
+
+// resultCodeName converts a transaction result code to snake case, e.g.
+// TransactionResultCodeTxBadSeq to tx_bad_seq
+func resultCodeName(code xdr.TransactionResultCode) string {
+	var b strings.Builder
+	for i, r := range strings.TrimPrefix(code.String(), "TransactionResultCode") {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// rpcTransaction is the part of a getTransaction result the client reads
+type rpcTransaction struct {
+	Status      string `json:"status"`
+	Ledger      uint32 `json:"ledger"`
+	EnvelopeXDR string `json:"envelopeXdr"`
+	ResultXDR   string `json:"resultXdr"`
+}
+
+// getTransaction looks a transaction up by hash. Its status is NOT_FOUND
+// until the transaction is in a ledger, then SUCCESS or FAILED.
+func (c *Client) getTransaction(ctx context.Context, txHash string) (*rpcTransaction, error) {
+	resp, err := c.Call(ctx, "getTransaction", map[string]interface{}{"hash": txHash})
+	if err != nil {
+		return nil, err
 	}
 
-	return hash, nil
+	var tx rpcTransaction
+	if err := json.Unmarshal(resp.Result, &tx); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	return &tx, nil
 }
 
 // GetTransactionStatus gets the status of a transaction
@@ -196,3 +462,87 @@ func (c *Client) PollTransactionStatus(ctx context.Context, txHash string, timeo
 		}
 	}
 }
+
+// DefaultLedgerPollInterval is the poll interval WaitForLedgerDelta uses,
+// a fraction of the ~5s ledger close time
+const DefaultLedgerPollInterval = time.Second
+
+// LatestLedgerSequence returns the sequence of the network's latest ledger
+func (c *Client) LatestLedgerSequence(ctx context.Context) (uint32, error) {
+	result, err := c.GetLatestLedger(ctx)
+	if err != nil {
+		return 0, err
+	}
+	seq, ok := result["sequence"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("getLatestLedger returned no sequence")
+	}
+	return uint32(seq), nil
+}
+
+// LatestLedgerCloseTime returns when the network's latest ledger closed.
+// RPCs that don't report closeTime fall back to the local clock, which is
+// within a ledger or two of it.
+func (c *Client) LatestLedgerCloseTime(ctx context.Context) (time.Time, error) {
+	result, err := c.GetLatestLedger(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// closeTime is a string of unix seconds
+	switch v := result["closeTime"].(type) {
+	case string:
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid closeTime %q: %w", v, err)
+		}
+		return time.Unix(secs, 0), nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	default:
+		return time.Now(), nil
+	}
+}
+
+// WaitForLedger polls getLatestLedger every poll interval until the network
+// reaches seq, and returns the latest ledger seen. Failed polls are retried
+// until ctx is done.
+func (c *Client) WaitForLedger(ctx context.Context, seq uint32, poll time.Duration) (uint32, error) {
+	if poll <= 0 {
+		poll = DefaultLedgerPollInterval
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	var latest uint32
+	for {
+		current, err := c.LatestLedgerSequence(ctx)
+		if err != nil {
+			slog.Debug("failed to read latest ledger, continuing to poll",
+				"target_ledger", seq,
+				"error", err,
+			)
+		} else {
+			latest = current
+			if latest >= seq {
+				return latest, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return latest, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForLedgerDelta waits until n more ledgers have closed after the
+// current one, and returns the latest ledger seen
+func (c *Client) WaitForLedgerDelta(ctx context.Context, n uint32) (uint32, error) {
+	current, err := c.LatestLedgerSequence(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest ledger: %w", err)
+	}
+	return c.WaitForLedger(ctx, current+n, DefaultLedgerPollInterval)
+}
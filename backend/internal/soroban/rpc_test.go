@@ -0,0 +1,143 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// advancingLedgerServer answers getLatestLedger with a sequence that grows
+// by one per call, starting at start
+func advancingLedgerServer(t *testing.T, start uint32) *fakeRPC {
+	t.Helper()
+	var calls uint32
+	srv := newFakeRPC(t)
+	srv.answerFunc("getLatestLedger", func(json.RawMessage) string {
+		seq := start + atomic.AddUint32(&calls, 1) - 1
+		return fmt.Sprintf(`{"sequence":%d}`, seq)
+	})
+	return srv
+}
+
+func TestWaitForLedger_ReachesTarget(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: advancingLedgerServer(t, 100).URL})
+
+	latest, err := client.WaitForLedger(context.Background(), 103, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForLedger failed: %v", err)
+	}
+	if latest != 103 {
+		t.Errorf("expected ledger 103, got %d", latest)
+	}
+}
+
+func TestWaitForLedger_AlreadyReached(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: rpcServer(t, `{"sequence":500}`, nil).URL})
+
+	latest, err := client.WaitForLedger(context.Background(), 400, time.Hour)
+	if err != nil || latest != 500 {
+		t.Errorf("expected ledger 500 without waiting, got %d, %v", latest, err)
+	}
+}
+
+func TestWaitForLedger_ContextCancelled(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: rpcServer(t, `{"sequence":10}`, nil).URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	latest, err := client.WaitForLedger(ctx, 11, time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if latest != 10 {
+		t.Errorf("expected the last seen ledger, got %d", latest)
+	}
+}
+
+func TestWaitForLedgerDelta(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: advancingLedgerServer(t, 100).URL})
+
+	// The first call reads the tip (100); the wait then starts at 101.
+	latest, err := client.WaitForLedgerDelta(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("WaitForLedgerDelta failed: %v", err)
+	}
+	if latest != 101 {
+		t.Errorf("expected ledger 101, got %d", latest)
+	}
+}
+
+// recordingObserver records the methods it is told about
+type recordingObserver struct {
+	mu      sync.Mutex
+	methods []string
+	failed  int
+}
+
+func (o *recordingObserver) OnCall(method string, dur time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.methods = append(o.methods, method)
+	if err != nil {
+		o.failed++
+	}
+}
+
+func TestRPCObserver_SeesFullInvoke(t *testing.T) {
+	void, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	srv := newFakeRPC(t)
+	srv.answer("simulateTransaction", fmt.Sprintf(`{"latestLedger":5,"results":[{"xdr":%q}]}`, void))
+	srv.answer("sendTransaction", sentTx)
+	var polls atomic.Int32
+	confirmed := confirmedTx(t, 6, 100, "")
+	srv.answerFunc("getTransaction", func(json.RawMessage) string {
+		if polls.Add(1) < 3 {
+			return pendingTx
+		}
+		return confirmed
+	})
+
+	obs := &recordingObserver{}
+	client, _ := NewClient(Config{RPCURL: srv.URL, RPCObserver: obs})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = 5 * time.Millisecond
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), rc)
+	tb.account.store(&txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey(), Sequence: 1})
+	ec, _ := NewEscrowContract(client, tb, testContractHex)
+
+	result, err := ec.Invoke(context.Background(), "get_balance", nil)
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if result.Status != "success" {
+		t.Fatalf("expected a confirmed result, got %+v", result)
+	}
+	want := []string{"simulateTransaction", "sendTransaction", "getTransaction", "getTransaction", "getTransaction"}
+	if strings.Join(obs.methods, ",") != strings.Join(want, ",") {
+		t.Errorf("expected calls %v, got %v", want, obs.methods)
+	}
+}
+
+func TestRPCObserver_SeesEachAttempt(t *testing.T) {
+	bad := failingServer(t)
+	good := rpcServer(t, `{"sequence":42}`, nil)
+	obs := &recordingObserver{}
+	client, _ := NewClient(Config{RPCURL: bad.URL, FallbackRPCURLs: []string{good.URL}, RPCObserver: obs})
+
+	if _, err := client.GetLatestLedger(context.Background()); err != nil {
+		t.Fatalf("GetLatestLedger failed: %v", err)
+	}
+	if len(obs.methods) != 2 || obs.methods[0] != "getLatestLedger" || obs.failed != 1 {
+		t.Errorf("expected a failed and a successful getLatestLedger, got %v with %d failures", obs.methods, obs.failed)
+	}
+}
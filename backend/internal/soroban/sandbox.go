@@ -2,10 +2,16 @@ package soroban
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
 )
 
 // SandboxConfig holds configuration for sandbox shadow testing.
@@ -13,44 +19,153 @@ type SandboxConfig struct {
 	Enabled                  bool
 	EscrowSandboxContractID  string
 	ProgramSandboxContractID string
-	ShadowedOperations       []string // e.g. ["lock_funds", "release_funds", "refund"]
-	SandboxSourceSecret      string   // Separate keypair to avoid tx_bad_seq with production
-	MaxConcurrentShadows     int      // Bounds goroutine count (default: 10)
+	ShadowedOperations       []string      // e.g. ["lock_funds", "release_funds", "refund"]
+	SandboxSourceSecret      string        // Separate keypair to avoid tx_bad_seq with production
+	MaxConcurrentShadows     int           // Bounds goroutine count (default: 10)
+	StatsWindow              time.Duration // Rolling window for per-operation rates (default: 5m)
+
+	// Synchronous runs shadows inline so callers (mainly integration tests)
+	// observe the outcome when the Shadow* call returns. Production should
+	// leave this false so shadows never add latency to the primary call.
+	Synchronous bool
+	// DisableContextDetach runs shadows on the caller's context instead of
+	// one detached from its cancellation, so canceling it stops them. Meant
+	// for tests that need shadows to stop deterministically; in production
+	// it lets an HTTP request's cancellation abort its shadows.
+	DisableContextDetach bool
+
+	// BackpressurePolicy decides what happens when MaxConcurrentShadows
+	// shadows are already running (default: BackpressureDropNewest).
+	BackpressurePolicy BackpressurePolicy
+	BlockTimeout       time.Duration // Longest a BackpressureBlock launch waits (default: 1s)
+	QueueSize          int           // Capacity of the BackpressureQueue queue (default: 100)
+
+	// ShadowPriorities ranks operations in the BackpressureQueue queue:
+	// higher values launch first, and a full queue evicts its lowest-priority
+	// shadow to admit a higher-priority one. Unlisted operations have
+	// priority 0.
+	ShadowPriorities map[string]int
+
+	// RedactAddresses truncates account and contract addresses in the
+	// manager's log fields, e.g. GABC…XYZ, for deployments that must not log
+	// full identifiers
+	RedactAddresses bool
+	// LogLevel is the level successful shadows log at (default: Info). Set
+	// it to slog.LevelDebug to quiet them; failures always log at Warn.
+	LogLevel slog.Level
+
+	// DeadLetterPath, if set, is a file that shadows dropped by backpressure
+	// are appended to as JSON lines so they can be replayed. Writes happen in
+	// the background and the file is rotated at DeadLetterMaxBytes
+	// (default: 10 MiB). DeadLetterCodec selects plain or gzip-compressed
+	// JSON lines (default: CodecJSONL); OpenJSONL reads either.
+	DeadLetterPath     string
+	DeadLetterMaxBytes int64
+	DeadLetterCodec    Codec
+
+	// FunctionNameMap remaps shadowed operations to the host functions the
+	// sandbox contracts export them as, e.g. {"lock_funds": "lock_funds_v2"}
+	// to shadow a renamed function. Operations not in the map keep their
+	// production names.
+	FunctionNameMap map[string]string
+}
+
+// BackpressurePolicy controls how shadow launches behave at capacity
+type BackpressurePolicy string
+
+const (
+	// BackpressureDropNewest drops the new shadow and logs a warning
+	BackpressureDropNewest BackpressurePolicy = "drop_newest"
+
+	// BackpressureDropAndCount drops the new shadow, counting it in the stats
+	// without a warning log
+	BackpressureDropAndCount BackpressurePolicy = "drop_and_count"
+
+	// BackpressureBlock waits up to BlockTimeout for a free slot before
+	// dropping. The wait happens in the Shadow* caller, so this must never be
+	// used where the caller is a production request path; it exists for tests.
+	BackpressureBlock BackpressurePolicy = "block"
+
+	// BackpressureQueue holds up to QueueSize shadows and launches them as
+	// slots free up, highest ShadowPriorities first, dropping when the queue
+	// is full
+	BackpressureQueue BackpressurePolicy = "queue"
+)
+
+const (
+	defaultBlockTimeout = time.Second
+	defaultQueueSize    = 100
+)
+
+// KnownShadowOperations is the set of operation names that can be shadowed,
+// each with a typed Shadow* wrapper. Keep it in sync when adding or removing
+// shadow methods.
+var KnownShadowOperations = map[string]bool{
+	"lock_funds":    true,
+	"release_funds": true,
+	"refund":        true,
+	"single_payout": true,
+	"batch_payout":  true,
+}
+
+// programShadowOperations are the shadowed operations that run against the
+// sandbox program contract; the others run against the sandbox escrow
+var programShadowOperations = map[string]bool{
+	"single_payout": true,
+	"batch_payout":  true,
 }
 
 // SandboxManager mirrors selected contract operations to sandbox contract
 // instances for testing new features against real-ish data flow. Shadow
-// operations run asynchronously and never block or affect production calls.
+// operations run asynchronously (unless SandboxConfig.Synchronous is set) and
+// never affect production calls.
 type SandboxManager struct {
 	config    SandboxConfig
 	escrow    *EscrowContract
 	program   *ProgramEscrowContract
 	shadowOps map[string]bool
 	sem       chan struct{}
+	stats     *sandboxStats
+	queue     *shadowQueue
+	drained   chan struct{} // closed when drainQueue returns
+	events    shadowBus
+	logger    *slog.Logger // nil uses slog.Default()
+	paused    atomic.Bool
+	inFlight  inFlightShadows
+	amounts   amountFormat
+
+	comparisons comparisonRing
+
+	deadLetter *deadLetterSink // nil unless DeadLetterPath is set
 }
 
 // NewSandboxManager creates a SandboxManager with its own contract clients
 // pointing at sandbox addresses and a separate TransactionBuilder. Returns an
-// error if enabled but required configuration is missing.
+// error listing every problem if enabled but the configuration is missing
+// or invalid.
 func NewSandboxManager(client *Client, cfg SandboxConfig) (*SandboxManager, error) {
 	if !cfg.Enabled {
 		return &SandboxManager{config: cfg}, nil
 	}
 
-	if cfg.EscrowSandboxContractID == "" {
-		return nil, fmt.Errorf("sandbox: SANDBOX_ESCROW_CONTRACT_ID is required when sandbox is enabled")
+	if err := validateSandboxConfig(cfg); err != nil {
+		return nil, err
 	}
-	if cfg.ProgramSandboxContractID == "" {
-		return nil, fmt.Errorf("sandbox: SANDBOX_PROGRAM_ESCROW_CONTRACT_ID is required when sandbox is enabled")
-	}
-	if cfg.SandboxSourceSecret == "" {
-		return nil, fmt.Errorf("sandbox: SANDBOX_SOURCE_SECRET is required when sandbox is enabled")
+	if cfg.BackpressurePolicy == "" {
+		cfg.BackpressurePolicy = BackpressureDropNewest
 	}
 
 	maxConcurrent := cfg.MaxConcurrentShadows
 	if maxConcurrent <= 0 {
 		maxConcurrent = 10
 	}
+	if maxConcurrent > maxConcurrentShadowsLimit {
+		slog.Warn("sandbox: capping MaxConcurrentShadows",
+			"configured", maxConcurrent,
+			"cap", maxConcurrentShadowsLimit,
+		)
+		maxConcurrent = maxConcurrentShadowsLimit
+	}
 
 	// Create a separate TransactionBuilder with its own keypair so sandbox
 	// transactions don't conflict with production sequence numbers.
@@ -59,39 +174,186 @@ func NewSandboxManager(client *Client, cfg SandboxConfig) (*SandboxManager, erro
 		return nil, fmt.Errorf("sandbox: failed to create transaction builder: %w", err)
 	}
 
-	// Build the operation lookup set.
+	// Build the operation lookup set; validation rejected unknown names.
 	shadowOps := make(map[string]bool, len(cfg.ShadowedOperations))
 	for _, op := range cfg.ShadowedOperations {
-		op = strings.TrimSpace(op)
-		if op != "" {
+		if op = strings.TrimSpace(op); op != "" {
 			shadowOps[op] = true
 		}
 	}
+	functionNames := make(map[string]string, len(cfg.FunctionNameMap))
+	for op, name := range cfg.FunctionNameMap {
+		functionNames[op] = name
+	}
+	priorities := make(map[string]int, len(cfg.ShadowPriorities))
+	for op, p := range cfg.ShadowPriorities {
+		priorities[op] = p
+	}
+	cfg.ShadowPriorities = priorities
+
+	if cfg.BlockTimeout <= 0 {
+		cfg.BlockTimeout = defaultBlockTimeout
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
 
 	slog.Info("sandbox mode enabled",
-		"escrow_contract", cfg.EscrowSandboxContractID,
-		"program_contract", cfg.ProgramSandboxContractID,
+		"escrow_contract", redactAddress(cfg.EscrowSandboxContractID, cfg.RedactAddresses),
+		"program_contract", redactAddress(cfg.ProgramSandboxContractID, cfg.RedactAddresses),
 		"shadowed_operations", cfg.ShadowedOperations,
 		"max_concurrent", maxConcurrent,
+		"backpressure_policy", cfg.BackpressurePolicy,
 	)
 
-	return &SandboxManager{
+	escrow, err := NewEscrowContract(client, txBuilder, cfg.EscrowSandboxContractID)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: escrow contract: %w", err)
+	}
+	program, err := NewProgramEscrowContract(client, txBuilder, cfg.ProgramSandboxContractID)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: program contract: %w", err)
+	}
+	escrow.FunctionNames = functionNames
+	program.FunctionNames = functionNames
+
+	sm := &SandboxManager{
 		config:    cfg,
-		escrow:    NewEscrowContract(client, txBuilder, cfg.EscrowSandboxContractID),
-		program:   NewProgramEscrowContract(client, txBuilder, cfg.ProgramSandboxContractID),
+		escrow:    escrow,
+		program:   program,
 		shadowOps: shadowOps,
 		sem:       make(chan struct{}, maxConcurrent),
-	}, nil
+		stats:     newSandboxStats(cfg.StatsWindow),
+	}
+	if cfg.DeadLetterPath != "" {
+		sm.deadLetter, err = newDeadLetterSink(cfg.DeadLetterPath, cfg.DeadLetterMaxBytes, cfg.DeadLetterCodec)
+		if err != nil {
+			return nil, fmt.Errorf("sandbox: %w", err)
+		}
+	}
+	if cfg.BackpressurePolicy == BackpressureQueue {
+		sm.queue = newShadowQueue(cfg.QueueSize)
+		sm.startDrain()
+	}
+	return sm, nil
 }
 
-// shouldShadow returns true if the given operation is configured for shadowing.
+// maxConcurrentShadowsLimit caps MaxConcurrentShadows; more would mostly
+// pile up sandbox transactions behind one source account
+const maxConcurrentShadowsLimit = 1000
+
+// validateSandboxConfig checks an enabled config and reports every problem
+// at once, so operators can fix them in one pass
+func validateSandboxConfig(cfg SandboxConfig) error {
+	var problems []error
+
+	contractIDs := []struct{ name, value string }{
+		{"SANDBOX_ESCROW_CONTRACT_ID", cfg.EscrowSandboxContractID},
+		{"SANDBOX_PROGRAM_ESCROW_CONTRACT_ID", cfg.ProgramSandboxContractID},
+	}
+	for _, id := range contractIDs {
+		if id.value == "" {
+			problems = append(problems, fmt.Errorf("sandbox: %s is required when sandbox is enabled", id.name))
+		} else if err := ValidateContractAddress(id.value); err != nil {
+			problems = append(problems, fmt.Errorf("sandbox: %s is not a valid contract address: %w", id.name, err))
+		}
+	}
+
+	if cfg.SandboxSourceSecret == "" {
+		problems = append(problems, fmt.Errorf("sandbox: SANDBOX_SOURCE_SECRET is required when sandbox is enabled"))
+	} else if _, err := keypair.ParseFull(cfg.SandboxSourceSecret); err != nil {
+		problems = append(problems, fmt.Errorf("sandbox: SANDBOX_SOURCE_SECRET is not a valid secret seed"))
+	}
+
+	var unknown []string
+	for _, op := range cfg.ShadowedOperations {
+		if op = strings.TrimSpace(op); op != "" && !KnownShadowOperations[op] {
+			unknown = append(unknown, op)
+		}
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, fmt.Errorf("sandbox: unrecognized shadowed operations: %s", strings.Join(unknown, ", ")))
+	}
+
+	ops := make([]string, 0, len(cfg.FunctionNameMap))
+	for op := range cfg.FunctionNameMap {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		if !KnownShadowOperations[op] {
+			problems = append(problems, fmt.Errorf("sandbox: function name map has unrecognized operation %q", op))
+		} else if err := validateSymbol(cfg.FunctionNameMap[op]); err != nil {
+			problems = append(problems, fmt.Errorf("sandbox: function name for %s: %w", op, err))
+		}
+	}
+
+	ops = ops[:0]
+	for op := range cfg.ShadowPriorities {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		if !KnownShadowOperations[op] {
+			problems = append(problems, fmt.Errorf("sandbox: shadow priorities have unrecognized operation %q", op))
+		}
+	}
+
+	switch cfg.BackpressurePolicy {
+	case "", BackpressureDropNewest, BackpressureDropAndCount, BackpressureBlock, BackpressureQueue:
+	default:
+		problems = append(problems, fmt.Errorf("sandbox: unknown backpressure policy %q", cfg.BackpressurePolicy))
+	}
+
+	if err := cfg.DeadLetterCodec.validate(); err != nil {
+		problems = append(problems, fmt.Errorf("sandbox: dead-letter %w", err))
+	}
+
+	return errors.Join(problems...)
+}
+
+// shouldShadow returns true if the given operation is configured for shadowing
+// and the manager isn't paused.
 func (sm *SandboxManager) shouldShadow(operation string) bool {
-	if !sm.config.Enabled {
+	if !sm.config.Enabled || sm.paused.Load() {
 		return false
 	}
 	return sm.shadowOps[operation]
 }
 
+// skip reports whether operation should not be shadowed, counting shadows
+// skipped because the manager is paused
+func (sm *SandboxManager) skip(operation string) bool {
+	if sm.shouldShadow(operation) {
+		return false
+	}
+	if sm.config.Enabled && sm.shadowOps[operation] && sm.paused.Load() {
+		sm.stats.recordPaused(operation)
+	}
+	return true
+}
+
+// Pause stops launching shadows until Resume is called, e.g. during an
+// incident. Skipped shadows are counted per operation; running shadows,
+// stats and subscribers are unaffected.
+func (sm *SandboxManager) Pause() {
+	if sm.paused.CompareAndSwap(false, true) {
+		sm.log().Info("sandbox shadowing paused", "sandbox", true)
+	}
+}
+
+// Resume restarts shadowing after Pause
+func (sm *SandboxManager) Resume() {
+	if sm.paused.CompareAndSwap(true, false) {
+		sm.log().Info("sandbox shadowing resumed", "sandbox", true)
+	}
+}
+
+// IsPaused reports whether shadowing is paused
+func (sm *SandboxManager) IsPaused() bool {
+	return sm.paused.Load()
+}
+
 // acquireSemaphore tries to acquire a semaphore slot without blocking.
 // Returns false if the sandbox is at capacity.
 func (sm *SandboxManager) acquireSemaphore() bool {
@@ -107,119 +369,270 @@ func (sm *SandboxManager) releaseSemaphore() {
 	<-sm.sem
 }
 
-// logShadowResult emits a structured log entry for a completed shadow operation.
-func logShadowResult(operation string, start time.Time, err error) {
-	elapsed := time.Since(start)
-	if err != nil {
-		slog.Warn("sandbox shadow failed",
-			"sandbox", true,
-			"operation", operation,
-			"duration_ms", elapsed.Milliseconds(),
-			"error", err,
-		)
-		return
+// log returns the manager's logger
+func (sm *SandboxManager) log() *slog.Logger {
+	if sm.logger != nil {
+		return sm.logger
+	}
+	return slog.Default()
+}
+
+// address returns addr for logging, truncated when RedactAddresses is set
+func (sm *SandboxManager) address(addr string) string {
+	return redactAddress(addr, sm.config.RedactAddresses)
+}
+
+// redactAddress shortens addr to its first four and last three characters
+// when redact is set. Values too short to identify anything are kept.
+func redactAddress(addr string, redact bool) string {
+	if !redact || len(addr) <= 8 {
+		return addr
 	}
-	slog.Info("sandbox shadow succeeded",
+	return addr[:4] + "…" + addr[len(addr)-3:]
+}
+
+// logShadowResult emits a structured log entry for a completed shadow
+// operation. attrs are the operation's own fields, already redacted.
+func (sm *SandboxManager) logShadowResult(operation string, start time.Time, attrs []any, err error) {
+	elapsed := time.Since(start)
+	fields := append([]any{
 		"sandbox", true,
 		"operation", operation,
 		"duration_ms", elapsed.Milliseconds(),
-	)
+	}, attrs...)
+	if err != nil {
+		sm.log().Warn("sandbox shadow failed", append(fields, "error", err)...)
+		return
+	}
+	sm.log().Log(context.Background(), sm.config.LogLevel, "sandbox shadow succeeded", fields...)
 }
 
-// ShadowLockFunds mirrors a lock_funds call to the sandbox escrow contract.
-func (sm *SandboxManager) ShadowLockFunds(ctx context.Context, depositor string, bountyID uint64, amount int64, deadline int64) {
-	const op = "lock_funds"
-	if !sm.shouldShadow(op) {
+// dispatch runs a shadow either inline or on its own goroutine depending on
+// SandboxConfig.Synchronous. The semaphore slot is released in both cases.
+func (sm *SandboxManager) dispatch(run func()) {
+	if sm.config.Synchronous {
+		defer sm.releaseSemaphore()
+		run()
 		return
 	}
-	if !sm.acquireSemaphore() {
-		slog.Warn("sandbox shadow skipped: at capacity", "sandbox", true, "operation", op)
+	go func() {
+		defer sm.releaseSemaphore()
+		run()
+	}()
+}
+
+// recordShadowResult logs a completed shadow operation, records its outcome
+// and publishes it to subscribers.
+func (sm *SandboxManager) recordShadowResult(operation string, start time.Time, attrs []any, result *TransactionResult, err error) {
+	sm.logShadowResult(operation, start, attrs, err)
+	sm.stats.recordFinished(operation, err)
+	sm.events.publish(ShadowEvent{
+		Operation: operation,
+		Duration:  time.Since(start),
+		Err:       err,
+		Result:    result,
+	})
+}
+
+// shadow launches call against the sandbox if op is shadowed, applying the
+// configured backpressure policy when the sandbox is at capacity. inputs are
+// the call's arguments, recorded if it is dropped; attrs are extra log fields
+// describing the call.
+func (sm *SandboxManager) shadow(ctx context.Context, op string, inputs map[string]interface{}, attrs []any, call func(ctx context.Context) (*TransactionResult, error)) {
+	if sm.skip(op) {
 		return
 	}
 
 	// Detach from the HTTP request lifecycle so cancellation of the parent
-	// context does not abort the shadow operation.
-	shadowCtx := context.WithoutCancel(ctx)
-
-	go func() {
-		defer sm.releaseSemaphore()
+	// context does not abort the shadow operation. Each run gets its own
+	// cancelable child so CancelAll can still stop it.
+	shadowCtx := ctx
+	if !sm.config.DisableContextDetach {
+		shadowCtx = context.WithoutCancel(ctx)
+	}
+	run := func() {
+		runCtx, done := sm.inFlight.start(shadowCtx, op)
 		start := time.Now()
-		_, err := sm.escrow.LockFunds(shadowCtx, depositor, bountyID, amount, deadline)
-		logShadowResult(op, start, err)
-	}()
-}
+		result, err := call(runCtx)
+		done()
+		sm.recordShadowResult(op, start, attrs, result, err)
+	}
 
-// ShadowReleaseFunds mirrors a release_funds call to the sandbox escrow contract.
-func (sm *SandboxManager) ShadowReleaseFunds(ctx context.Context, bountyID uint64, contributor string) {
-	const op = "release_funds"
-	if !sm.shouldShadow(op) {
+	acquired := sm.acquireSemaphore()
+	sm.stats.recordLaunchAttempt(!acquired)
+	if acquired {
+		sm.stats.recordStarted(op)
+		sm.dispatch(run)
 		return
 	}
-	if !sm.acquireSemaphore() {
-		slog.Warn("sandbox shadow skipped: at capacity", "sandbox", true, "operation", op)
+
+	switch sm.config.BackpressurePolicy {
+	case BackpressureBlock:
+		timer := time.NewTimer(sm.config.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case sm.sem <- struct{}{}:
+			sm.stats.recordStarted(op)
+			sm.dispatch(run)
+			return
+		case <-timer.C:
+			sm.drop(op, DropBlockTimeout, inputs)
+		}
+	case BackpressureQueue:
+		evicted, ok := sm.queue.push(queuedShadow{operation: op, inputs: inputs, priority: sm.config.ShadowPriorities[op], run: run})
+		if !ok {
+			sm.drop(op, DropQueueFull, inputs)
+			break
+		}
+		if evicted != nil {
+			sm.drop(evicted.operation, DropQueueFull, evicted.inputs)
+			sm.log().Warn("sandbox shadow evicted from queue for a higher-priority one",
+				"sandbox", true,
+				"operation", evicted.operation,
+				"admitted", op,
+			)
+		}
+		return
+	case BackpressureDropAndCount:
+		sm.drop(op, DropAtCapacity, inputs)
 		return
+	default:
+		sm.drop(op, DropAtCapacity, inputs)
 	}
 
-	shadowCtx := context.WithoutCancel(ctx)
+	sm.log().Warn("sandbox shadow skipped: at capacity", append([]any{
+		"sandbox", true,
+		"operation", op,
+		"policy", sm.config.BackpressurePolicy,
+	}, attrs...)...)
+}
 
-	go func() {
-		defer sm.releaseSemaphore()
-		start := time.Now()
-		_, err := sm.escrow.ReleaseFunds(shadowCtx, bountyID, contributor)
-		logShadowResult(op, start, err)
-	}()
+// drop counts a dropped shadow and records it to the dead-letter file
+func (sm *SandboxManager) drop(op, reason string, inputs map[string]interface{}) {
+	sm.stats.recordDropped(op, reason)
+	sm.deadLetter.record(DeadLetterEntry{
+		Timestamp: time.Now().UTC(),
+		Operation: op,
+		Reason:    reason,
+		Inputs:    inputs,
+	})
 }
 
-// ShadowRefund mirrors a refund call to the sandbox escrow contract.
-func (sm *SandboxManager) ShadowRefund(ctx context.Context, bountyID uint64) {
-	const op = "refund"
-	if !sm.shouldShadow(op) {
-		return
+// Close stops launching queued shadows, abandoning any still queued, and
+// waits for the queue drainer to exit. It then flushes and closes the
+// dead-letter file, if any. Shadows dropped afterwards are only counted.
+func (sm *SandboxManager) Close() {
+	if sm.queue != nil {
+		sm.queue.close()
+		if sm.drained != nil {
+			<-sm.drained
+		}
 	}
-	if !sm.acquireSemaphore() {
-		slog.Warn("sandbox shadow skipped: at capacity", "sandbox", true, "operation", op)
+	sm.deadLetter.close()
+}
+
+// startDrain starts the goroutine that launches queued shadows
+func (sm *SandboxManager) startDrain() {
+	sm.drained = make(chan struct{})
+	go sm.drainQueue()
+}
+
+// drainQueue launches queued shadows, highest priority first, as semaphore
+// slots free up. The slot is taken before choosing the shadow so one queued
+// while waiting for it can still go first. It returns once the queue is
+// closed, even while waiting for a slot.
+func (sm *SandboxManager) drainQueue() {
+	defer close(sm.drained)
+	for sm.queue.wait() {
+		select {
+		case sm.sem <- struct{}{}:
+		case <-sm.queue.done:
+			return
+		}
+		q, ok := sm.queue.pop()
+		if !ok {
+			sm.releaseSemaphore()
+			continue
+		}
+		sm.stats.recordStarted(q.operation)
+		sm.dispatch(q.run)
+	}
+}
+
+// Shadow mirrors a call to host function fn on the sandbox contract op runs
+// against, for operations without a typed wrapper. op must be one of the
+// configured ShadowedOperations; fn goes through FunctionNameMap like the
+// typed wrappers' calls.
+func (sm *SandboxManager) Shadow(ctx context.Context, op, fn string, args []xdr.ScVal) {
+	if sm.skip(op) {
 		return
 	}
 
-	shadowCtx := context.WithoutCancel(ctx)
+	// Copy the slice to avoid races if the caller mutates it after returning.
+	callArgs := make([]xdr.ScVal, len(args))
+	copy(callArgs, args)
 
-	go func() {
-		defer sm.releaseSemaphore()
-		start := time.Now()
-		_, err := sm.escrow.Refund(shadowCtx, bountyID)
-		logShadowResult(op, start, err)
-	}()
+	inputs := map[string]interface{}{"function": fn, "args": encodeShadowArgs(callArgs)}
+	sm.shadow(ctx, op, inputs, []any{"function", fn}, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.contract(op).Invoke(ctx, fn, callArgs)
+	})
 }
 
-// ShadowSinglePayout mirrors a single_payout call to the sandbox program contract.
-func (sm *SandboxManager) ShadowSinglePayout(ctx context.Context, recipient string, amount int64) {
-	const op = "single_payout"
-	if !sm.shouldShadow(op) {
-		return
+// contract returns the sandbox contract op runs against
+func (sm *SandboxManager) contract(op string) Contract {
+	if programShadowOperations[op] {
+		return sm.program
 	}
-	if !sm.acquireSemaphore() {
-		slog.Warn("sandbox shadow skipped: at capacity", "sandbox", true, "operation", op)
-		return
+	return sm.escrow
+}
+
+// encodeShadowArgs renders args as base64 XDR for dead-letter entries
+func encodeShadowArgs(args []xdr.ScVal) []string {
+	encoded := make([]string, len(args))
+	for i, arg := range args {
+		encoded[i], _ = xdr.MarshalBase64(arg)
 	}
+	return encoded
+}
+
+// ShadowLockFunds mirrors a lock_funds call to the sandbox escrow contract.
+func (sm *SandboxManager) ShadowLockFunds(ctx context.Context, depositor string, bountyID uint64, amount int64, deadline int64) {
+	inputs := map[string]interface{}{"depositor": depositor, "bounty_id": bountyID, "amount": amount, "deadline": deadline}
+	attrs := append([]any{"depositor", sm.address(depositor), "bounty_id", bountyID}, sm.amountAttrs("amount", amount)...)
+	sm.shadow(ctx, "lock_funds", inputs, attrs, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.escrow.LockFunds(ctx, depositor, bountyID, amount, deadline)
+	})
+}
 
-	shadowCtx := context.WithoutCancel(ctx)
+// ShadowReleaseFunds mirrors a release_funds call to the sandbox escrow contract.
+func (sm *SandboxManager) ShadowReleaseFunds(ctx context.Context, bountyID uint64, contributor string) {
+	inputs := map[string]interface{}{"bounty_id": bountyID, "contributor": contributor}
+	attrs := []any{"contributor", sm.address(contributor), "bounty_id", bountyID}
+	sm.shadow(ctx, "release_funds", inputs, attrs, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.escrow.ReleaseFunds(ctx, bountyID, contributor)
+	})
+}
 
-	go func() {
-		defer sm.releaseSemaphore()
-		start := time.Now()
-		_, err := sm.program.SinglePayout(shadowCtx, recipient, amount)
-		logShadowResult(op, start, err)
-	}()
+// ShadowRefund mirrors a refund call to the sandbox escrow contract.
+func (sm *SandboxManager) ShadowRefund(ctx context.Context, bountyID uint64) {
+	inputs := map[string]interface{}{"bounty_id": bountyID}
+	sm.shadow(ctx, "refund", inputs, []any{"bounty_id", bountyID}, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.escrow.Refund(ctx, bountyID)
+	})
+}
+
+// ShadowSinglePayout mirrors a single_payout call to the sandbox program contract.
+func (sm *SandboxManager) ShadowSinglePayout(ctx context.Context, recipient string, amount int64) {
+	inputs := map[string]interface{}{"recipient": recipient, "amount": amount}
+	attrs := append([]any{"recipient", sm.address(recipient)}, sm.amountAttrs("amount", amount)...)
+	sm.shadow(ctx, "single_payout", inputs, attrs, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.program.SinglePayout(ctx, recipient, amount)
+	})
 }
 
 // ShadowBatchPayout mirrors a batch_payout call to the sandbox program contract.
 func (sm *SandboxManager) ShadowBatchPayout(ctx context.Context, payouts []PayoutItem) {
-	const op = "batch_payout"
-	if !sm.shouldShadow(op) {
-		return
-	}
-	if !sm.acquireSemaphore() {
-		slog.Warn("sandbox shadow skipped: at capacity", "sandbox", true, "operation", op)
+	if sm.skip("batch_payout") {
 		return
 	}
 
@@ -227,12 +640,13 @@ func (sm *SandboxManager) ShadowBatchPayout(ctx context.Context, payouts []Payou
 	items := make([]PayoutItem, len(payouts))
 	copy(items, payouts)
 
-	shadowCtx := context.WithoutCancel(ctx)
-
-	go func() {
-		defer sm.releaseSemaphore()
-		start := time.Now()
-		_, err := sm.program.BatchPayout(shadowCtx, items)
-		logShadowResult(op, start, err)
-	}()
+	var total int64
+	for _, item := range items {
+		total += item.Amount
+	}
+	inputs := map[string]interface{}{"payouts": items}
+	attrs := append([]any{"payouts", len(items)}, sm.amountAttrs("total", total)...)
+	sm.shadow(ctx, "batch_payout", inputs, attrs, func(ctx context.Context) (*TransactionResult, error) {
+		return sm.program.BatchPayout(ctx, items)
+	})
 }
@@ -0,0 +1,59 @@
+package soroban
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// tokenConfigFetchTimeout bounds the background token config fetch
+	tokenConfigFetchTimeout = 30 * time.Second
+	// tokenConfigRetryInterval is how long a failed fetch waits before the
+	// next logged amount may try again
+	tokenConfigRetryInterval = time.Minute
+)
+
+// amountFormat caches the formatter for the sandbox escrow's token
+type amountFormat struct {
+	fetching  atomic.Bool
+	formatter atomic.Pointer[AmountFormatter]
+}
+
+// amountAttrs returns the log fields for amount under key, formatted with
+// the sandbox escrow token's decimals. The token config is fetched in the
+// background the first time it's needed, so shadows never wait on it; until
+// it arrives, or if it can't be fetched, the raw amount is logged with
+// amount_format=base_units. Program payouts are assumed to use the same
+// token.
+func (sm *SandboxManager) amountAttrs(key string, amount int64) []any {
+	if f := sm.amounts.formatter.Load(); f != nil {
+		return []any{key, f.Format(amount)}
+	}
+	sm.fetchAmountFormatter()
+	return []any{key, amount, "amount_format", "base_units"}
+}
+
+// fetchAmountFormatter starts fetching the token config unless a fetch is
+// already running or recently failed
+func (sm *SandboxManager) fetchAmountFormatter() {
+	if sm.escrow == nil || !sm.amounts.fetching.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenConfigFetchTimeout)
+		defer cancel()
+
+		cfg, err := sm.escrow.GetTokenConfig(ctx)
+		if err != nil {
+			sm.log().Warn("sandbox: failed to fetch token config, logging amounts in base units",
+				"sandbox", true,
+				"error", err,
+			)
+			time.AfterFunc(tokenConfigRetryInterval, func() { sm.amounts.fetching.Store(false) })
+			return
+		}
+		f := cfg.Formatter()
+		sm.amounts.formatter.Store(&f)
+	}()
+}
@@ -0,0 +1,145 @@
+package soroban
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxComparisons bounds the comparisons retained for ComparisonReport
+	maxComparisons = 10000
+	// topDivergences is how many divergence signatures a report lists
+	topDivergences = 10
+)
+
+// ShadowComparison is the outcome of comparing a shadow with the production
+// call it mirrored
+type ShadowComparison struct {
+	Operation string
+	// Divergence is a signature of how the sandbox differed, e.g.
+	// "fee_mismatch"; empty means the two agreed. Comparisons with the same
+	// signature are grouped in reports, so leave out per-call values.
+	Divergence string
+	// At defaults to the time the comparison is recorded
+	At time.Time
+}
+
+// OperationAgreement is how often one operation's shadows agreed with
+// production within a report's window
+type OperationAgreement struct {
+	Compared      uint64  `json:"compared"`
+	Agreed        uint64  `json:"agreed"`
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+// DivergenceCount is how often a divergence signature was recorded
+type DivergenceCount struct {
+	Operation string `json:"operation"`
+	Signature string `json:"signature"`
+	Count     uint64 `json:"count"`
+}
+
+// SandboxComparisonReport summarizes recorded comparisons over a window,
+// suitable for serving from an admin endpoint
+type SandboxComparisonReport struct {
+	WindowSeconds float64                       `json:"window_seconds"`
+	Operations    map[string]OperationAgreement `json:"operations"`
+	// TopDivergences lists the most common signatures, most frequent first
+	TopDivergences []DivergenceCount `json:"top_divergences"`
+	TakenAt        time.Time         `json:"taken_at"`
+}
+
+// comparisonRing keeps the latest maxComparisons comparisons. The zero value
+// is ready to use.
+type comparisonRing struct {
+	mu   sync.Mutex
+	buf  []ShadowComparison
+	next int
+}
+
+func (r *comparisonRing) add(c ShadowComparison) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < maxComparisons {
+		r.buf = append(r.buf, c)
+		return
+	}
+	r.buf[r.next] = c
+	r.next = (r.next + 1) % maxComparisons
+}
+
+// since returns the retained comparisons recorded at or after t
+func (r *comparisonRing) since(t time.Time) []ShadowComparison {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []ShadowComparison
+	for _, c := range r.buf {
+		if !c.At.Before(t) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// RecordComparison records the outcome of comparing a shadow with production
+// for ComparisonReport. Only the latest 10,000 comparisons are kept.
+func (sm *SandboxManager) RecordComparison(c ShadowComparison) {
+	if c.At.IsZero() {
+		c.At = time.Now()
+	}
+	sm.comparisons.add(c)
+}
+
+// ComparisonReport aggregates the comparisons recorded within window
+// (default: 5 minutes) into per-operation agreement rates and the most
+// common divergence signatures, surfacing systematic differences between
+// the sandbox and production rather than one-off noise.
+func (sm *SandboxManager) ComparisonReport(window time.Duration) SandboxComparisonReport {
+	if window <= 0 {
+		window = defaultStatsWindow
+	}
+	now := time.Now()
+
+	type signature struct{ operation, divergence string }
+	ops := make(map[string]OperationAgreement)
+	divergences := make(map[signature]uint64)
+	for _, c := range sm.comparisons.since(now.Add(-window)) {
+		st := ops[c.Operation]
+		st.Compared++
+		if c.Divergence == "" {
+			st.Agreed++
+		} else {
+			divergences[signature{c.Operation, c.Divergence}]++
+		}
+		ops[c.Operation] = st
+	}
+	for name, st := range ops {
+		st.AgreementRate = float64(st.Agreed) / float64(st.Compared)
+		ops[name] = st
+	}
+
+	top := make([]DivergenceCount, 0, len(divergences))
+	for sig, n := range divergences {
+		top = append(top, DivergenceCount{Operation: sig.operation, Signature: sig.divergence, Count: n})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		if top[i].Operation != top[j].Operation {
+			return top[i].Operation < top[j].Operation
+		}
+		return top[i].Signature < top[j].Signature
+	})
+	if len(top) > topDivergences {
+		top = top[:topDivergences]
+	}
+
+	return SandboxComparisonReport{
+		WindowSeconds:  window.Seconds(),
+		Operations:     ops,
+		TopDivergences: top,
+		TakenAt:        now,
+	}
+}
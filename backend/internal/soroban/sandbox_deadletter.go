@@ -0,0 +1,189 @@
+package soroban
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultDeadLetterMaxBytes is the size at which the dead-letter file is
+	// rotated
+	defaultDeadLetterMaxBytes = 10 << 20
+	// deadLetterBuffer is how many entries may wait for the background writer
+	deadLetterBuffer = 1000
+)
+
+// Reasons a shadow was dropped, recorded in DeadLetterEntry.Reason
+const (
+	DropAtCapacity   = "at_capacity"
+	DropBlockTimeout = "block_timeout"
+	DropQueueFull    = "queue_full"
+)
+
+// DeadLetterEntry is a dropped shadow operation with the inputs needed to
+// replay it. Inputs are never redacted.
+type DeadLetterEntry struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Operation string                 `json:"operation"`
+	Reason    string                 `json:"reason"`
+	Inputs    map[string]interface{} `json:"inputs"`
+}
+
+// deadLetterSink appends dropped shadows to a file as JSON lines from a
+// background goroutine, so recording never blocks the caller. When the file
+// would exceed maxBytes it is renamed to path + ".1", replacing the previous
+// rotation, and a new file is started. maxBytes counts the uncompressed
+// lines, so gzip-encoded files are rotated well before reaching it on disk.
+type deadLetterSink struct {
+	path     string
+	maxBytes int64
+	codec    Codec
+
+	mu      sync.RWMutex // guards closed against sends on entries
+	closed  bool
+	entries chan DeadLetterEntry
+	done    chan struct{}
+	lost    atomic.Uint64 // entries dropped because the buffer was full
+
+	file *os.File
+	w    jsonlWriter
+	size int64
+}
+
+func newDeadLetterSink(path string, maxBytes int64, codec Codec) (*deadLetterSink, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultDeadLetterMaxBytes
+	}
+	s := &deadLetterSink{
+		path:     path,
+		maxBytes: maxBytes,
+		codec:    codec,
+		entries:  make(chan DeadLetterEntry, deadLetterBuffer),
+		done:     make(chan struct{}),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *deadLetterSink) open() error {
+	file, err := openJSONLFile(s.path, s.codec)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat dead-letter file: %w", err)
+	}
+	s.file, s.w, s.size = file, newJSONLWriter(file, s.codec), info.Size()
+	return nil
+}
+
+// record queues entry without blocking, counting it as lost if the writer
+// is behind
+func (s *deadLetterSink) record(entry DeadLetterEntry) {
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.entries <- entry:
+	default:
+		s.lost.Add(1)
+	}
+}
+
+// run writes queued entries, flushing whenever the queue empties
+func (s *deadLetterSink) run() {
+	defer close(s.done)
+	for entry := range s.entries {
+		s.write(entry)
+		if len(s.entries) == 0 {
+			s.flush()
+		}
+	}
+	s.closeFile()
+}
+
+func (s *deadLetterSink) write(entry DeadLetterEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("failed to encode dead-letter entry", "operation", entry.Operation, "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+		s.rotate()
+	}
+	if s.file == nil {
+		return
+	}
+	n, err := s.w.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		slog.Warn("failed to write dead-letter entry", "path", s.path, "error", err)
+	}
+}
+
+// rotate moves the current file aside and opens a new one. On failure the
+// sink stops writing rather than growing the old file without bound.
+func (s *deadLetterSink) rotate() {
+	s.closeFile()
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		slog.Warn("failed to rotate dead-letter file", "path", s.path, "error", err)
+		return
+	}
+	if err := s.open(); err != nil {
+		slog.Warn("failed to reopen dead-letter file", "path", s.path, "error", err)
+	}
+}
+
+func (s *deadLetterSink) flush() {
+	if s.file == nil {
+		return
+	}
+	if err := s.w.Flush(); err != nil {
+		slog.Warn("failed to flush dead-letter file", "path", s.path, "error", err)
+	}
+}
+
+// closeFile ends the encoding and closes the current file
+func (s *deadLetterSink) closeFile() {
+	if s.file == nil {
+		return
+	}
+	if err := s.w.Close(); err != nil {
+		slog.Warn("failed to flush dead-letter file", "path", s.path, "error", err)
+	}
+	s.file.Close()
+	s.file = nil
+}
+
+// close stops accepting entries and waits for queued ones to be written
+func (s *deadLetterSink) close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.entries)
+	s.mu.Unlock()
+	<-s.done
+}
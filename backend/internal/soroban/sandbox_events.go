@@ -0,0 +1,72 @@
+package soroban
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shadowEventBuffer is the channel capacity of each subscriber
+const shadowEventBuffer = 64
+
+// ShadowEvent describes a completed shadow operation
+type ShadowEvent struct {
+	Operation string
+	Duration  time.Duration
+	Err       error
+	Result    *TransactionResult // nil when the shadow failed
+}
+
+// shadowBus fans shadow events out to subscribers. The zero value is ready
+// to use.
+type shadowBus struct {
+	mu      sync.RWMutex
+	subs    map[uint64]chan ShadowEvent
+	nextID  uint64
+	dropped atomic.Uint64
+}
+
+func (b *shadowBus) subscribe() (<-chan ShadowEvent, func()) {
+	ch := make(chan ShadowEvent, shadowEventBuffer)
+
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[uint64]chan ShadowEvent)
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish delivers ev to every subscriber without blocking, dropping it for
+// subscribers whose buffer is full
+func (b *shadowBus) publish(ev ShadowEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving an event after each shadow completes,
+// and a function that unsubscribes and closes the channel. Events are dropped
+// rather than delivered late when the subscriber falls behind, so shadows are
+// never stalled by a slow consumer; drops are counted in the snapshot.
+func (sm *SandboxManager) Subscribe() (<-chan ShadowEvent, func()) {
+	return sm.events.subscribe()
+}
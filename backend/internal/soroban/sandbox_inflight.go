@@ -0,0 +1,83 @@
+package soroban
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InFlightShadow is a shadow operation that is currently running
+type InFlightShadow struct {
+	ID        uint64    `json:"id"`
+	Operation string    `json:"operation"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// inFlightShadows tracks running shadows with the functions that cancel them.
+// The zero value is ready to use.
+type inFlightShadows struct {
+	mu      sync.Mutex
+	nextID  uint64
+	running map[uint64]inFlightShadow
+}
+
+type inFlightShadow struct {
+	info   InFlightShadow
+	cancel context.CancelFunc
+}
+
+// start registers a shadow of op and returns the context it should run with,
+// a cancelable child of parent, and a function that must be called when it
+// finishes to release the entry
+func (f *inFlightShadows) start(parent context.Context, op string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running == nil {
+		f.running = make(map[uint64]inFlightShadow)
+	}
+	f.nextID++
+	id := f.nextID
+	f.running[id] = inFlightShadow{
+		info:   InFlightShadow{ID: id, Operation: op, StartedAt: time.Now()},
+		cancel: cancel,
+	}
+
+	return ctx, func() {
+		f.mu.Lock()
+		delete(f.running, id)
+		f.mu.Unlock()
+		cancel()
+	}
+}
+
+// ListInFlight returns the shadows currently running, oldest first. Queued
+// shadows haven't started and aren't listed.
+func (sm *SandboxManager) ListInFlight() []InFlightShadow {
+	f := &sm.inFlight
+	f.mu.Lock()
+	shadows := make([]InFlightShadow, 0, len(f.running))
+	for _, s := range f.running {
+		shadows = append(shadows, s.info)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(shadows, func(i, j int) bool { return shadows[i].ID < shadows[j].ID })
+	return shadows
+}
+
+// CancelAll cancels the context of every running shadow, e.g. on shutdown or
+// once the sandbox contract is found to be broken, and returns how many were
+// canceled. Each finishes as failed once its call returns. Shadows started
+// or dequeued afterwards run normally; Pause the manager first to stop them.
+func (sm *SandboxManager) CancelAll() int {
+	f := &sm.inFlight
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.running {
+		s.cancel()
+	}
+	return len(f.running)
+}
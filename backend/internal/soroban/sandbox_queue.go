@@ -0,0 +1,143 @@
+package soroban
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// queuedShadow is a shadow waiting for a semaphore slot under BackpressureQueue
+type queuedShadow struct {
+	operation string
+	inputs    map[string]interface{} // recorded if the shadow is evicted
+	priority  int
+	seq       uint64 // keeps shadows of equal priority in arrival order
+	run       func()
+}
+
+// shadowQueue holds queued shadows in priority order, highest first, up to
+// a fixed capacity
+type shadowQueue struct {
+	mu       sync.Mutex
+	items    shadowHeap
+	capacity int
+	nextSeq  uint64
+	closed   bool
+	ready    chan struct{} // signaled when a shadow is queued
+	done     chan struct{} // closed on close
+}
+
+func newShadowQueue(capacity int) *shadowQueue {
+	return &shadowQueue{capacity: capacity, ready: make(chan struct{}, 1), done: make(chan struct{})}
+}
+
+// push queues q, reporting whether it was accepted. When the queue is full,
+// the lowest-priority shadow, the newest among equals, is evicted and
+// returned to make room if q outranks it; otherwise q is rejected.
+func (sq *shadowQueue) push(q queuedShadow) (evicted *queuedShadow, ok bool) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if sq.closed {
+		return nil, false
+	}
+
+	if len(sq.items) >= sq.capacity {
+		lowest := sq.items.lowest()
+		if lowest < 0 || sq.items[lowest].priority >= q.priority {
+			return nil, false
+		}
+		e := heap.Remove(&sq.items, lowest).(queuedShadow)
+		evicted = &e
+	}
+
+	sq.nextSeq++
+	q.seq = sq.nextSeq
+	heap.Push(&sq.items, q)
+	select {
+	case sq.ready <- struct{}{}:
+	default:
+	}
+	return evicted, true
+}
+
+// wait blocks until a shadow is queued, reporting false once the queue is
+// closed
+func (sq *shadowQueue) wait() bool {
+	for {
+		sq.mu.Lock()
+		n, closed := len(sq.items), sq.closed
+		sq.mu.Unlock()
+		if closed {
+			return false
+		}
+		if n > 0 {
+			return true
+		}
+		select {
+		case <-sq.ready:
+		case <-sq.done:
+		}
+	}
+}
+
+// pop removes the highest-priority shadow, if any
+func (sq *shadowQueue) pop() (queuedShadow, bool) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if len(sq.items) == 0 {
+		return queuedShadow{}, false
+	}
+	return heap.Pop(&sq.items).(queuedShadow), true
+}
+
+// len returns the number of queued shadows; a nil queue is empty
+func (sq *shadowQueue) len() int {
+	if sq == nil {
+		return 0
+	}
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	return len(sq.items)
+}
+
+// close stops the drainer; queued shadows are abandoned
+func (sq *shadowQueue) close() {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if !sq.closed {
+		sq.closed = true
+		close(sq.done)
+	}
+}
+
+// shadowHeap implements heap.Interface with the highest priority, then the
+// earliest arrival, on top
+type shadowHeap []queuedShadow
+
+func (h shadowHeap) Len() int { return len(h) }
+func (h shadowHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h shadowHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *shadowHeap) Push(x any) { *h = append(*h, x.(queuedShadow)) }
+
+func (h *shadowHeap) Pop() any {
+	old := *h
+	q := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return q
+}
+
+// lowest returns the index of the lowest-priority, newest shadow, or -1
+func (h shadowHeap) lowest() int {
+	idx := -1
+	for i, q := range h {
+		if idx < 0 || q.priority < h[idx].priority || (q.priority == h[idx].priority && q.seq > h[idx].seq) {
+			idx = i
+		}
+	}
+	return idx
+}
@@ -0,0 +1,66 @@
+package soroban
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// saturationBuckets is how many slices the stats window is split into for
+// the rolling saturation ratio
+const saturationBuckets = 60
+
+// saturationSampler counts shadow launch attempts, and how many found the
+// semaphore full, in time buckets covering the stats window. Sampling is a
+// few atomic operations with no lock, so it adds nothing measurable to the
+// launch path. A sample racing a bucket's reset may be lost, which is fine
+// for a ratio.
+type saturationSampler struct {
+	width   time.Duration
+	buckets [saturationBuckets]saturationBucket
+}
+
+type saturationBucket struct {
+	slot     atomic.Int64 // which width-sized interval since the epoch the counts are for
+	attempts atomic.Uint64
+	full     atomic.Uint64
+}
+
+func newSaturationSampler(window time.Duration) *saturationSampler {
+	width := window / saturationBuckets
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	return &saturationSampler{width: width}
+}
+
+// sample records a launch attempt at now
+func (s *saturationSampler) sample(now time.Time, full bool) {
+	slot := now.UnixNano() / int64(s.width)
+	b := &s.buckets[slot%saturationBuckets]
+	if old := b.slot.Load(); old != slot && b.slot.CompareAndSwap(old, slot) {
+		b.attempts.Store(0)
+		b.full.Store(0)
+	}
+	b.attempts.Add(1)
+	if full {
+		b.full.Add(1)
+	}
+}
+
+// ratio returns the fraction of the window's launch attempts that found the
+// semaphore full, and how many attempts it is based on
+func (s *saturationSampler) ratio(now time.Time) (float64, uint64) {
+	current := now.UnixNano() / int64(s.width)
+	var attempts, full uint64
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if slot := b.slot.Load(); slot > current-saturationBuckets && slot <= current {
+			attempts += b.attempts.Load()
+			full += b.full.Load()
+		}
+	}
+	if attempts == 0 {
+		return 0, 0
+	}
+	return float64(full) / float64(attempts), attempts
+}
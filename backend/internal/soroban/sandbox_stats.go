@@ -0,0 +1,270 @@
+package soroban
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultStatsWindow is the rolling window used for per-operation rates
+const defaultStatsWindow = 5 * time.Minute
+
+// maxWindowOutcomes bounds the memory used by the rolling window
+const maxWindowOutcomes = 10000
+
+// OperationStats holds shadow counters for a single operation
+type OperationStats struct {
+	Succeeded uint64 `json:"succeeded"`
+	Failed    uint64 `json:"failed"`
+	Dropped   uint64 `json:"dropped"`
+	Paused    uint64 `json:"paused"` // Skipped while the manager was paused
+	InFlight  int    `json:"in_flight"`
+
+	// DroppedByReason splits Dropped by reason: DropAtCapacity,
+	// DropBlockTimeout or DropQueueFull
+	DroppedByReason map[string]uint64 `json:"dropped_by_reason,omitempty"`
+
+	// Rolling-window counters and the success rate derived from them. The
+	// rate is zero when no shadow completed within the window.
+	WindowSucceeded   uint64  `json:"window_succeeded"`
+	WindowFailed      uint64  `json:"window_failed"`
+	WindowSuccessRate float64 `json:"window_success_rate"`
+}
+
+// SandboxSnapshot is a point-in-time view of sandbox health, suitable for
+// serving from an admin endpoint. Operations and InFlight are read together
+// and agree with each other; Paused, QueueDepth, DroppedEvents and the
+// saturation figures are each read separately just after, so under load
+// they are approximate relative to the operation counters.
+type SandboxSnapshot struct {
+	Enabled            bool                      `json:"enabled"`
+	Paused             bool                      `json:"paused"`
+	ShadowedOperations []string                  `json:"shadowed_operations"`
+	MaxConcurrent      int                       `json:"max_concurrent"`
+	InFlight           int                       `json:"in_flight"`
+	QueueDepth         int                       `json:"queue_depth"`
+	WindowSeconds      float64                   `json:"window_seconds"`
+	Operations         map[string]OperationStats `json:"operations"`
+	DroppedEvents      uint64                    `json:"dropped_events"` // Shadow events not delivered to slow subscribers
+	TakenAt            time.Time                 `json:"taken_at"`
+
+	// SaturationRatio is the fraction of shadow launches within the window
+	// that found all MaxConcurrentShadows slots taken, out of
+	// SaturationSamples launches. CapacityDrops totals the dropped shadows
+	// across operations and DropsByReason splits it by reason: at_capacity
+	// and block_timeout drops found no free slot, queue_full drops found
+	// the backpressure queue full or were evicted from it by a
+	// higher-priority shadow. A ratio that stays high, or drops that keep
+	// growing, mean MaxConcurrentShadows should be raised.
+	SaturationRatio   float64           `json:"saturation_ratio"`
+	SaturationSamples uint64            `json:"saturation_samples"`
+	CapacityDrops     uint64            `json:"capacity_drops"`
+	DropsByReason     map[string]uint64 `json:"drops_by_reason"`
+}
+
+// shadowOutcome is a completed shadow recorded in the rolling window
+type shadowOutcome struct {
+	at        time.Time
+	operation string
+	failed    bool
+}
+
+// sandboxStats tracks shadow outcomes. All counters live behind a single
+// mutex so a snapshot never observes a half-updated set of totals.
+type sandboxStats struct {
+	mu       sync.Mutex
+	window   time.Duration
+	ops      map[string]*OperationStats
+	outcomes []shadowOutcome
+
+	// saturation is sampled without s.mu, see saturationSampler
+	saturation *saturationSampler
+}
+
+func newSandboxStats(window time.Duration) *sandboxStats {
+	if window <= 0 {
+		window = defaultStatsWindow
+	}
+	return &sandboxStats{
+		window:     window,
+		ops:        make(map[string]*OperationStats),
+		saturation: newSaturationSampler(window),
+	}
+}
+
+// recordLaunchAttempt samples whether a shadow launch found the semaphore
+// full
+func (s *sandboxStats) recordLaunchAttempt(full bool) {
+	if s == nil {
+		return
+	}
+	s.saturation.sample(time.Now(), full)
+}
+
+// saturationRatio returns the rolling saturation ratio and its sample count
+func (s *sandboxStats) saturationRatio() (float64, uint64) {
+	if s == nil {
+		return 0, 0
+	}
+	return s.saturation.ratio(time.Now())
+}
+
+// opLocked returns the counters for an operation. Callers must hold s.mu.
+func (s *sandboxStats) opLocked(operation string) *OperationStats {
+	st, ok := s.ops[operation]
+	if !ok {
+		st = &OperationStats{}
+		s.ops[operation] = st
+	}
+	return st
+}
+
+func (s *sandboxStats) recordStarted(operation string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opLocked(operation).InFlight++
+}
+
+func (s *sandboxStats) recordDropped(operation, reason string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.opLocked(operation)
+	st.Dropped++
+	if st.DroppedByReason == nil {
+		st.DroppedByReason = make(map[string]uint64)
+	}
+	st.DroppedByReason[reason]++
+}
+
+func (s *sandboxStats) recordPaused(operation string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opLocked(operation).Paused++
+}
+
+func (s *sandboxStats) recordFinished(operation string, err error) {
+	if s == nil {
+		return
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.opLocked(operation)
+	st.InFlight--
+	if err != nil {
+		st.Failed++
+	} else {
+		st.Succeeded++
+	}
+
+	s.outcomes = append(s.outcomes, shadowOutcome{at: now, operation: operation, failed: err != nil})
+	s.pruneLocked(now)
+}
+
+// pruneLocked drops outcomes that fell out of the window or exceed the cap.
+// Callers must hold s.mu.
+func (s *sandboxStats) pruneLocked(now time.Time) {
+	cutoff := now.Add(-s.window)
+	drop := 0
+	for drop < len(s.outcomes) && s.outcomes[drop].at.Before(cutoff) {
+		drop++
+	}
+	if excess := len(s.outcomes) - drop - maxWindowOutcomes; excess > 0 {
+		drop += excess
+	}
+	if drop > 0 {
+		s.outcomes = append(s.outcomes[:0], s.outcomes[drop:]...)
+	}
+}
+
+// snapshot copies all counters under a single lock acquisition
+func (s *sandboxStats) snapshot() (ops map[string]OperationStats, inFlight int, window time.Duration) {
+	ops = make(map[string]OperationStats)
+	if s == nil {
+		return ops, 0, defaultStatsWindow
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(now)
+
+	for name, st := range s.ops {
+		cp := *st
+		cp.DroppedByReason = maps.Clone(st.DroppedByReason)
+		ops[name] = cp
+		inFlight += st.InFlight
+	}
+	for _, o := range s.outcomes {
+		st := ops[o.operation]
+		if o.failed {
+			st.WindowFailed++
+		} else {
+			st.WindowSucceeded++
+		}
+		ops[o.operation] = st
+	}
+	for name, st := range ops {
+		if total := st.WindowSucceeded + st.WindowFailed; total > 0 {
+			st.WindowSuccessRate = float64(st.WindowSucceeded) / float64(total)
+			ops[name] = st
+		}
+	}
+
+	return ops, inFlight, s.window
+}
+
+// Snapshot returns a point-in-time view of the sandbox's configuration and
+// shadow outcome statistics. See SandboxSnapshot for which fields are
+// consistent with each other.
+func (sm *SandboxManager) Snapshot() SandboxSnapshot {
+	takenAt := time.Now()
+	ops, inFlight, window := sm.stats.snapshot()
+
+	shadowed := make([]string, 0, len(sm.shadowOps))
+	for op := range sm.shadowOps {
+		shadowed = append(shadowed, op)
+	}
+	sort.Strings(shadowed)
+
+	// Pauses are counted separately, not as drops
+	var capacityDrops uint64
+	dropsByReason := make(map[string]uint64)
+	for _, st := range ops {
+		capacityDrops += st.Dropped
+		for reason, n := range st.DroppedByReason {
+			dropsByReason[reason] += n
+		}
+	}
+	ratio, samples := sm.stats.saturationRatio()
+
+	return SandboxSnapshot{
+		Enabled:            sm.config.Enabled,
+		Paused:             sm.paused.Load(),
+		ShadowedOperations: shadowed,
+		MaxConcurrent:      cap(sm.sem),
+		InFlight:           inFlight,
+		QueueDepth:         sm.queue.len(),
+		WindowSeconds:      window.Seconds(),
+		Operations:         ops,
+		DroppedEvents:      sm.events.dropped.Load(),
+		TakenAt:            takenAt,
+		SaturationRatio:    ratio,
+		SaturationSamples:  samples,
+		CapacityDrops:      capacityDrops,
+		DropsByReason:      dropsByReason,
+	}
+}
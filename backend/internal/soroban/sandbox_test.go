@@ -1,8 +1,22 @@
 package soroban
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
 )
 
 func TestShouldShadow_EnabledOperations(t *testing.T) {
@@ -126,6 +140,30 @@ func TestNewSandboxManager_MissingEscrowID(t *testing.T) {
 	}
 }
 
+func TestNewSandboxManager_ReportsAllProblems(t *testing.T) {
+	_, err := NewSandboxManager(nil, SandboxConfig{
+		Enabled:                 true,
+		EscrowSandboxContractID: "CABC",
+		SandboxSourceSecret:     "SNOTAREALSECRET",
+		ShadowedOperations:      []string{"lockfunds"},
+		BackpressurePolicy:      "sometimes",
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid config")
+	}
+	for _, want := range []string{
+		"SANDBOX_ESCROW_CONTRACT_ID is not a valid contract address",
+		"SANDBOX_PROGRAM_ESCROW_CONTRACT_ID is required",
+		"SANDBOX_SOURCE_SECRET is not a valid secret seed",
+		"unrecognized shadowed operations: lockfunds",
+		`unknown backpressure policy "sometimes"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in the aggregated error, got:\n%v", want, err)
+		}
+	}
+}
+
 func TestNewSandboxManager_MissingProgramID(t *testing.T) {
 	_, err := NewSandboxManager(nil, SandboxConfig{
 		Enabled:                  true,
@@ -149,3 +187,779 @@ func TestNewSandboxManager_MissingSourceSecret(t *testing.T) {
 		t.Error("expected error when source secret is missing")
 	}
 }
+
+func TestNewSandboxManager_UnknownOperation(t *testing.T) {
+	_, err := NewSandboxManager(nil, SandboxConfig{
+		Enabled:                  true,
+		EscrowSandboxContractID:  "CABC",
+		ProgramSandboxContractID: "CDEF",
+		SandboxSourceSecret:      keypair.MustRandom().Seed(),
+		ShadowedOperations:       []string{"lock_funds", "lockfunds", " refund "},
+	})
+	if err == nil || !strings.Contains(err.Error(), "lockfunds") {
+		t.Errorf("expected error naming the unknown operation, got %v", err)
+	}
+}
+
+func TestNewSandboxManager_FunctionNameMap(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://127.0.0.1:0"})
+	cfg := SandboxConfig{
+		Enabled:                  true,
+		EscrowSandboxContractID:  testContractHex,
+		ProgramSandboxContractID: testContractHex,
+		SandboxSourceSecret:      keypair.MustRandom().Seed(),
+		FunctionNameMap:          map[string]string{"lock_funds": "lock_funds_v2"},
+	}
+	sm, err := NewSandboxManager(client, cfg)
+	if err != nil {
+		t.Fatalf("NewSandboxManager failed: %v", err)
+	}
+	if got := hostFunction(sm.escrow.FunctionNames, "lock_funds"); got != "lock_funds_v2" {
+		t.Errorf("expected lock_funds to be remapped, got %q", got)
+	}
+	if got := hostFunction(sm.escrow.FunctionNames, "refund"); got != "refund" {
+		t.Errorf("expected unmapped refund to keep its name, got %q", got)
+	}
+
+	cfg.FunctionNameMap = map[string]string{"lockfunds": "lock_funds_v2"}
+	if _, err := NewSandboxManager(client, cfg); err == nil || !strings.Contains(err.Error(), "lockfunds") {
+		t.Errorf("expected error naming the unknown operation, got %v", err)
+	}
+	cfg.FunctionNameMap = map[string]string{"refund": "refund-v2"}
+	if _, err := NewSandboxManager(client, cfg); err == nil {
+		t.Error("expected error for an invalid function name")
+	}
+}
+
+func TestKnownShadowOperations_MatchMethods(t *testing.T) {
+	// Every Shadow<Op> method must have its snake_case name in the known set.
+	smType := reflect.TypeOf(&SandboxManager{})
+	methods := 0
+	for i := 0; i < smType.NumMethod(); i++ {
+		name := smType.Method(i).Name
+		// Shadow itself is the generic entry point
+		if !strings.HasPrefix(name, "Shadow") || name == "Shadow" {
+			continue
+		}
+		methods++
+
+		var op strings.Builder
+		for j, r := range strings.TrimPrefix(name, "Shadow") {
+			if unicode.IsUpper(r) && j > 0 {
+				op.WriteByte('_')
+			}
+			op.WriteRune(unicode.ToLower(r))
+		}
+		if !KnownShadowOperations[op.String()] {
+			t.Errorf("%s has no entry %q in KnownShadowOperations", name, op.String())
+		}
+	}
+	if methods != len(KnownShadowOperations) {
+		t.Errorf("expected %d Shadow methods, found %d", len(KnownShadowOperations), methods)
+	}
+}
+
+func TestSnapshot_AggregatesOutcomes(t *testing.T) {
+	sm := &SandboxManager{
+		config: SandboxConfig{Enabled: true},
+		shadowOps: map[string]bool{
+			"refund":     true,
+			"lock_funds": true,
+		},
+		sem:   make(chan struct{}, 4),
+		stats: newSandboxStats(time.Minute),
+	}
+
+	sm.stats.recordStarted("lock_funds")
+	sm.stats.recordStarted("lock_funds")
+	sm.stats.recordStarted("lock_funds")
+	sm.stats.recordFinished("lock_funds", nil)
+	sm.stats.recordFinished("lock_funds", errors.New("boom"))
+	sm.stats.recordDropped("refund", DropAtCapacity)
+
+	snap := sm.Snapshot()
+
+	if !snap.Enabled {
+		t.Error("expected snapshot to report enabled")
+	}
+	if len(snap.ShadowedOperations) != 2 || snap.ShadowedOperations[0] != "lock_funds" {
+		t.Errorf("expected sorted shadowed operations, got %v", snap.ShadowedOperations)
+	}
+	if snap.MaxConcurrent != 4 {
+		t.Errorf("expected max concurrent 4, got %d", snap.MaxConcurrent)
+	}
+	if snap.InFlight != 1 {
+		t.Errorf("expected 1 in-flight shadow, got %d", snap.InFlight)
+	}
+
+	lock := snap.Operations["lock_funds"]
+	if lock.Succeeded != 1 || lock.Failed != 1 {
+		t.Errorf("expected 1 success and 1 failure, got %+v", lock)
+	}
+	if lock.WindowSuccessRate != 0.5 {
+		t.Errorf("expected window success rate 0.5, got %f", lock.WindowSuccessRate)
+	}
+	if snap.Operations["refund"].Dropped != 1 {
+		t.Errorf("expected 1 dropped refund, got %+v", snap.Operations["refund"])
+	}
+
+	if _, err := json.Marshal(snap); err != nil {
+		t.Errorf("snapshot should serialize to JSON: %v", err)
+	}
+}
+
+func TestSnapshot_WindowExpiry(t *testing.T) {
+	stats := newSandboxStats(time.Minute)
+	stats.recordStarted("refund")
+	stats.recordFinished("refund", nil)
+
+	// Age the recorded outcome past the window.
+	stats.outcomes[0].at = time.Now().Add(-2 * time.Minute)
+
+	ops, _, _ := stats.snapshot()
+	refund := ops["refund"]
+	if refund.Succeeded != 1 {
+		t.Errorf("expected lifetime success count to be kept, got %d", refund.Succeeded)
+	}
+	if refund.WindowSucceeded != 0 || refund.WindowSuccessRate != 0 {
+		t.Errorf("expected expired outcome to leave the window, got %+v", refund)
+	}
+}
+
+func TestSnapshot_NilStats(t *testing.T) {
+	sm := &SandboxManager{config: SandboxConfig{Enabled: false}}
+	snap := sm.Snapshot()
+	if snap.Enabled || snap.InFlight != 0 || len(snap.Operations) != 0 {
+		t.Errorf("expected empty snapshot for disabled manager, got %+v", snap)
+	}
+}
+
+func TestShadowSynchronous_RecordsBeforeReturn(t *testing.T) {
+	client, err := NewClient(Config{RPCURL: "http://127.0.0.1:0", Network: NetworkTestnet})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	// An invalid contract address makes the shadow fail before any network call.
+	sm := &SandboxManager{
+		config:    SandboxConfig{Enabled: true, Synchronous: true},
+		escrow:    &EscrowContract{client: client, contractAddress: "not-a-contract"},
+		shadowOps: map[string]bool{"refund": true},
+		sem:       make(chan struct{}, 1),
+		stats:     newSandboxStats(time.Minute),
+	}
+
+	sm.ShadowRefund(context.Background(), 1)
+
+	refund := sm.Snapshot().Operations["refund"]
+	if refund.Failed != 1 || refund.InFlight != 0 {
+		t.Errorf("expected the shadow to have completed with a failure, got %+v", refund)
+	}
+	if len(sm.sem) != 0 {
+		t.Error("expected the semaphore slot to be released")
+	}
+}
+
+// fullSandbox returns an enabled manager shadowing refund whose single
+// semaphore slot is already taken. Shadows fail fast on the invalid contract.
+func fullSandbox(t *testing.T, cfg SandboxConfig) *SandboxManager {
+	t.Helper()
+	client, err := NewClient(Config{RPCURL: "http://127.0.0.1:0", Network: NetworkTestnet})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	cfg.Enabled = true
+	cfg.Synchronous = true
+	sm := &SandboxManager{
+		config:    cfg,
+		escrow:    &EscrowContract{client: client, contractAddress: "not-a-contract"},
+		shadowOps: map[string]bool{"refund": true},
+		sem:       make(chan struct{}, 1),
+		stats:     newSandboxStats(time.Minute),
+	}
+	sm.sem <- struct{}{}
+	return sm
+}
+
+func TestBackpressure_DropAndCount(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureDropAndCount})
+	sm.ShadowRefund(context.Background(), 1)
+
+	if dropped := sm.Snapshot().Operations["refund"].Dropped; dropped != 1 {
+		t.Errorf("expected 1 dropped shadow, got %d", dropped)
+	}
+}
+
+func TestBackpressure_BlockWaitsForSlot(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureBlock, BlockTimeout: time.Second})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		sm.releaseSemaphore()
+	}()
+
+	sm.ShadowRefund(context.Background(), 1)
+
+	refund := sm.Snapshot().Operations["refund"]
+	if refund.Failed != 1 || refund.Dropped != 0 {
+		t.Errorf("expected the shadow to run once a slot freed, got %+v", refund)
+	}
+}
+
+func TestBackpressure_BlockTimesOut(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureBlock, BlockTimeout: 10 * time.Millisecond})
+	sm.ShadowRefund(context.Background(), 1)
+
+	refund := sm.Snapshot().Operations["refund"]
+	if refund.Dropped != 1 || refund.DroppedByReason[DropBlockTimeout] != 1 {
+		t.Errorf("expected the shadow to be dropped after the timeout, got %+v", refund)
+	}
+}
+
+func TestBackpressure_Queue(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureQueue})
+	sm.queue = newShadowQueue(1)
+
+	sm.ShadowRefund(context.Background(), 1)
+	sm.ShadowRefund(context.Background(), 2) // queue is full
+
+	snap := sm.Snapshot()
+	if snap.QueueDepth != 1 || snap.Operations["refund"].Dropped != 1 || snap.DropsByReason[DropQueueFull] != 1 {
+		t.Fatalf("expected 1 queued and 1 dropped shadow, got depth %d, %+v", snap.QueueDepth, snap.Operations["refund"])
+	}
+
+	sm.startDrain()
+	sm.releaseSemaphore()
+
+	deadline := time.Now().Add(time.Second)
+	for sm.Snapshot().Operations["refund"].Failed != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("queued shadow did not run after a slot freed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	sm.Close()
+}
+
+func TestBackpressure_CloseStopsQueueDrainer(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureQueue})
+	sm.queue = newShadowQueue(1)
+	sm.startDrain()
+
+	// The drainer blocks waiting for the slot the full semaphore never frees
+	sm.ShadowRefund(context.Background(), 1)
+
+	closed := make(chan struct{})
+	go func() {
+		sm.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the drainer waiting for a slot")
+	}
+	if stats := sm.Snapshot().Operations["refund"]; stats.InFlight != 0 || stats.Failed != 0 {
+		t.Errorf("expected the queued shadow to be abandoned, got %+v", stats)
+	}
+}
+
+func TestShadowQueue_PopsHighestPriorityFirst(t *testing.T) {
+	q := newShadowQueue(4)
+	q.push(queuedShadow{operation: "refund", priority: 0})
+	q.push(queuedShadow{operation: "lock_funds", priority: 5})
+	q.push(queuedShadow{operation: "batch_payout", priority: 5})
+	q.push(queuedShadow{operation: "single_payout", priority: 1})
+
+	var got []string
+	for q.len() > 0 {
+		s, _ := q.pop()
+		got = append(got, s.operation)
+	}
+	want := []string{"lock_funds", "batch_payout", "single_payout", "refund"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected order %v, got %v", want, got)
+	}
+}
+
+func TestShadowQueue_FullQueueEvictsLowerPriority(t *testing.T) {
+	q := newShadowQueue(2)
+	q.push(queuedShadow{operation: "refund", priority: 0})
+	q.push(queuedShadow{operation: "single_payout", priority: 1})
+
+	evicted, ok := q.push(queuedShadow{operation: "lock_funds", priority: 5})
+	if !ok || evicted == nil || evicted.operation != "refund" {
+		t.Fatalf("expected the refund shadow to be evicted, got %+v (queued: %v)", evicted, ok)
+	}
+
+	// equal priority doesn't evict; the newcomer is rejected
+	if evicted, ok = q.push(queuedShadow{operation: "refund", priority: 1}); ok || evicted != nil {
+		t.Fatalf("expected the new shadow to be rejected, got %+v (queued: %v)", evicted, ok)
+	}
+	if q.len() != 2 {
+		t.Errorf("expected 2 queued shadows, got %d", q.len())
+	}
+}
+
+func TestNewSandboxManager_UnknownShadowPriority(t *testing.T) {
+	_, err := NewSandboxManager(nil, SandboxConfig{
+		Enabled:                  true,
+		EscrowSandboxContractID:  "CABC",
+		ProgramSandboxContractID: "CDEF",
+		SandboxSourceSecret:      keypair.MustRandom().Seed(),
+		ShadowPriorities:         map[string]int{"withdraw": 1},
+	})
+	if err == nil {
+		t.Error("expected error for a priority on an unknown operation")
+	}
+}
+
+func TestNewSandboxManager_UnknownBackpressurePolicy(t *testing.T) {
+	_, err := NewSandboxManager(nil, SandboxConfig{
+		Enabled:                  true,
+		EscrowSandboxContractID:  "CABC",
+		ProgramSandboxContractID: "CDEF",
+		SandboxSourceSecret:      keypair.MustRandom().Seed(),
+		BackpressurePolicy:       "lifo",
+	})
+	if err == nil {
+		t.Error("expected error for unknown backpressure policy")
+	}
+}
+
+func TestSubscribe_ReceivesShadowEvents(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{})
+	sm.releaseSemaphore()
+
+	first, unsubFirst := sm.Subscribe()
+	second, unsubSecond := sm.Subscribe()
+	defer unsubSecond()
+
+	sm.ShadowRefund(context.Background(), 1)
+
+	for _, ch := range []<-chan ShadowEvent{first, second} {
+		select {
+		case ev := <-ch:
+			if ev.Operation != "refund" || ev.Err == nil || ev.Result != nil {
+				t.Errorf("unexpected event %+v", ev)
+			}
+		default:
+			t.Fatal("expected every subscriber to receive the event")
+		}
+	}
+
+	unsubFirst()
+	unsubFirst() // unsubscribing twice is safe
+	if _, ok := <-first; ok {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+	sm.ShadowRefund(context.Background(), 2)
+	if len(second) != 1 {
+		t.Errorf("expected remaining subscriber to keep receiving, got %d events", len(second))
+	}
+}
+
+func TestSubscribe_SlowSubscriberDrops(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{})
+	sm.releaseSemaphore()
+	_, unsub := sm.Subscribe()
+	defer unsub()
+
+	for i := 0; i < shadowEventBuffer+3; i++ {
+		sm.ShadowRefund(context.Background(), uint64(i))
+	}
+
+	if dropped := sm.Snapshot().DroppedEvents; dropped != 3 {
+		t.Errorf("expected 3 dropped events, got %d", dropped)
+	}
+}
+
+func TestRedactAddress(t *testing.T) {
+	addr := "GABCDEFGHIJKLMNOPQRSTUVWXYZ234567ABCDEFGHIJKLMNOPQRSTXYZ"
+	if got := redactAddress(addr, true); got != "GABC…XYZ" {
+		t.Errorf("expected GABC…XYZ, got %q", got)
+	}
+	if got := redactAddress(addr, false); got != addr {
+		t.Errorf("expected address unchanged without redaction, got %q", got)
+	}
+	if got := redactAddress("GABC", true); got != "GABC" {
+		t.Errorf("expected short value unchanged, got %q", got)
+	}
+}
+
+func TestShadowLogging_RedactsAddresses(t *testing.T) {
+	var buf bytes.Buffer
+	sm := fullSandbox(t, SandboxConfig{RedactAddresses: true})
+	sm.releaseSemaphore()
+	sm.shadowOps["release_funds"] = true
+	sm.logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+	contributor := keypair.MustRandom().Address()
+	sm.ShadowReleaseFunds(context.Background(), 7, contributor)
+
+	out := buf.String()
+	if strings.Contains(out, contributor) {
+		t.Errorf("expected the address to be redacted, got %s", out)
+	}
+	if want := redactAddress(contributor, true); !strings.Contains(out, "contributor="+want) {
+		t.Errorf("expected redacted contributor %s in %s", want, out)
+	}
+}
+
+func TestShadowLogging_SuccessLevel(t *testing.T) {
+	var buf bytes.Buffer
+	sm := &SandboxManager{
+		config: SandboxConfig{LogLevel: slog.LevelDebug},
+		logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})),
+	}
+
+	sm.logShadowResult("refund", time.Now(), nil, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected debug-level success to be filtered, got %s", buf.String())
+	}
+
+	sm.logShadowResult("refund", time.Now(), nil, errors.New("boom"))
+	if !strings.Contains(buf.String(), "level=WARN") {
+		t.Errorf("expected failures to log at warn, got %s", buf.String())
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	var buf bytes.Buffer
+	sm := fullSandbox(t, SandboxConfig{})
+	sm.releaseSemaphore()
+	sm.shadowOps["batch_payout"] = true
+	sm.logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+	sm.Pause()
+	sm.Pause() // already paused: no second transition
+	if !sm.IsPaused() || !sm.Snapshot().Paused {
+		t.Fatal("expected the manager to report paused")
+	}
+
+	sm.ShadowRefund(context.Background(), 1)
+	sm.ShadowRefund(context.Background(), 2)
+	sm.ShadowBatchPayout(context.Background(), nil)
+
+	snap := sm.Snapshot()
+	if refund := snap.Operations["refund"]; refund.Paused != 2 || refund.Failed != 0 || refund.Dropped != 0 {
+		t.Errorf("expected 2 paused refunds and none run or dropped, got %+v", refund)
+	}
+	if batch := snap.Operations["batch_payout"]; batch.Paused != 1 {
+		t.Errorf("expected batch payout to be counted once, got %+v", batch)
+	}
+
+	sm.Resume()
+	sm.ShadowRefund(context.Background(), 3)
+	if refund := sm.Snapshot().Operations["refund"]; refund.Failed != 1 || refund.Paused != 2 {
+		t.Errorf("expected shadowing to restart with stats kept, got %+v", refund)
+	}
+
+	if n := strings.Count(buf.String(), "sandbox shadowing paused"); n != 1 {
+		t.Errorf("expected a single pause log, got %d", n)
+	}
+	if n := strings.Count(buf.String(), "sandbox shadowing resumed"); n != 1 {
+		t.Errorf("expected a single resume log, got %d", n)
+	}
+}
+
+func TestDeadLetter_RecordsDroppedShadows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureDropAndCount})
+	sink, err := newDeadLetterSink(path, 0, CodecJSONL)
+	if err != nil {
+		t.Fatalf("newDeadLetterSink failed: %v", err)
+	}
+	sm.deadLetter = sink
+
+	sm.ShadowRefund(context.Background(), 42)
+	sm.Close()
+	sm.ShadowRefund(context.Background(), 43) // after Close: counted only
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read dead-letter file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(lines), data)
+	}
+	var entry DeadLetterEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("invalid entry: %v", err)
+	}
+	if entry.Operation != "refund" || entry.Reason != DropAtCapacity || entry.Inputs["bounty_id"] != float64(42) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if dropped := sm.Snapshot().Operations["refund"].Dropped; dropped != 2 {
+		t.Errorf("expected both drops counted, got %d", dropped)
+	}
+}
+
+func TestDeadLetter_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	sink, err := newDeadLetterSink(path, 200, CodecJSONL)
+	if err != nil {
+		t.Fatalf("newDeadLetterSink failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		sink.record(DeadLetterEntry{Operation: "refund", Reason: DropQueueFull, Inputs: map[string]interface{}{"bounty_id": i}})
+	}
+	sink.close()
+
+	for _, p := range []string{path, path + ".1"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", p, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("expected %s to stay within the cap, got %d bytes", p, info.Size())
+		}
+	}
+}
+
+func TestShadow_Generic(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{})
+	sm.releaseSemaphore()
+	events, unsub := sm.Subscribe()
+	defer unsub()
+
+	id, _ := EncodeScValUint64(1)
+	sm.Shadow(context.Background(), "lock_funds", "lock_funds", []xdr.ScVal{id})
+	if len(events) != 0 {
+		t.Fatal("expected an unshadowed operation to be skipped")
+	}
+
+	sm.Shadow(context.Background(), "refund", "refund", []xdr.ScVal{id})
+	select {
+	case ev := <-events:
+		if ev.Operation != "refund" || ev.Err == nil || !strings.Contains(ev.Err.Error(), "invalid contract address") {
+			t.Errorf("expected the refund to reach the sandbox escrow, got %+v", ev)
+		}
+	default:
+		t.Fatal("expected a shadow event")
+	}
+}
+
+func TestSandboxContract_RoutesByOperation(t *testing.T) {
+	sm := &SandboxManager{escrow: &EscrowContract{}, program: &ProgramEscrowContract{}}
+	if sm.contract("batch_payout") != Contract(sm.program) {
+		t.Error("expected batch_payout to run against the program contract")
+	}
+	if sm.contract("refund") != Contract(sm.escrow) {
+		t.Error("expected refund to run against the escrow contract")
+	}
+}
+
+func TestShadowContractEvent(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureDropAndCount})
+
+	event := func(topic string, fields map[string]xdr.ScVal) ContractEvent {
+		sym, _ := EncodeScValSymbol(topic)
+		m := xdr.ScMap{}
+		for name, val := range fields {
+			key, _ := EncodeScValSymbol(name)
+			m = append(m, xdr.ScMapEntry{Key: key, Val: val})
+		}
+		mPtr := &m
+		return ContractEvent{ID: topic, Topics: []xdr.ScVal{sym}, Value: xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}}
+	}
+	bountyID, _ := EncodeScValUint64(7)
+
+	if err := sm.shadowContractEvent(context.Background(), event("f_ref", map[string]xdr.ScVal{"bounty_id": bountyID})); err != nil {
+		t.Fatalf("shadowContractEvent failed: %v", err)
+	}
+	if dropped := sm.Snapshot().Operations["refund"].Dropped; dropped != 1 {
+		t.Errorf("expected the refund event to reach the refund shadow, got %d dropped", dropped)
+	}
+
+	if err := sm.shadowContractEvent(context.Background(), event("f_lock", map[string]xdr.ScVal{"bounty_id": bountyID})); err == nil {
+		t.Error("expected an error for a lock event missing its fields")
+	}
+	if err := sm.shadowContractEvent(context.Background(), event("init", nil)); err != nil {
+		t.Errorf("expected other topics to be ignored, got %v", err)
+	}
+}
+
+func TestStartEventShadowing_Disabled(t *testing.T) {
+	sm, _ := NewSandboxManager(nil, SandboxConfig{})
+	if err := sm.StartEventShadowing(context.Background(), testContractHex); err == nil {
+		t.Error("expected an error when the sandbox is disabled")
+	}
+}
+
+func TestCancelAll_CancelsRunningShadows(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{})
+	sm.config.Synchronous = false
+	sm.releaseSemaphore()
+	events, unsub := sm.Subscribe()
+	defer unsub()
+
+	started := make(chan struct{})
+	sm.shadow(context.Background(), "refund", nil, nil, func(ctx context.Context) (*TransactionResult, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	inFlight := sm.ListInFlight()
+	if len(inFlight) != 1 || inFlight[0].Operation != "refund" || inFlight[0].StartedAt.IsZero() {
+		t.Fatalf("expected the running refund to be listed, got %+v", inFlight)
+	}
+	if n := sm.CancelAll(); n != 1 {
+		t.Errorf("expected 1 canceled shadow, got %d", n)
+	}
+
+	select {
+	case ev := <-events:
+		if !errors.Is(ev.Err, context.Canceled) {
+			t.Errorf("expected the shadow to fail with context.Canceled, got %v", ev.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("canceled shadow did not finish")
+	}
+	if inFlight := sm.ListInFlight(); len(inFlight) != 0 {
+		t.Errorf("expected finished shadows to be removed, got %+v", inFlight)
+	}
+}
+
+func TestShadow_ContextDetachment(t *testing.T) {
+	run := func(disable bool) error {
+		t.Helper()
+		sm := fullSandbox(t, SandboxConfig{DisableContextDetach: disable})
+		sm.config.Synchronous = false
+		sm.releaseSemaphore()
+		events, unsub := sm.Subscribe()
+		defer unsub()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		sm.shadow(ctx, "refund", nil, nil, func(ctx context.Context) (*TransactionResult, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return nil, nil
+			}
+		})
+		<-started
+		cancel()
+
+		select {
+		case ev := <-events:
+			return ev.Err
+		case <-time.After(time.Second):
+			t.Fatal("shadow did not finish")
+			return nil
+		}
+	}
+
+	if err := run(false); err != nil {
+		t.Errorf("expected a detached shadow to outlive its parent context, got %v", err)
+	}
+	if err := run(true); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceling the parent to stop a non-detached shadow, got %v", err)
+	}
+}
+
+func TestListInFlight_Disabled(t *testing.T) {
+	sm, _ := NewSandboxManager(nil, SandboxConfig{})
+	if inFlight := sm.ListInFlight(); len(inFlight) != 0 {
+		t.Errorf("expected nothing in flight, got %+v", inFlight)
+	}
+	if n := sm.CancelAll(); n != 0 {
+		t.Errorf("expected nothing to cancel, got %d", n)
+	}
+}
+
+func TestAmountAttrs(t *testing.T) {
+	sm := &SandboxManager{}
+	attrs := sm.amountAttrs("amount", 12345000)
+	if len(attrs) != 4 || attrs[1] != int64(12345000) || attrs[3] != "base_units" {
+		t.Errorf("expected a raw amount before the token is known, got %v", attrs)
+	}
+
+	sm.amounts.formatter.Store(&AmountFormatter{Decimals: 7, Symbol: "USDC"})
+	attrs = sm.amountAttrs("amount", 12345000)
+	if len(attrs) != 2 || attrs[1] != "1.2345 USDC" {
+		t.Errorf("expected a formatted amount, got %v", attrs)
+	}
+}
+
+func TestComparisonReport(t *testing.T) {
+	sm := &SandboxManager{}
+	for i := 0; i < 3; i++ {
+		sm.RecordComparison(ShadowComparison{Operation: "lock_funds"})
+	}
+	sm.RecordComparison(ShadowComparison{Operation: "lock_funds", Divergence: "fee_mismatch"})
+	sm.RecordComparison(ShadowComparison{Operation: "refund", Divergence: "fee_mismatch"})
+	sm.RecordComparison(ShadowComparison{Operation: "refund", Divergence: "fee_mismatch"})
+	sm.RecordComparison(ShadowComparison{Operation: "refund", Divergence: "status_mismatch", At: time.Now().Add(-time.Hour)})
+
+	report := sm.ComparisonReport(time.Minute)
+	if st := report.Operations["lock_funds"]; st.Compared != 4 || st.Agreed != 3 || st.AgreementRate != 0.75 {
+		t.Errorf("unexpected lock_funds agreement %+v", st)
+	}
+	if st := report.Operations["refund"]; st.Compared != 2 || st.AgreementRate != 0 {
+		t.Errorf("expected the old refund comparison outside the window, got %+v", st)
+	}
+	want := []DivergenceCount{
+		{Operation: "refund", Signature: "fee_mismatch", Count: 2},
+		{Operation: "lock_funds", Signature: "fee_mismatch", Count: 1},
+	}
+	if !reflect.DeepEqual(report.TopDivergences, want) {
+		t.Errorf("expected %+v, got %+v", want, report.TopDivergences)
+	}
+}
+
+func TestComparisonRing_Bounded(t *testing.T) {
+	var r comparisonRing
+	for i := 0; i < maxComparisons+5; i++ {
+		r.add(ShadowComparison{Operation: fmt.Sprint(i), At: time.Now()})
+	}
+	got := r.since(time.Time{})
+	if len(got) != maxComparisons {
+		t.Fatalf("expected %d retained comparisons, got %d", maxComparisons, len(got))
+	}
+	for _, c := range got {
+		if c.Operation == "0" {
+			t.Fatal("expected the oldest comparison to be overwritten")
+		}
+	}
+}
+
+func TestSaturationSampler(t *testing.T) {
+	s := newSaturationSampler(time.Minute)
+	now := time.Unix(1000, 0)
+	s.sample(now, true)
+	s.sample(now, false)
+	s.sample(now.Add(10*time.Second), false)
+	s.sample(now.Add(10*time.Second), false)
+
+	if ratio, samples := s.ratio(now.Add(10 * time.Second)); ratio != 0.25 || samples != 4 {
+		t.Errorf("expected 1 of 4 launches saturated, got %v of %d", ratio, samples)
+	}
+	// The first samples have left the window
+	if ratio, samples := s.ratio(now.Add(65 * time.Second)); ratio != 0 || samples != 2 {
+		t.Errorf("expected only the later samples in the window, got %v of %d", ratio, samples)
+	}
+	if _, samples := s.ratio(now.Add(time.Hour)); samples != 0 {
+		t.Errorf("expected an empty window, got %d samples", samples)
+	}
+}
+
+func TestSnapshot_Saturation(t *testing.T) {
+	sm := fullSandbox(t, SandboxConfig{BackpressurePolicy: BackpressureDropAndCount})
+	sm.ShadowRefund(context.Background(), 1)
+	sm.ShadowRefund(context.Background(), 2)
+
+	snap := sm.Snapshot()
+	if snap.SaturationRatio != 1 || snap.SaturationSamples != 2 || snap.CapacityDrops != 2 {
+		t.Errorf("expected 2 saturated launches and drops, got ratio %v of %d, %d drops",
+			snap.SaturationRatio, snap.SaturationSamples, snap.CapacityDrops)
+	}
+	if snap.DropsByReason[DropAtCapacity] != 2 || len(snap.DropsByReason) != 1 {
+		t.Errorf("expected both drops reported as at_capacity, got %v", snap.DropsByReason)
+	}
+}
@@ -0,0 +1,87 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Escrow event topics emitted by the production contract (see events.rs)
+const (
+	eventFundsLocked   = "f_lock"
+	eventFundsReleased = "f_rel"
+	eventFundsRefunded = "f_ref"
+)
+
+// StartEventShadowing streams productionContractID's events and shadows each
+// lock, release and refund it sees, so production code doesn't need to call
+// the Shadow* methods itself. Shadows go through the usual gates (shadowed
+// operations, pause and backpressure). Events that can't be decoded are
+// logged and skipped. Shadowing stops when ctx is done.
+func (sm *SandboxManager) StartEventShadowing(ctx context.Context, productionContractID string) error {
+	if !sm.config.Enabled {
+		return fmt.Errorf("sandbox: event shadowing requires the sandbox to be enabled")
+	}
+
+	stream, err := sm.escrow.client.StreamEvents(ctx, productionContractID, 0, 0)
+	if err != nil {
+		return fmt.Errorf("sandbox: failed to stream events: %w", err)
+	}
+
+	go func() {
+		for event := range stream.Events {
+			if err := sm.shadowContractEvent(ctx, event); err != nil {
+				sm.log().Warn("skipping undecodable production event", "event_id", event.ID, "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// shadowContractEvent fires the shadow matching a production escrow event.
+// Events with other topics are ignored.
+func (sm *SandboxManager) shadowContractEvent(ctx context.Context, event ContractEvent) error {
+	if len(event.Topics) == 0 || event.Topics[0].Type != xdr.ScValTypeScvSymbol {
+		return nil
+	}
+	topic := string(*event.Topics[0].Sym)
+	if topic != eventFundsLocked && topic != eventFundsReleased && topic != eventFundsRefunded {
+		return nil
+	}
+
+	fields, err := DecodeScValStruct(event.Value)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", topic, err)
+	}
+	bountyID, err := structField(fields, "bounty_id", DecodeScValUint64)
+	if err != nil {
+		return err
+	}
+
+	switch topic {
+	case eventFundsLocked:
+		depositor, err := structField(fields, "depositor", decodeScValAddressString)
+		if err != nil {
+			return err
+		}
+		amount, err := structField(fields, "amount", DecodeScValInt64)
+		if err != nil {
+			return err
+		}
+		deadline, err := structField(fields, "deadline", DecodeScValUint64)
+		if err != nil {
+			return err
+		}
+		sm.ShadowLockFunds(ctx, depositor, bountyID, amount, int64(deadline))
+	case eventFundsReleased:
+		recipient, err := structField(fields, "recipient", decodeScValAddressString)
+		if err != nil {
+			return err
+		}
+		sm.ShadowReleaseFunds(ctx, bountyID, recipient)
+	case eventFundsRefunded:
+		sm.ShadowRefund(ctx, bountyID)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package soroban
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// SequenceCoordinator serializes sequence use across TransactionBuilders that
+// share a source account. Without it, builders sharing a source race for the
+// same sequence number, and each tx_bad_seq sends every one of them back to
+// reload it, where they collide again. With it, builders with the same
+// source take turns from loading the sequence until their submission
+// settles, and each one starts from the sequence the previous one advanced,
+// so a stale sequence is reloaded once rather than by the whole herd.
+// Builders with distinct sources don't wait on each other.
+//
+// Giving each builder its own source account, as SandboxManager does, is
+// the better fix; the coordinator makes sharing one survivable. The zero
+// value is ready to use.
+type SequenceCoordinator struct {
+	mu      sync.Mutex
+	sources map[string]*sharedSequence
+}
+
+// NewSequenceCoordinator creates a coordinator to set as the Sequences field
+// of each builder sharing a source
+func NewSequenceCoordinator() *SequenceCoordinator {
+	return &SequenceCoordinator{}
+}
+
+// sharedSequence is one source account's turn and the sequence the last
+// successful submission left it at
+type sharedSequence struct {
+	turn    chan struct{}
+	account *txnbuild.SimpleAccount // nil means reload before the next use
+}
+
+// acquire waits for source's turn. The returned sequence must be released.
+// A nil coordinator returns a nil sequence, which does nothing.
+func (c *SequenceCoordinator) acquire(ctx context.Context, source string) (*sharedSequence, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	if c.sources == nil {
+		c.sources = make(map[string]*sharedSequence)
+	}
+	s, ok := c.sources[source]
+	if !ok {
+		s = &sharedSequence{turn: make(chan struct{}, 1)}
+		c.sources[source] = s
+	}
+	c.mu.Unlock()
+
+	select {
+	case s.turn <- struct{}{}:
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take returns the account left by the last successful submission, if any,
+// and clears it so a failed submission forces a reload
+func (s *sharedSequence) take() *txnbuild.SimpleAccount {
+	if s == nil {
+		return nil
+	}
+	account := s.account
+	s.account = nil
+	return account
+}
+
+// advance records the account a submitted transaction advanced
+func (s *sharedSequence) advance(account txnbuild.Account) {
+	if s == nil {
+		return
+	}
+	seq, err := account.GetSequenceNumber()
+	if err != nil {
+		return
+	}
+	s.account = &txnbuild.SimpleAccount{AccountID: account.GetAccountID(), Sequence: seq}
+}
+
+// release ends the holder's turn
+func (s *sharedSequence) release() {
+	if s == nil {
+		return
+	}
+	<-s.turn
+}
@@ -0,0 +1,56 @@
+package soroban
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Signer signs transactions on behalf of a single account. Implementations
+// may keep the key outside the process, e.g. in a KMS or HSM.
+type Signer interface {
+	// Sign returns tx with the account's signature attached
+	Sign(tx *txnbuild.Transaction) (*txnbuild.Transaction, error)
+	// PublicKey returns the account's G... address
+	PublicKey() string
+}
+
+// LocalSigner signs with a secret key held in process memory
+type LocalSigner struct {
+	kp                *keypair.Full
+	networkPassphrase string
+}
+
+// NewLocalSigner creates a signer from an S... secret for the given network
+func NewLocalSigner(secret, networkPassphrase string) (*LocalSigner, error) {
+	kp, err := keypair.ParseFull(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source secret: %w", err)
+	}
+	return newLocalSigner(kp, networkPassphrase), nil
+}
+
+func newLocalSigner(kp *keypair.Full, networkPassphrase string) *LocalSigner {
+	return &LocalSigner{kp: kp, networkPassphrase: networkPassphrase}
+}
+
+// Sign signs tx for the signer's network
+func (s *LocalSigner) Sign(tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+	return tx.Sign(s.networkPassphrase, s.kp)
+}
+
+// PublicKey returns the signer's address
+func (s *LocalSigner) PublicKey() string {
+	return s.kp.Address()
+}
+
+// authKey returns the key used to sign Soroban authorization entries, which
+// only local signers can provide. Remote signers rely on source-account
+// authorization, which is covered by the transaction signature.
+func authKey(s Signer) *keypair.Full {
+	if local, ok := s.(*LocalSigner); ok {
+		return local.kp
+	}
+	return nil
+}
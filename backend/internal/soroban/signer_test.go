@@ -0,0 +1,101 @@
+package soroban
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// remoteSigner stands in for a KMS-backed signer that never exposes its key
+type remoteSigner struct {
+	kp    *keypair.Full
+	calls int
+}
+
+func (s *remoteSigner) Sign(tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+	s.calls++
+	return tx.Sign(network.TestNetworkPassphrase, s.kp)
+}
+
+func (s *remoteSigner) PublicKey() string { return s.kp.Address() }
+
+func TestLocalSigner_Sign(t *testing.T) {
+	kp := keypair.MustRandom()
+	signer, err := NewLocalSigner(kp.Seed(), network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatalf("NewLocalSigner failed: %v", err)
+	}
+	if signer.PublicKey() != kp.Address() {
+		t.Errorf("expected public key %s, got %s", kp.Address(), signer.PublicKey())
+	}
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	signed, err := signer.Sign(tx)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	hash, _ := signed.Hash(network.TestNetworkPassphrase)
+	sigs := signed.Signatures()
+	if len(sigs) != 1 || kp.Verify(hash[:], sigs[0].Signature) != nil {
+		t.Error("expected a valid signature for the network")
+	}
+}
+
+func TestNewLocalSigner_InvalidSecret(t *testing.T) {
+	if _, err := NewLocalSigner("not-a-secret", network.TestNetworkPassphrase); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+}
+
+func TestWithSigner_RemoteSigner(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://localhost", Network: NetworkTestnet})
+	remote := &remoteSigner{kp: keypair.MustRandom()}
+	tb, err := NewTransactionBuilderWithSigner(client, remote, DefaultRetryConfig())
+	if err != nil {
+		t.Fatalf("NewTransactionBuilderWithSigner failed: %v", err)
+	}
+
+	if tb.signer.PublicKey() != remote.PublicKey() {
+		t.Errorf("expected builder source %s, got %s", remote.PublicKey(), tb.signer.PublicKey())
+	}
+	if authKey(tb.signer) != nil {
+		t.Error("expected remote signers to expose no auth key")
+	}
+
+	admin := keypair.MustRandom()
+	derived := tb.withSigner(admin)
+	if derived.signer.PublicKey() != admin.Address() || authKey(derived.signer) == nil {
+		t.Error("expected withSigner to switch to a local admin signer")
+	}
+	if tb.signer != remote {
+		t.Error("expected the original builder to be unchanged")
+	}
+}
+
+func TestSignAuthEntries_NoAuthKey(t *testing.T) {
+	kp := keypair.MustRandom()
+	addr, _ := xdr.AddressToAccountId(kp.Address())
+	entry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &addr},
+			},
+		},
+	}
+	if _, err := signAuthEntries([]xdr.SorobanAuthorizationEntry{entry}, nil, network.TestNetworkPassphrase, 1); err == nil {
+		t.Error("expected an error when no key can sign address credentials")
+	}
+}
@@ -0,0 +1,180 @@
+package soroban
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// SimResult represents the decoded response of a simulateTransaction call
+type SimResult struct {
+	MinResourceFee  int64                       `json:"min_resource_fee"`
+	TransactionData *xdr.SorobanTransactionData `json:"-"`
+	Results         []SimHostFunctionResult     `json:"-"`
+	LatestLedger    uint32                      `json:"latest_ledger"`
+	// Resources is nil when the simulation returned no transaction data
+	Resources *ResourceUsage `json:"resources,omitempty"`
+}
+
+// SimHostFunctionResult holds the return value and the authorization entries
+// required by a single simulated host function invocation
+type SimHostFunctionResult struct {
+	ReturnValue xdr.ScVal
+	Auth        []xdr.SorobanAuthorizationEntry
+}
+
+// Simulate runs the operations through simulateTransaction without signing or
+// submitting them. The builder's source account is used as the transaction source.
+func (tb *TransactionBuilder) Simulate(ctx context.Context, operations []txnbuild.Operation) (*SimResult, error) {
+	account, err := tb.loadSourceAccount()
+	if err != nil {
+		return nil, err
+	}
+	// Simulating doesn't use up the sequence, so a prewarmed account stays
+	tb.account.release(account)
+
+	return tb.simulate(ctx, account, operations)
+}
+
+// simulatePreview simulates operations for planning purposes. Simulation
+// ignores the sequence number, so the source account isn't looked up.
+func (tb *TransactionBuilder) simulatePreview(ctx context.Context, operations []txnbuild.Operation) (*SimResult, error) {
+	return tb.simulate(ctx, &txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey()}, operations)
+}
+
+// simulate builds an unsigned transaction for the given account and simulates it
+func (tb *TransactionBuilder) simulate(ctx context.Context, account txnbuild.Account, operations []txnbuild.Operation) (*SimResult, error) {
+	if timeout := tb.retryConfig.SimulateTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Work on a copy so simulation never advances the caller's sequence number
+	simAccount := &txnbuild.SimpleAccount{
+		AccountID: account.GetAccountID(),
+	}
+	seq, err := account.GetSequenceNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sequence number: %w", err)
+	}
+	simAccount.Sequence = seq
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        simAccount,
+			IncrementSequenceNum: true,
+			BaseFee:              txnbuild.MinBaseFee,
+			Operations:           operations,
+			Preconditions: txnbuild.Preconditions{
+				TimeBounds: txnbuild.NewInfiniteTimeout(),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build simulation transaction: %w", err)
+	}
+
+	txXDR, err := tx.Base64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode simulation transaction: %w", err)
+	}
+
+	raw, err := tb.client.SimulateTransaction(ctx, txXDR)
+	if err != nil {
+		return nil, fmt.Errorf("simulation failed: %w", err)
+	}
+
+	return parseSimResult(raw, tb.client.returnLimit(ctx))
+}
+
+// parseSimResult decodes the JSON result of simulateTransaction. Return values
+// larger than maxReturnBytes are rejected before they are unmarshaled.
+func parseSimResult(raw map[string]interface{}, maxReturnBytes int) (*SimResult, error) {
+	if simErr, ok := raw["error"].(string); ok && simErr != "" {
+		// Attach the revert reason from the diagnostic events when available
+		events, _ := ExtractDiagnostics(raw)
+		if isMissingFunction(simErr, events) {
+			return nil, fmt.Errorf("%w: simulation error: %s", ErrFunctionNotImplemented, simErr)
+		}
+		if top, ok := topDiagnostic(events); ok {
+			return nil, fmt.Errorf("simulation error: %s: %s", simErr, top)
+		}
+		return nil, fmt.Errorf("simulation error: %s", simErr)
+	}
+
+	result := &SimResult{}
+
+	if latest, ok := raw["latestLedger"].(float64); ok {
+		result.LatestLedger = uint32(latest)
+	}
+
+	// minResourceFee is a stringified int64 in the RPC response
+	if feeStr, ok := raw["minResourceFee"].(string); ok && feeStr != "" {
+		fee, err := strconv.ParseInt(feeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minResourceFee %q: %w", feeStr, err)
+		}
+		result.MinResourceFee = fee
+	}
+
+	if txData, ok := raw["transactionData"].(string); ok && txData != "" {
+		var data xdr.SorobanTransactionData
+		if err := xdr.SafeUnmarshalBase64(txData, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode transactionData: %w", err)
+		}
+		result.TransactionData = &data
+
+		usage := newResourceUsage(data)
+		usage.applyCost(raw)
+		result.Resources = &usage
+	}
+
+	rawResults, _ := raw["results"].([]interface{})
+	for i, r := range rawResults {
+		entry, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid simulation result %d", i)
+		}
+
+		var hostResult SimHostFunctionResult
+		if retXDR, ok := entry["xdr"].(string); ok && retXDR != "" {
+			if size := base64.StdEncoding.DecodedLen(len(retXDR)); maxReturnBytes > 0 && size > maxReturnBytes {
+				return nil, fmt.Errorf("return value %d is ~%d bytes (limit %d): %w", i, size, maxReturnBytes, ErrResultTooLarge)
+			}
+			if err := xdr.SafeUnmarshalBase64(retXDR, &hostResult.ReturnValue); err != nil {
+				return nil, fmt.Errorf("failed to decode return value %d: %w", i, err)
+			}
+		}
+
+		rawAuth, _ := entry["auth"].([]interface{})
+		for j, a := range rawAuth {
+			authXDR, ok := a.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid auth entry %d of result %d", j, i)
+			}
+			var authEntry xdr.SorobanAuthorizationEntry
+			if err := xdr.SafeUnmarshalBase64(authXDR, &authEntry); err != nil {
+				return nil, fmt.Errorf("failed to decode auth entry %d of result %d: %w", j, i, err)
+			}
+			hostResult.Auth = append(hostResult.Auth, authEntry)
+		}
+
+		result.Results = append(result.Results, hostResult)
+	}
+
+	return result, nil
+}
+
+// ParseReturnValue returns the return value of the single host function
+// invoked in a simulation, guarding against empty or missing results
+func ParseReturnValue(result *SimResult) (xdr.ScVal, error) {
+	if result == nil || len(result.Results) == 0 {
+		return xdr.ScVal{}, fmt.Errorf("no results returned from simulation")
+	}
+	return result.Results[0].ReturnValue, nil
+}
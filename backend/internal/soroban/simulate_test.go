@@ -0,0 +1,247 @@
+package soroban
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+func TestParseSimResult(t *testing.T) {
+	retVal, _ := EncodeScValInt64(42)
+	retXDR, err := xdr.MarshalBase64(retVal)
+	if err != nil {
+		t.Fatalf("failed to encode return value: %v", err)
+	}
+
+	raw := map[string]interface{}{
+		"latestLedger":   float64(1234),
+		"minResourceFee": "58181",
+		"results": []interface{}{
+			map[string]interface{}{
+				"xdr":  retXDR,
+				"auth": []interface{}{},
+			},
+		},
+	}
+
+	sim, err := parseSimResult(raw, DefaultMaxReturnBytes)
+	if err != nil {
+		t.Fatalf("parseSimResult failed: %v", err)
+	}
+	if sim.LatestLedger != 1234 {
+		t.Errorf("expected latest ledger 1234, got %d", sim.LatestLedger)
+	}
+	if sim.MinResourceFee != 58181 {
+		t.Errorf("expected min resource fee 58181, got %d", sim.MinResourceFee)
+	}
+	if len(sim.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(sim.Results))
+	}
+	if sim.Results[0].ReturnValue.I64 == nil || *sim.Results[0].ReturnValue.I64 != 42 {
+		t.Errorf("expected return value 42, got %v", sim.Results[0].ReturnValue)
+	}
+}
+
+func TestParseSimResult_Resources(t *testing.T) {
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{}}
+	txData, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{
+			Footprint:     xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{key}, ReadWrite: []xdr.LedgerKey{key, key}},
+			Instructions:  2_000_000,
+			DiskReadBytes: 3000,
+			WriteBytes:    400,
+		},
+		ResourceFee: 90000,
+	})
+	raw := map[string]interface{}{
+		"transactionData": txData,
+		"cost":            map[string]interface{}{"cpuInsns": "1900000", "memBytes": "524288"},
+	}
+
+	sim, err := parseSimResult(raw, DefaultMaxReturnBytes)
+	if err != nil {
+		t.Fatalf("parseSimResult failed: %v", err)
+	}
+	want := ResourceUsage{
+		Instructions:  2_000_000,
+		DiskReadBytes: 3000,
+		WriteBytes:    400,
+		ReadEntries:   3,
+		WriteEntries:  2,
+		MemoryBytes:   524288,
+		ResourceFee:   90000,
+	}
+	if sim.Resources == nil || *sim.Resources != want {
+		t.Errorf("expected %+v, got %+v", want, sim.Resources)
+	}
+
+	sim, _ = parseSimResult(map[string]interface{}{}, DefaultMaxReturnBytes)
+	if sim.Resources != nil {
+		t.Error("expected no resources without transaction data")
+	}
+}
+
+func TestParseSimResult_Error(t *testing.T) {
+	raw := map[string]interface{}{
+		"error": "HostError: Error(Contract, #6)",
+	}
+	if _, err := parseSimResult(raw, DefaultMaxReturnBytes); err == nil {
+		t.Error("expected error for failed simulation")
+	}
+}
+
+func TestParseSimResult_ResultTooLarge(t *testing.T) {
+	vals := make([]xdr.ScVal, 100)
+	for i := range vals {
+		vals[i], _ = EncodeScValUint64(uint64(i))
+	}
+	retVal, _ := EncodeScValVec(vals)
+	retXDR, _ := xdr.MarshalBase64(retVal)
+
+	raw := map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"xdr": retXDR},
+		},
+	}
+
+	if _, err := parseSimResult(raw, 256); !errors.Is(err, ErrResultTooLarge) {
+		t.Errorf("expected ErrResultTooLarge, got %v", err)
+	}
+	if _, err := parseSimResult(raw, 4096); err != nil {
+		t.Errorf("expected result within a larger limit to decode, got %v", err)
+	}
+}
+
+func TestClientReturnLimit(t *testing.T) {
+	client, err := NewClient(Config{RPCURL: "http://127.0.0.1:0", MaxReturnBytes: 1024})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if got := client.returnLimit(ctx); got != 1024 {
+		t.Errorf("expected configured limit 1024, got %d", got)
+	}
+	if got := client.returnLimit(WithMaxReturnBytes(ctx, 1<<24)); got != 1<<24 {
+		t.Errorf("expected per-call override, got %d", got)
+	}
+}
+
+func TestSignAuthEntries(t *testing.T) {
+	signer := keypair.MustRandom()
+	accountID, err := xdr.AddressToAccountId(signer.Address())
+	if err != nil {
+		t.Fatalf("failed to build account id: %v", err)
+	}
+
+	contractAddr, err := EncodeContractAddress("0000000000000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatalf("failed to encode contract address: %v", err)
+	}
+
+	entry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address: xdr.ScAddress{
+					Type:      xdr.ScAddressTypeScAddressTypeAccount,
+					AccountId: &accountID,
+				},
+				Nonce:     7,
+				Signature: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: contractAddr,
+					FunctionName:    "lock_funds",
+				},
+			},
+		},
+	}
+	sourceEntry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount,
+		},
+		RootInvocation: entry.RootInvocation,
+	}
+
+	signed, err := signAuthEntries([]xdr.SorobanAuthorizationEntry{entry, sourceEntry}, signer, network.TestNetworkPassphrase, 100)
+	if err != nil {
+		t.Fatalf("signAuthEntries failed: %v", err)
+	}
+	if len(signed) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(signed))
+	}
+	if signed[1].Credentials.Type != xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount {
+		t.Error("expected source-account entry to pass through unchanged")
+	}
+
+	creds := signed[0].Credentials.Address
+	if uint32(creds.SignatureExpirationLedger) != 100+authSignatureValidityLedgers {
+		t.Errorf("unexpected expiration ledger %d", creds.SignatureExpirationLedger)
+	}
+	if creds.Signature.Vec == nil || len(**creds.Signature.Vec) != 1 {
+		t.Fatalf("expected signature vector with one entry")
+	}
+
+	// Verify the signature against the preimage the host will reconstruct.
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 xdr.Hash(network.ID(network.TestNetworkPassphrase)),
+			Nonce:                     creds.Nonce,
+			SignatureExpirationLedger: creds.SignatureExpirationLedger,
+			Invocation:                signed[0].RootInvocation,
+		},
+	}
+	payload, err := preimage.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode preimage: %v", err)
+	}
+	hash := sha256.Sum256(payload)
+
+	sigMap := **(**creds.Signature.Vec)[0].Map
+	sig := *sigMap[1].Val.Bytes
+	if err := signer.Verify(hash[:], sig); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+}
+
+func TestSignAuthEntries_ForeignAddress(t *testing.T) {
+	signer := keypair.MustRandom()
+	other := keypair.MustRandom()
+	accountID, _ := xdr.AddressToAccountId(other.Address())
+
+	entry := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address: xdr.ScAddress{
+					Type:      xdr.ScAddressTypeScAddressTypeAccount,
+					AccountId: &accountID,
+				},
+			},
+		},
+	}
+
+	if _, err := signAuthEntries([]xdr.SorobanAuthorizationEntry{entry}, signer, network.TestNetworkPassphrase, 1); err == nil {
+		t.Error("expected error when auth entry belongs to another account")
+	}
+}
+
+func TestParseReturnValue_NoResults(t *testing.T) {
+	if _, err := ParseReturnValue(nil); err == nil {
+		t.Error("expected error for nil result")
+	}
+	if _, err := ParseReturnValue(&SimResult{}); err == nil {
+		t.Error("expected error for empty results")
+	}
+}
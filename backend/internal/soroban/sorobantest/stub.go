@@ -0,0 +1,102 @@
+// Package sorobantest provides test doubles for the soroban package.
+package sorobantest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jagadeesh/grainlify/backend/internal/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+// StubClient is an in-process Soroban RPC that answers each method with a
+// canned result, so the client's encoding and decoding paths can be
+// benchmarked without network noise. Methods without a result get a
+// method-not-found error. Horizon isn't stubbed, so a submission's source
+// account must be prewarmed rather than loaded.
+type StubClient struct {
+	server *httptest.Server
+
+	mu      sync.RWMutex
+	results map[string]json.RawMessage
+}
+
+// NewStubClient starts a stub that is shut down when tb finishes
+func NewStubClient(tb testing.TB) *StubClient {
+	tb.Helper()
+	s := &StubClient{results: make(map[string]json.RawMessage)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	tb.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *StubClient) serve(w http.ResponseWriter, r *http.Request) {
+	var req soroban.RPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	result, ok := s.results[req.Method]
+	s.mu.RUnlock()
+
+	resp := soroban.RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if !ok {
+		resp.Error = &soroban.RPCError{Code: -32601, Message: "method not found: " + req.Method}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Client returns a client pointed at the stub
+func (s *StubClient) Client() (*soroban.Client, error) {
+	return soroban.NewClient(soroban.Config{RPCURL: s.server.URL})
+}
+
+// SetResult answers method with result, a JSON document
+func (s *StubClient) SetResult(method, result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[method] = json.RawMessage(result)
+}
+
+// SetReturnValue answers simulateTransaction with a successful simulation
+// returning v
+func (s *StubClient) SetReturnValue(v xdr.ScVal) error {
+	encoded, err := xdr.MarshalBase64(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode return value: %w", err)
+	}
+	s.SetResult("simulateTransaction", fmt.Sprintf(`{"latestLedger":1,"results":[{"xdr":%q}]}`, encoded))
+	return nil
+}
+
+// SetAccount answers getLedgerEntries with address's account entry at seq,
+// so TransactionBuilder.VerifyAccount and PrewarmSequence succeed
+func (s *StubClient) SetAccount(address string, seq int64) error {
+	accountID, err := xdr.AddressToAccountId(address)
+	if err != nil {
+		return fmt.Errorf("invalid account: %w", err)
+	}
+	key, err := xdr.MarshalBase64(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %w", err)
+	}
+	data, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: accountID, SeqNum: xdr.SequenceNumber(seq)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	s.SetResult("getLedgerEntries", fmt.Sprintf(`{"entries":[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":1}],"latestLedger":1}`, key, data))
+	return nil
+}
@@ -0,0 +1,94 @@
+package sorobantest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jagadeesh/grainlify/backend/internal/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+const testContract = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// safeReport is a simulate_upgrade return value with every check passed
+func safeReport(t testing.TB) xdr.ScVal {
+	t.Helper()
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := soroban.EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	passed, failed, isSafe := xdr.Uint32(10), xdr.Uint32(0), true
+	m := xdr.ScMap{
+		field("checks_failed", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &failed}),
+		field("checks_passed", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &passed}),
+		field("errors", soroban.NewScValVec()),
+		field("is_safe", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &isSafe}),
+		field("warnings", soroban.NewScValVec()),
+	}
+	mPtr := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+}
+
+// upgradeClient returns an upgrade safety client whose RPC is a stub
+// simulating a safe upgrade
+func upgradeClient(t testing.TB) *soroban.UpgradeSafetyClient {
+	t.Helper()
+	stub := NewStubClient(t)
+	kp := keypair.MustRandom()
+	if err := stub.SetReturnValue(safeReport(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := stub.SetAccount(kp.Address(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := stub.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, err := soroban.NewTransactionBuilder(client, kp.Seed(), soroban.DefaultRetryConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tb.PrewarmSequence(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	u, err := soroban.NewUpgradeSafetyClient(client, tb, testContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestStubClient_SimulateUpgrade(t *testing.T) {
+	u := upgradeClient(t)
+	for i := 0; i < 2; i++ {
+		report, err := u.SimulateUpgrade(context.Background())
+		if err != nil {
+			t.Fatalf("SimulateUpgrade failed: %v", err)
+		}
+		if !report.IsSafe || report.ChecksPassed != 10 {
+			t.Errorf("unexpected report %+v", report)
+		}
+	}
+}
+
+func TestStubClient_UnknownMethod(t *testing.T) {
+	client, _ := NewStubClient(t).Client()
+	if _, err := client.GetLatestLedger(context.Background()); err == nil {
+		t.Error("expected an error for a method without a result")
+	}
+}
+
+func BenchmarkSimulateUpgrade(b *testing.B) {
+	u := upgradeClient(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := u.SimulateUpgrade(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// TokenContract reads from a Stellar Asset Contract or other SEP-41 token
+type TokenContract struct {
+	client          *Client
+	txBuilder       *TransactionBuilder
+	contractAddress string
+}
+
+// NewTokenContract creates a new token contract client
+func NewTokenContract(client *Client, txBuilder *TransactionBuilder, contractAddress string) *TokenContract {
+	return &TokenContract{
+		client:          client,
+		txBuilder:       txBuilder,
+		contractAddress: contractAddress,
+	}
+}
+
+// Balance returns the token balance of address (read-only, uses RPC simulation)
+func (tc *TokenContract) Balance(ctx context.Context, address string) (int64, error) {
+	contractAddr, err := EncodeContractAddress(tc.contractAddress)
+	if err != nil {
+		return 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	addrVal, err := EncodeScValAddress(address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "balance", []xdr.ScVal{addrVal})
+	if err != nil {
+		return 0, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := tc.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	ret, err := ParseReturnValue(sim)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, err := DecodeScValInt64(ret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse balance: %w", err)
+	}
+	return balance, nil
+}
+
+// Decimals returns the number of decimals the token uses
+func (tc *TokenContract) Decimals(ctx context.Context) (uint32, error) {
+	ret, err := tc.read(ctx, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals, err := DecodeScValUint32(ret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse decimals: %w", err)
+	}
+	return decimals, nil
+}
+
+// Symbol returns the token's symbol, e.g. "USDC"
+func (tc *TokenContract) Symbol(ctx context.Context) (string, error) {
+	ret, err := tc.read(ctx, "symbol")
+	if err != nil {
+		return "", err
+	}
+	symbol, err := DecodeScValString(ret)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse symbol: %w", err)
+	}
+	return symbol, nil
+}
+
+// read simulates a token function that takes no arguments
+func (tc *TokenContract) read(ctx context.Context, fn string) (xdr.ScVal, error) {
+	contractAddr, err := EncodeContractAddress(tc.contractAddress)
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, fn, []xdr.ScVal{})
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := tc.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to get %s: %w", fn, err)
+	}
+	return ParseReturnValue(sim)
+}
+
+// TokenConfig describes the token an escrow holds
+type TokenConfig struct {
+	Address  string `json:"address"`
+	Decimals uint32 `json:"decimals"`
+	Symbol   string `json:"symbol"`
+}
+
+// Formatter returns an AmountFormatter for the token
+func (c TokenConfig) Formatter() AmountFormatter {
+	return AmountFormatter{Decimals: int(c.Decimals), Symbol: c.Symbol}
+}
+
+// GetTokenConfig returns the escrow's token and its metadata. The result is
+// cached, since the token is fixed at init; call InvalidateTokenConfig to
+// force a re-read.
+func (ec *EscrowContract) GetTokenConfig(ctx context.Context) (TokenConfig, error) {
+	ec.tokenMu.Lock()
+	defer ec.tokenMu.Unlock()
+	if ec.tokenConfig != nil {
+		return *ec.tokenConfig, nil
+	}
+
+	address, err := ec.tokenAddress(ctx)
+	if err != nil {
+		return TokenConfig{}, err
+	}
+
+	token := NewTokenContract(ec.client, ec.txBuilder, address)
+	decimals, err := token.Decimals(ctx)
+	if err != nil {
+		return TokenConfig{}, err
+	}
+	symbol, err := token.Symbol(ctx)
+	if err != nil {
+		return TokenConfig{}, err
+	}
+
+	ec.tokenConfig = &TokenConfig{Address: address, Decimals: decimals, Symbol: symbol}
+	return *ec.tokenConfig, nil
+}
+
+// InvalidateTokenConfig drops the cached token config
+func (ec *EscrowContract) InvalidateTokenConfig() {
+	ec.tokenMu.Lock()
+	defer ec.tokenMu.Unlock()
+	ec.tokenConfig = nil
+}
+
+// tokenAddress returns the escrow's token address from get_token. Contracts
+// without that function keep it in instance storage under DataKey::Token,
+// which is read directly.
+func (ec *EscrowContract) tokenAddress(ctx context.Context) (string, error) {
+	contractAddr, err := EncodeContractAddress(ec.contractAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "get_token", []xdr.ScVal{})
+	if err != nil {
+		return "", fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	sim, err := ec.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	switch {
+	case errors.Is(err, ErrFunctionNotImplemented):
+		return ec.storedTokenAddress(ctx)
+	case err != nil:
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+
+	ret, err := ParseReturnValue(sim)
+	if err != nil {
+		return "", err
+	}
+	address, err := DecodeScValAddress(ret)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token address: %w", err)
+	}
+	return address.String(), nil
+}
+
+// storedTokenAddress reads DataKey::Token from the escrow's instance storage
+func (ec *EscrowContract) storedTokenAddress(ctx context.Context) (string, error) {
+	keys, err := NewLedgerKeyBuilder(ec.contractAddress)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := ec.client.ReadEntries(ctx, []xdr.LedgerKey{keys.Instance()})
+	if err != nil {
+		return "", fmt.Errorf("failed to read contract instance: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].Found || entries[0].Data.ContractData == nil {
+		return "", fmt.Errorf("contract instance not found")
+	}
+
+	instance, ok := entries[0].Data.ContractData.Val.GetInstance()
+	if !ok || instance.Storage == nil {
+		return "", fmt.Errorf("contract instance has no storage")
+	}
+	tokenKey := EnumKey("Token")
+	for _, entry := range *instance.Storage {
+		if entry.Key.Equals(tokenKey) {
+			address, err := DecodeScValAddress(entry.Val)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse token address: %w", err)
+			}
+			return address.String(), nil
+		}
+	}
+	return "", fmt.Errorf("escrow is not initialized: no token in instance storage")
+}
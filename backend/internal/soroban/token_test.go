@@ -0,0 +1,85 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestCheckBalanceDelta(t *testing.T) {
+	if err := checkBalanceDelta(100, 150, 50); err != nil {
+		t.Errorf("expected matching delta to pass, got %v", err)
+	}
+	if err := checkBalanceDelta(100, 100, 50); !errors.Is(err, ErrPayoutNotReflected) {
+		t.Errorf("expected ErrPayoutNotReflected for a no-op payout, got %v", err)
+	}
+	if err := checkBalanceDelta(100, 175, 50); !errors.Is(err, ErrPayoutNotReflected) {
+		t.Errorf("expected ErrPayoutNotReflected for an unexpected delta, got %v", err)
+	}
+}
+
+func TestSinglePayoutVerified_RequiresToken(t *testing.T) {
+	pec, _ := NewProgramEscrowContract(&Client{}, nil, testContractHex)
+	if _, err := pec.SinglePayoutVerified(context.Background(), "GABC", 10); err == nil {
+		t.Error("expected an error without a token address")
+	}
+}
+
+func TestTokenConfig_Formatter(t *testing.T) {
+	cfg := TokenConfig{Address: "CABC", Decimals: 6, Symbol: "USDC"}
+	if got := cfg.Formatter().Format(1_500_000); got != "1.5 USDC" {
+		t.Errorf("expected 1.5 USDC, got %q", got)
+	}
+}
+
+func TestGetTokenConfig_Cached(t *testing.T) {
+	ec, _ := NewEscrowContract(&Client{}, nil, testContractHex)
+	want := TokenConfig{Address: "CABC", Decimals: 7, Symbol: "XLM"}
+	ec.tokenConfig = &want
+
+	// No RPC is configured, so a cache miss would fail
+	got, err := ec.GetTokenConfig(context.Background())
+	if err != nil || got != want {
+		t.Fatalf("expected cached config, got %+v, %v", got, err)
+	}
+
+	ec.InvalidateTokenConfig()
+	if ec.tokenConfig != nil {
+		t.Error("expected InvalidateTokenConfig to drop the cached config")
+	}
+}
+
+func TestStoredTokenAddress(t *testing.T) {
+	b, err := NewLedgerKeyBuilder(testContractHex)
+	if err != nil {
+		t.Fatalf("NewLedgerKeyBuilder failed: %v", err)
+	}
+	token := xdr.MustAddress(keypair.MustRandom().Address())
+	tokenVal := xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{
+		Type:      xdr.ScAddressTypeScAddressTypeAccount,
+		AccountId: &token,
+	}}
+	adminVal, _ := EncodeScValUint64(1)
+	storage := xdr.ScMap{
+		{Key: EnumKey("Admin"), Val: adminVal},
+		{Key: EnumKey("Token"), Val: tokenVal},
+	}
+	srv := newFakeRPC(t)
+	srv.putEntry(b.Instance(), xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+		Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset},
+		Storage:    &storage,
+	}})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+
+	ec, _ := NewEscrowContract(client, nil, testContractHex)
+	got, err := ec.storedTokenAddress(context.Background())
+	if err != nil {
+		t.Fatalf("storedTokenAddress failed: %v", err)
+	}
+	if got != token.Address() {
+		t.Errorf("expected %s, got %s", token.Address(), got)
+	}
+}
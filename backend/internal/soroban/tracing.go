@@ -0,0 +1,73 @@
+package soroban
+
+import (
+	"context"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys used by the client
+const (
+	attrRPCMethod    = attribute.Key("soroban.rpc.method")
+	attrRPCEndpoint  = attribute.Key("soroban.rpc.endpoint")
+	attrOperation    = attribute.Key("soroban.operation")
+	attrContractID   = attribute.Key("soroban.contract_id")
+	attrTxHash       = attribute.Key("soroban.tx_hash")
+	attrNetworkLabel = attribute.Key("soroban.network")
+)
+
+// startSpan starts a client span if a tracer was configured. The returned
+// span is nil when tracing is disabled; pass it to endSpan either way.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c == nil || c.tracer == nil {
+		return ctx, nil
+	}
+	attrs = append(attrs, attrNetworkLabel.String(string(c.network)))
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records the outcome of a span started by startSpan and ends it
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// invokeAttributes describes the first contract invocation among operations
+func invokeAttributes(operations []txnbuild.Operation) []attribute.KeyValue {
+	for _, op := range operations {
+		ihf, ok := op.(*txnbuild.InvokeHostFunction)
+		if !ok {
+			continue
+		}
+		invoke, ok := ihf.HostFunction.GetInvokeContract()
+		if !ok {
+			return []attribute.KeyValue{attrOperation.String(ihf.HostFunction.Type.String())}
+		}
+
+		attrs := []attribute.KeyValue{attrOperation.String(string(invoke.FunctionName))}
+		if invoke.ContractAddress.Type == xdr.ScAddressTypeScAddressTypeContract && invoke.ContractAddress.ContractId != nil {
+			id := *invoke.ContractAddress.ContractId
+			if addr, err := strkey.Encode(strkey.VersionByteContract, id[:]); err == nil {
+				attrs = append(attrs, attrContractID.String(addr))
+			}
+		}
+		return attrs
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package soroban
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer keeps every span it starts so tests can inspect them
+type recordingTracer struct {
+	noop.Tracer
+
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &recordingSpan{name: name, attrs: cfg.Attributes()}
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return ctx, span
+}
+
+type recordingSpan struct {
+	noop.Span
+
+	name   string
+	attrs  []attribute.KeyValue
+	status codes.Code
+	errs   []error
+	ended  bool
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string)           { s.status = code }
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue)        { s.attrs = append(s.attrs, kv...) }
+func (s *recordingSpan) End(_ ...trace.SpanEndOption)                  { s.ended = true }
+func (s *recordingSpan) attr(key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestCall_RecordsSpanPerEndpoint(t *testing.T) {
+	bad := failingServer(t)
+	good := rpcServer(t, `{"sequence":42}`, nil)
+	tracer := &recordingTracer{}
+
+	client, err := NewClient(Config{RPCURL: bad.URL, FallbackRPCURLs: []string{good.URL}, Tracer: tracer})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if _, err := client.GetLatestLedger(context.Background()); err != nil {
+		t.Fatalf("GetLatestLedger failed: %v", err)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected one span per attempted endpoint, got %d", len(tracer.spans))
+	}
+	failed, ok := tracer.spans[0], tracer.spans[1]
+	if failed.status != codes.Error || len(failed.errs) != 1 || !failed.ended {
+		t.Errorf("expected failed span to record its error, got %+v", failed)
+	}
+	if ok.status != codes.Ok || len(ok.errs) != 0 || !ok.ended {
+		t.Errorf("expected successful span to be Ok, got %+v", ok)
+	}
+	if v, _ := ok.attr(attrRPCMethod); v.AsString() != "getLatestLedger" {
+		t.Errorf("expected method attribute, got %q", v.AsString())
+	}
+	if v, _ := ok.attr(attrRPCEndpoint); v.AsString() != good.URL {
+		t.Errorf("expected endpoint attribute %s, got %q", good.URL, v.AsString())
+	}
+}
+
+func TestCall_NoTracerIsNoop(t *testing.T) {
+	srv := rpcServer(t, `{"sequence":42}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	if _, err := client.GetLatestLedger(context.Background()); err != nil {
+		t.Fatalf("expected untraced call to succeed, got %v", err)
+	}
+}
+
+func TestInvokeAttributes(t *testing.T) {
+	var id xdr.Hash
+	id[0] = 1
+	contractID := xdr.ContractId(id)
+	op := &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+			InvokeContract: &xdr.InvokeContractArgs{
+				ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				FunctionName:    "lock_funds",
+			},
+		},
+	}
+
+	span := &recordingSpan{attrs: invokeAttributes([]txnbuild.Operation{op})}
+	if v, _ := span.attr(attrOperation); v.AsString() != "lock_funds" {
+		t.Errorf("expected operation lock_funds, got %q", v.AsString())
+	}
+	if v, ok := span.attr(attrContractID); !ok || v.AsString()[0] != 'C' {
+		t.Errorf("expected contract strkey, got %q", v.AsString())
+	}
+}
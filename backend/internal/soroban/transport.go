@@ -0,0 +1,51 @@
+package soroban
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Transport defaults for the HTTP client NewClient builds when
+// Config.HTTPClient is nil. Each bounds one phase of a request so a stalled
+// connection fails instead of holding a caller until HTTPTimeout; a context
+// deadline still bounds the whole call on top of them.
+const (
+	// DefaultDialTimeout bounds establishing a TCP connection
+	DefaultDialTimeout = 10 * time.Second
+	// DefaultTLSHandshakeTimeout bounds the TLS handshake
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+	// DefaultResponseHeaderTimeout bounds the wait for response headers once
+	// a request is written. Simulating a large transaction can take several
+	// seconds, so it is generous.
+	DefaultResponseHeaderTimeout = 20 * time.Second
+	// DefaultMaxIdleConnsPerHost is how many keep-alive connections are
+	// pooled per RPC or Horizon host
+	DefaultMaxIdleConnsPerHost = 16
+	// DefaultIdleConnTimeout is how long a pooled connection may sit unused
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// newDefaultHTTPClient builds the HTTP client used when none is injected.
+// Proxies are taken from the environment (HTTPS_PROXY and friends) as with
+// http.DefaultTransport.
+func newDefaultHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   DefaultDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+			ResponseHeaderTimeout: DefaultResponseHeaderTimeout,
+			ExpectContinueTimeout: time.Second,
+			MaxIdleConns:          4 * DefaultMaxIdleConnsPerHost,
+			MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+			IdleConnTimeout:       DefaultIdleConnTimeout,
+		},
+	}
+}
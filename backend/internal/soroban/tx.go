@@ -3,127 +3,534 @@ package soroban
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
+
+	"github.com/jagadeesh/grainlify/backend/internal/backoff"
 )
 
 // TransactionBuilder handles building, signing, and submitting Soroban transactions
 type TransactionBuilder struct {
 	client      *Client
-	sourceKP    *keypair.Full
+	signer      Signer
 	retryConfig RetryConfig
+
+	// AutoAuth runs a simulateTransaction preflight before signing so the
+	// Soroban resource footprint and authorization entries are attached to
+	// invoke operations. Enabled by default; disable only when operations
+	// already carry their own auth and transaction data.
+	AutoAuth bool
+
+	// TimeBounds is how long a built transaction stays valid. Once it lapses
+	// the network rejects the transaction, so a held envelope can't be
+	// replayed later. Defaults to DefaultTimeBounds. Windows are rounded up
+	// to whole seconds.
+	TimeBounds time.Duration
+
+	// WasmRequirements are checked by UploadWasm before uploading
+	WasmRequirements WasmRequirements
+
+	// AllowedFunctions, if set, are the only contract functions the builder
+	// will submit calls to; anything else, including other host functions
+	// like WASM uploads, fails with ErrFunctionNotAllowed before submission.
+	// A guardrail for locked-down service accounts. Empty allows everything.
+	AllowedFunctions []string
+
+	// Sequences, if set, coordinates sequence numbers with other builders
+	// sharing the coordinator and source account, so they don't thrash on
+	// tx_bad_seq. See SequenceCoordinator.
+	Sequences *SequenceCoordinator
+
+	// Logger receives debug-level entries for each phase of a transaction
+	// (simulated, built, signed, submitted, confirming, confirmed or
+	// failed) with its durations. Nil uses slog.Default().
+	Logger *slog.Logger
+
+	// account holds the source account loaded by VerifyAccount until the
+	// first transaction consumes it, or by PrewarmSequence for every
+	// transaction after
+	account *sourceAccountCache
+}
+
+// DefaultTimeBounds is the default validity window of built transactions
+const DefaultTimeBounds = 5 * time.Minute
+
+// sourceAccountCache holds a verified source account for one-time reuse.
+// Once warm, each successful submission puts the account back with its
+// advanced sequence, so later transactions skip the lookup.
+type sourceAccountCache struct {
+	mu      sync.Mutex
+	account *txnbuild.SimpleAccount
+	warm    bool
+}
+
+func (c *sourceAccountCache) store(account *txnbuild.SimpleAccount) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.account = account
+}
+
+// take returns the cached account, if any, and clears the cache
+func (c *sourceAccountCache) take() *txnbuild.SimpleAccount {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	account := c.account
+	c.account = nil
+	return account
+}
+
+// prewarm caches account and keeps the cache filled after each submission.
+// It reports false, leaving the cache alone, if it was already warm and
+// holding an account.
+func (c *sourceAccountCache) prewarm(account *txnbuild.SimpleAccount) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.warm && c.account != nil {
+		return false
+	}
+	c.account = account
+	c.warm = true
+	return true
+}
+
+// release puts back an account whose sequence a submitted transaction
+// advanced, if the cache is warm and nothing has refilled it since
+func (c *sourceAccountCache) release(account txnbuild.Account) {
+	if c == nil {
+		return
+	}
+	seq, err := account.GetSequenceNumber()
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.warm && c.account == nil {
+		c.account = &txnbuild.SimpleAccount{AccountID: account.GetAccountID(), Sequence: seq}
+	}
 }
 
-// NewTransactionBuilder creates a new transaction builder
+// invalidate drops a cached account whose sequence is stale
+func (c *sourceAccountCache) invalidate() {
+	c.store(nil)
+}
+
+// NewTransactionBuilder creates a new transaction builder that signs with a
+// LocalSigner for sourceSecret
 func NewTransactionBuilder(client *Client, sourceSecret string, retryConfig RetryConfig) (*TransactionBuilder, error) {
-	sourceKP, err := keypair.ParseFull(sourceSecret)
+	var passphrase string
+	if client != nil {
+		passphrase = client.GetNetworkPassphrase()
+	}
+	signer, err := NewLocalSigner(sourceSecret, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("invalid source secret: %w", err)
+		return nil, err
 	}
+	return NewTransactionBuilderWithSigner(client, signer, retryConfig)
+}
 
+// NewTransactionBuilderWithSigner creates a transaction builder whose source
+// account and signatures come from signer, so the secret key never needs to
+// enter the process. It fails if retryConfig does not pass Validate.
+func NewTransactionBuilderWithSigner(client *Client, signer Signer, retryConfig RetryConfig) (*TransactionBuilder, error) {
+	if err := retryConfig.Validate(); err != nil {
+		return nil, err
+	}
 	return &TransactionBuilder{
 		client:      client,
-		sourceKP:    sourceKP,
+		signer:      signer,
 		retryConfig: retryConfig,
+		AutoAuth:    true,
+		TimeBounds:  DefaultTimeBounds,
+		account:     &sourceAccountCache{},
+	}, nil
+}
+
+// VerifyAccount checks that the source account exists on the network, so a
+// missing or unfunded account is reported clearly instead of failing the
+// first transaction. The loaded account is cached for that transaction's
+// sequence number.
+func (tb *TransactionBuilder) VerifyAccount(ctx context.Context) error {
+	account, err := tb.fetchSourceAccount(ctx)
+	if err != nil {
+		return err
+	}
+	tb.account.store(account)
+
+	slog.Info("source account verified",
+		"account", account.AccountID,
+		"sequence", account.Sequence,
+	)
+	return nil
+}
+
+// PrewarmSequence loads and caches the source account's sequence ahead of a
+// burst of submissions, e.g. a scheduled payout run. From then on each
+// successful submission caches the next sequence, so the burst runs without
+// per-transaction lookups. A tx_bad_seq rejection drops the cached value and
+// the next transaction reloads it. Calling it again while the cache is warm
+// does nothing.
+func (tb *TransactionBuilder) PrewarmSequence(ctx context.Context) error {
+	if tb.account == nil {
+		return fmt.Errorf("builder has no sequence cache to prewarm")
+	}
+
+	account, err := tb.fetchSourceAccount(ctx)
+	if err != nil {
+		return err
+	}
+	if tb.account.prewarm(account) {
+		slog.Info("source sequence prewarmed",
+			"account", account.AccountID,
+			"sequence", account.Sequence,
+		)
+	}
+	return nil
+}
+
+// fetchSourceAccount reads the source account's current sequence from the RPC
+func (tb *TransactionBuilder) fetchSourceAccount(ctx context.Context) (*txnbuild.SimpleAccount, error) {
+	address := tb.signer.PublicKey()
+	accountID, err := xdr.AddressToAccountId(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source account: %w", err)
+	}
+
+	key := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	}
+	entries, err := tb.client.ReadEntries(ctx, []xdr.LedgerKey{key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify source account: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].Found || entries[0].Data.Account == nil {
+		return nil, fmt.Errorf("%w: source account %s not found — fund it first", ErrSourceAccountNotFound, address)
+	}
+
+	return &txnbuild.SimpleAccount{
+		AccountID: address,
+		Sequence:  int64(entries[0].Data.Account.SeqNum),
 	}, nil
 }
 
+// withSigner returns a copy of the builder that uses the given key as the
+// transaction source and signer. A nil key returns the builder unchanged.
+func (tb *TransactionBuilder) withSigner(kp *keypair.Full) *TransactionBuilder {
+	if kp == nil {
+		return tb
+	}
+	return tb.WithSigner(newLocalSigner(kp, tb.client.GetNetworkPassphrase()))
+}
+
+// WithSigner returns a copy of the builder that uses signer as the transaction
+// source and signer, e.g. a remote signer for privileged operations
+func (tb *TransactionBuilder) WithSigner(signer Signer) *TransactionBuilder {
+	derived := *tb
+	derived.signer = signer
+	derived.account = nil
+	return &derived
+}
+
 // BuildAndSubmit builds a transaction, signs it, and submits it to the network
 func (tb *TransactionBuilder) BuildAndSubmit(ctx context.Context, operations []txnbuild.Operation) (*TransactionResult, error) {
-	// Get account details
-	accountRequest := horizonclient.AccountRequest{AccountID: tb.sourceKP.Address()}
-	accountDetail, err := tb.client.GetHorizonClient().AccountDetail(accountRequest)
+	ctx, span := tb.client.startSpan(ctx, "soroban.BuildAndSubmit", invokeAttributes(operations)...)
+	result, err := tb.buildAndSubmit(ctx, operations)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get account details: %w", err)
+		if summary := invokeSummary(operations); summary != "" {
+			err = fmt.Errorf("%s: %w", summary, err)
+		}
+	}
+	if span != nil && result != nil {
+		span.SetAttributes(attrTxHash.String(result.Hash))
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+// BuildAndSubmitAs is BuildAndSubmit with source as the transaction source
+// and signer, for operations the end user rather than the service account
+// must authorize, e.g. a depositor-signed lock. The source's sequence number
+// is looked up separately and never touches the builder's cached account.
+func (tb *TransactionBuilder) BuildAndSubmitAs(ctx context.Context, source *keypair.Full, operations []txnbuild.Operation) (*TransactionResult, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source key is required")
+	}
+	return tb.withSigner(source).BuildAndSubmit(ctx, operations)
+}
+
+func (tb *TransactionBuilder) buildAndSubmit(ctx context.Context, operations []txnbuild.Operation) (*TransactionResult, error) {
+	if err := checkFunctionsAllowed(operations, tb.AllowedFunctions); err != nil {
+		return nil, err
+	}
+
+	// Builders sharing the source take turns from here until the
+	// submission settles
+	shared, err := tb.Sequences.acquire(ctx, tb.signer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	defer shared.release()
+
+	// Get account details
+	var account txnbuild.Account
+	if cached := shared.take(); cached != nil {
+		account = cached
+	} else if account, err = tb.loadSourceAccount(); err != nil {
+		return nil, err
+	}
+
+	lc := tb.lifecycle(operations, "")
+
+	// Attach resource footprint and signed auth entries before signing
+	if tb.AutoAuth {
+		if err := tb.preflight(ctx, account, operations); err != nil {
+			lc.fail(ctx, "simulate", err)
+			return nil, err
+		}
+		lc.step(ctx, "transaction simulated")
+	}
+
+	validity := tb.TimeBounds
+	if validity <= 0 {
+		validity = DefaultTimeBounds
 	}
 
 	// Build transaction
 	tx, err := txnbuild.NewTransaction(
 		txnbuild.TransactionParams{
-			SourceAccount:        &accountDetail,
+			SourceAccount:        account,
 			IncrementSequenceNum: true,
 			BaseFee:              txnbuild.MinBaseFee,
 			Operations:           operations,
+			Preconditions: txnbuild.Preconditions{
+				TimeBounds: txnbuild.NewTimeout(timeoutSeconds(validity)),
+			},
 		},
 	)
 	if err != nil {
+		lc.fail(ctx, "build", err)
 		return nil, fmt.Errorf("failed to build transaction: %w", err)
 	}
+	lc.step(ctx, "transaction built", "fee", tx.MaxFee())
 
 	// Sign transaction
-	tx, err = tx.Sign(tb.client.GetNetworkPassphrase(), tb.sourceKP)
+	tx, err = tb.signer.Sign(tx)
 	if err != nil {
+		lc.fail(ctx, "sign", err)
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
+	if hash, err := tx.HashHex(tb.client.GetNetworkPassphrase()); err == nil {
+		lc.hash = hash
+	}
+	lc.step(ctx, "transaction signed")
 
 	// Submit with retry
-	return tb.submitWithRetry(ctx, tx)
+	result, err := tb.submitWithRetry(ctx, tx)
+	if err != nil {
+		lc.fail(ctx, "submit", err)
+		if isBadSequence(err) {
+			tb.account.invalidate()
+		}
+		return nil, err
+	}
+	lc.step(ctx, "transaction submitted")
+	// Building the transaction advanced the account's sequence
+	tb.account.release(account)
+	shared.advance(account)
+	return result, nil
+}
+
+// isBadSequence reports whether err is a tx_bad_seq rejection
+func isBadSequence(err error) bool {
+	var submitErr *SubmitError
+	return errors.As(err, &submitErr) && submitErr.Code == "tx_bad_seq"
+}
+
+// checkFunctionsAllowed rejects host function operations that don't call one
+// of a non-empty allowlist's functions
+func checkFunctionsAllowed(operations []txnbuild.Operation, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, op := range operations {
+		ihf, ok := op.(*txnbuild.InvokeHostFunction)
+		if !ok {
+			continue
+		}
+		invoke, ok := ihf.HostFunction.GetInvokeContract()
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrFunctionNotAllowed, ihf.HostFunction.Type)
+		}
+		if !slices.Contains(allowed, string(invoke.FunctionName)) {
+			return fmt.Errorf("%w: %s", ErrFunctionNotAllowed, invoke.FunctionName)
+		}
+	}
+	return nil
+}
+
+// loadSourceAccount fetches the source account and its current sequence number
+func (tb *TransactionBuilder) loadSourceAccount() (txnbuild.Account, error) {
+	if account := tb.account.take(); account != nil {
+		return account, nil
+	}
+
+	accountRequest := horizonclient.AccountRequest{AccountID: tb.signer.PublicKey()}
+	accountDetail, err := tb.client.GetHorizonClient().AccountDetail(accountRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account details: %w", err)
+	}
+	return &accountDetail, nil
 }
 
-// submitWithRetry submits a transaction with retry logic
+// preflight simulates the operations and attaches the simulated Soroban
+// transaction data and signed authorization entries to the invoke operation.
+// Operations without a host function invocation are left untouched.
+func (tb *TransactionBuilder) preflight(ctx context.Context, account txnbuild.Account, operations []txnbuild.Operation) error {
+	var invokeOp *txnbuild.InvokeHostFunction
+	for _, op := range operations {
+		if ihf, ok := op.(*txnbuild.InvokeHostFunction); ok {
+			invokeOp = ihf
+			break
+		}
+	}
+	if invokeOp == nil {
+		return nil
+	}
+
+	sim, err := tb.simulate(ctx, account, operations)
+	if err != nil {
+		return fmt.Errorf("auth preflight failed: %w", err)
+	}
+	if len(sim.Results) == 0 {
+		return fmt.Errorf("auth preflight failed: no results returned from simulation")
+	}
+
+	auth, err := signAuthEntries(sim.Results[0].Auth, authKey(tb.signer), tb.client.GetNetworkPassphrase(), sim.LatestLedger)
+	if err != nil {
+		return fmt.Errorf("auth preflight failed: %w", err)
+	}
+	invokeOp.Auth = auth
+
+	if sim.TransactionData != nil {
+		invokeOp.Ext = xdr.TransactionExt{V: 1, SorobanData: sim.TransactionData}
+	}
+
+	slog.Debug("auth preflight completed",
+		"auth_entries", len(auth),
+		"min_resource_fee", sim.MinResourceFee,
+		"latest_ledger", sim.LatestLedger,
+	)
+
+	return nil
+}
+
+// submitWithRetry submits a transaction through the RPC with retry logic.
+// SendTransaction only fails over when a submission can't have reached an
+// endpoint, so a retry never sends a transaction the network may already
+// have.
 func (tb *TransactionBuilder) submitWithRetry(ctx context.Context, tx *txnbuild.Transaction) (*TransactionResult, error) {
 	var lastErr error
-	delay := tb.retryConfig.InitialDelay
+	var retryAfter time.Duration
+	maxTime := tx.Timebounds().MaxTime
+
+	envelope, err := tx.Base64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
 
 	for attempt := 0; attempt <= tb.retryConfig.MaxRetries; attempt++ {
+		// Resubmitting after the time bound would only be rejected again
+		if txExpired(maxTime, time.Now()) {
+			return nil, fmt.Errorf("%w: valid until %s", ErrTxExpired, time.Unix(maxTime, 0).UTC())
+		}
+		if err := spendAttempt(ctx, "submission"); err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+			return nil, err
+		}
+
 		if attempt > 0 {
+			wait := tb.retryConfig.backoff().Delay(attempt)
+			// A rate-limited response says how long to back off; honor it
+			// when it's longer than our own backoff
+			if retryAfter > wait {
+				wait = retryAfter
+			}
 			slog.Info("retrying transaction submission",
 				"attempt", attempt,
 				"max_retries", tb.retryConfig.MaxRetries,
-				"delay", delay,
+				"delay", wait,
+				"retry_after", retryAfter,
 			)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
+			if err := budgetAllowsWait(ctx, wait, "submission"); err != nil {
+				return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
 			}
-			delay = time.Duration(float64(delay) * tb.retryConfig.BackoffMultiplier)
-			if delay > tb.retryConfig.MaxDelay {
-				delay = tb.retryConfig.MaxDelay
+			if err := backoff.Wait(ctx, wait); err != nil {
+				return nil, err
 			}
 		}
 
 		// Submit transaction
-		resp, err := tb.client.GetHorizonClient().SubmitTransaction(tx)
+		hash, err := tb.client.SendTransaction(ctx, envelope)
+		if errors.Is(err, ErrSubmissionUnknown) {
+			// The transaction may be in flight; resubmitting could send it
+			// twice, so leave it to confirmation to find out
+			slog.Warn("transaction submission outcome unknown",
+				"tx_hash", hash,
+				"error", err,
+			)
+			err = nil
+		}
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			lastErr = err
-			if herr, ok := err.(*horizonclient.Error); ok {
-				slog.Warn("transaction submission failed",
-					"attempt", attempt+1,
-					"error", herr.Problem.Detail,
-					"result_codes", herr.Problem.Extras,
-				)
+			retryAfter, _ = RetryAfter(err)
+			slog.Warn("transaction submission failed",
+				"attempt", attempt+1,
+				"error", err,
+			)
+			var submitErr *SubmitError
+			if errors.As(err, &submitErr) {
+				if submitErr.Code == "tx_too_late" {
+					return nil, fmt.Errorf("%w: %w", ErrTxExpired, err)
+				}
 				// Don't retry on certain errors
-				if isNonRetryableError(herr) {
+				if isNonRetryableError(submitErr.Code) {
 					return nil, fmt.Errorf("non-retryable error: %w", err)
 				}
-			} else {
-				slog.Warn("transaction submission failed",
-					"attempt", attempt+1,
-					"error", err,
-				)
 			}
 			continue
 		}
 
 		// Success
-		ledger := uint32(resp.Ledger)
 		result := &TransactionResult{
-			Hash:      resp.Hash,
-			Ledger:    ledger,
+			Hash:      hash,
 			Status:    "pending",
 			Submitted: time.Now(),
 		}
 
 		slog.Info("transaction submitted successfully",
-			"tx_hash", resp.Hash,
-			"ledger", resp.Ledger,
+			"tx_hash", hash,
 		)
 
 		return result, nil
@@ -132,56 +539,121 @@ func (tb *TransactionBuilder) submitWithRetry(ctx context.Context, tx *txnbuild.
 	return nil, fmt.Errorf("transaction submission failed after %d attempts: %w", tb.retryConfig.MaxRetries+1, lastErr)
 }
 
-// isNonRetryableError checks if an error should not be retried
-func isNonRetryableError(herr *horizonclient.Error) bool {
-	// Check result codes
-	if resultCodes, ok := herr.Problem.Extras["result_codes"].(map[string]interface{}); ok {
-		if transactionCode, ok := resultCodes["transaction"].(string); ok {
-			// These errors should not be retried
-			nonRetryableCodes := []string{
-				"tx_bad_auth",
-				"tx_bad_seq",
-				"tx_insufficient_balance",
-				"tx_no_source_account",
-			}
-			for _, code := range nonRetryableCodes {
-				if transactionCode == code {
-					return true
-				}
-			}
+// timeoutSeconds converts a validity window to whole seconds for
+// txnbuild.NewTimeout, rounding up: truncating a sub-second window would
+// give NewTimeout(0), which means no upper time bound at all
+func timeoutSeconds(validity time.Duration) int64 {
+	secs := int64((validity + time.Second - 1) / time.Second)
+	if secs < 1 {
+		return 1
+	}
+	return secs
+}
+
+// txExpired reports whether a transaction with the given max time (0 = none)
+// is no longer valid at now
+func txExpired(maxTime int64, now time.Time) bool {
+	return maxTime != 0 && now.Unix() > maxTime
+}
+
+// isNonRetryableError checks if a rejection's result code should not be
+// retried
+func isNonRetryableError(transactionCode string) bool {
+	// These errors should not be retried
+	nonRetryableCodes := []string{
+		"tx_bad_auth",
+		"tx_bad_seq",
+		"tx_insufficient_balance",
+		"tx_no_source_account",
+	}
+	for _, code := range nonRetryableCodes {
+		if transactionCode == code {
+			return true
 		}
 	}
 	return false
 }
 
-// WaitForConfirmation polls for transaction confirmation
+// WaitForConfirmation polls for transaction confirmation every
+// ConfirmPollInterval, each poll taking one of the client's
+// MaxConfirmationPollers slots. It returns a *ConfirmationTimeoutError once
+// ConfirmMaxAttempts checks or timeout have elapsed without confirmation.
+// A zero timeout uses RetryConfig.ConfirmTimeout, or DefaultConfirmTimeout
+// if that is unset too.
 func (tb *TransactionBuilder) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) (*TransactionResult, error) {
+	if timeout <= 0 {
+		timeout = tb.retryConfig.ConfirmTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+
+	lc := tb.lifecycle(nil, txHash)
+	lc.step(ctx, "transaction confirming", "timeout", timeout)
+	result, err := tb.waitForConfirmation(ctx, txHash, timeout)
+	if err != nil {
+		lc.fail(ctx, "confirm", err)
+		return nil, err
+	}
+	lc.step(ctx, "transaction confirmed",
+		"ledger", result.Ledger,
+		"fee_charged", result.FeeCharged,
+		"resource_fee", result.ResourceFee,
+	)
+	return result, nil
+}
+
+func (tb *TransactionBuilder) waitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) (*TransactionResult, error) {
 	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(2 * time.Second)
+	interval, maxAttempts := tb.retryConfig.confirmPolling()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for {
+	for attempt := 1; ; attempt++ {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-ticker.C:
 			if time.Now().After(deadline) {
-				return nil, fmt.Errorf("timeout waiting for transaction confirmation: %s", txHash)
+				return nil, &ConfirmationTimeoutError{TxHash: txHash, Attempts: attempt - 1}
+			}
+			if err := spendAttempt(ctx, "confirmation"); err != nil {
+				return nil, fmt.Errorf("%w: %s unconfirmed", err, txHash)
 			}
 
-			tx, err := tb.client.GetHorizonClient().TransactionDetail(txHash)
-			if err != nil {
-				// Transaction not found yet, continue polling
+			// Wait for a poll slot shared with the client's other pending
+			// confirmations
+			release, ok := tb.client.acquireConfirmSlot(ctx, deadline)
+			if !ok {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, &ConfirmationTimeoutError{TxHash: txHash, Attempts: attempt - 1}
+			}
+			tx, err := tb.client.getTransaction(ctx, txHash)
+			release()
+			if err != nil || tx.Status == "NOT_FOUND" {
+				// Transaction not found yet. Give up now if that was the
+				// last poll rather than a tick later.
+				if attempt >= maxAttempts || time.Now().After(deadline) {
+					return nil, &ConfirmationTimeoutError{TxHash: txHash, Attempts: attempt}
+				}
 				continue
 			}
+			feeCharged, code, _ := transactionOutcome(tx.ResultXDR)
+			if tx.Status != "SUCCESS" {
+				return nil, fmt.Errorf("%w: %s in ledger %d: %s", ErrTransactionFailed, txHash, tx.Ledger, code)
+			}
 
 			// Transaction found
 			result := &TransactionResult{
-				Hash:      txHash,
-				Ledger:    uint32(tx.Ledger),
-				Status:    "success",
-				Submitted: time.Now(), // Approximate
-				Confirmed: time.Now(),
+				Hash:        txHash,
+				Ledger:      tx.Ledger,
+				Status:      "success",
+				Submitted:   time.Now(), // Approximate
+				Confirmed:   time.Now(),
+				FeeCharged:  feeCharged,
+				ResourceFee: envelopeResourceFee(tx.EnvelopeXDR),
 			}
 
 			slog.Info("transaction confirmed",
@@ -194,10 +666,35 @@ func (tb *TransactionBuilder) WaitForConfirmation(ctx context.Context, txHash st
 	}
 }
 
+// ValidateContractAddress checks that contractID is a contract address
+// EncodeContractAddress accepts: a C-prefixed strkey, 64 hex characters or
+// 32 base64-encoded bytes
+func ValidateContractAddress(contractID string) error {
+	if contractID == "" {
+		return fmt.Errorf("contract address is required")
+	}
+	_, err := EncodeContractAddress(contractID)
+	return err
+}
+
 // EncodeContractAddress encodes a contract address to XDR
 func EncodeContractAddress(contractID string) (xdr.ScAddress, error) {
 	// Contract ID is typically a hex string (64 chars) or base64
 	var hash xdr.Hash
+
+	// A C-prefixed strkey is 56 characters, longer than either raw encoding
+	if len(contractID) == 56 && contractID[0] == 'C' {
+		raw, err := strkey.Decode(strkey.VersionByteContract, contractID)
+		if err != nil {
+			return xdr.ScAddress{}, fmt.Errorf("invalid contract strkey: %w", err)
+		}
+		copy(hash[:], raw)
+		contractId := xdr.ContractId(hash)
+		return xdr.ScAddress{
+			Type:       xdr.ScAddressTypeScAddressTypeContract,
+			ContractId: &contractId,
+		}, nil
+	}
 	
 	// Try hex first (64 hex chars = 32 bytes)
 	if len(contractID) == 64 {
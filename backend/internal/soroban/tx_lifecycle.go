@@ -0,0 +1,105 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// txLifecycle logs the phases of one transaction at debug level, each with
+// the time since the previous phase and since the first. Nothing is
+// formatted unless the logger has debug enabled, so production builders
+// don't pay for it.
+type txLifecycle struct {
+	logger    *slog.Logger
+	operation string
+	hash      string
+	start     time.Time
+	last      time.Time
+}
+
+// lifecycle starts logging a transaction of operations, or of the already
+// submitted txHash when operations is nil
+func (tb *TransactionBuilder) lifecycle(operations []txnbuild.Operation, txHash string) *txLifecycle {
+	now := time.Now()
+	return &txLifecycle{
+		logger:    tb.log(),
+		operation: operationName(operations),
+		hash:      txHash,
+		start:     now,
+		last:      now,
+	}
+}
+
+// log returns the builder's logger
+func (tb *TransactionBuilder) log() *slog.Logger {
+	if tb.Logger != nil {
+		return tb.Logger
+	}
+	return slog.Default()
+}
+
+// step logs that the transaction reached a phase
+func (lc *txLifecycle) step(ctx context.Context, msg string, args ...any) {
+	if !lc.logger.Enabled(ctx, slog.LevelDebug) {
+		lc.last = time.Now()
+		return
+	}
+	now := time.Now()
+	attrs := make([]any, 0, len(args)+8)
+	if lc.operation != "" {
+		attrs = append(attrs, "operation", lc.operation)
+	}
+	if lc.hash != "" {
+		attrs = append(attrs, "tx_hash", lc.hash)
+	}
+	attrs = append(attrs, "phase_duration", now.Sub(lc.last), "total_duration", now.Sub(lc.start))
+	attrs = append(attrs, args...)
+	lc.last = now
+	lc.logger.DebugContext(ctx, msg, attrs...)
+}
+
+// fail logs that the transaction failed in phase
+func (lc *txLifecycle) fail(ctx context.Context, phase string, err error) {
+	lc.step(ctx, "transaction failed", "phase", phase, "error", err)
+}
+
+// operationName names the transaction's first operation for logs: the
+// contract function it calls, the host function type, or the operation type
+func operationName(operations []txnbuild.Operation) string {
+	if len(operations) == 0 {
+		return ""
+	}
+	ihf, ok := operations[0].(*txnbuild.InvokeHostFunction)
+	if !ok {
+		return fmt.Sprintf("%T", operations[0])
+	}
+	if invoke, ok := ihf.HostFunction.GetInvokeContract(); ok {
+		return string(invoke.FunctionName)
+	}
+	return ihf.HostFunction.Type.String()
+}
+
+// envelopeResourceFee returns the Soroban resource fee declared in a
+// base64 transaction envelope, or 0 for classic transactions
+func envelopeResourceFee(envelopeXDR string) int64 {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXDR, &env); err != nil {
+		return 0
+	}
+	var ext xdr.TransactionExt
+	switch {
+	case env.V1 != nil:
+		ext = env.V1.Tx.Ext
+	case env.FeeBump != nil && env.FeeBump.Tx.InnerTx.V1 != nil:
+		ext = env.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	}
+	if data, ok := ext.GetSorobanData(); ok {
+		return int64(data.ResourceFee)
+	}
+	return 0
+}
@@ -0,0 +1,530 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestRetryConfigBackoff_CapsAtMaxDelay(t *testing.T) {
+	rc := RetryConfig{MaxDelay: 5 * time.Second, DisableJitter: true}
+
+	if d := rc.backoff().Apply(2 * time.Second); d != 2*time.Second {
+		t.Errorf("expected 2s, got %v", d)
+	}
+	if d := rc.backoff().Apply(time.Minute); d != 5*time.Second {
+		t.Errorf("expected delay capped at 5s, got %v", d)
+	}
+}
+
+func TestRetryConfigBackoff_InjectedSource(t *testing.T) {
+	var seen time.Duration
+	rc := RetryConfig{
+		MaxDelay: 10 * time.Second,
+		JitterFunc: func(d time.Duration) time.Duration {
+			seen = d
+			return d / 4
+		},
+	}
+
+	if d := rc.backoff().Apply(time.Minute); d != 2500*time.Millisecond {
+		t.Errorf("expected 2.5s, got %v", d)
+	}
+	if seen != 10*time.Second {
+		t.Errorf("expected jitter to be applied to the capped backoff, got %v", seen)
+	}
+}
+
+func TestRetryConfigBackoff_ClampsInjectedSource(t *testing.T) {
+	rc := RetryConfig{
+		MaxDelay:   time.Second,
+		JitterFunc: func(d time.Duration) time.Duration { return 2 * d },
+	}
+	if d := rc.backoff().Apply(time.Second); d != time.Second {
+		t.Errorf("expected out-of-range jitter clamped to 1s, got %v", d)
+	}
+
+	rc.JitterFunc = func(d time.Duration) time.Duration { return -d }
+	if d := rc.backoff().Apply(time.Second); d != 0 {
+		t.Errorf("expected negative jitter clamped to 0, got %v", d)
+	}
+}
+
+func TestRetryConfigBackoff_DefaultSourceInRange(t *testing.T) {
+	rc := DefaultRetryConfig()
+	for i := 0; i < 100; i++ {
+		d := rc.backoff().Apply(rc.InitialDelay)
+		if d < 0 || d > rc.InitialDelay {
+			t.Fatalf("jittered delay %v outside [0, %v]", d, rc.InitialDelay)
+		}
+	}
+}
+
+func TestVerifyAccount_NotFound(t *testing.T) {
+	srv := rpcServer(t, `{"entries":[],"latestLedger":100}`, nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	kp := keypair.MustRandom()
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+
+	err := tb.VerifyAccount(context.Background())
+	if !errors.Is(err, ErrSourceAccountNotFound) {
+		t.Fatalf("expected ErrSourceAccountNotFound, got %v", err)
+	}
+}
+
+func TestVerifyAccount_CachesSequence(t *testing.T) {
+	kp := keypair.MustRandom()
+	accountID, _ := xdr.AddressToAccountId(kp.Address())
+	key, _ := xdr.MarshalBase64(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	})
+	data, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: accountID, Balance: 100, SeqNum: 77},
+	})
+	srv := rpcServer(t, fmt.Sprintf(`{"entries":[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":5}],"latestLedger":100}`, key, data), nil)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	if err := tb.VerifyAccount(context.Background()); err != nil {
+		t.Fatalf("VerifyAccount failed: %v", err)
+	}
+
+	// The first load is served from the cache without a Horizon lookup.
+	account, err := tb.loadSourceAccount()
+	if err != nil {
+		t.Fatalf("loadSourceAccount failed: %v", err)
+	}
+	if seq, _ := account.GetSequenceNumber(); seq != 77 {
+		t.Errorf("expected cached sequence 77, got %d", seq)
+	}
+	if tb.account.take() != nil {
+		t.Error("expected the cache to be consumed")
+	}
+}
+
+func TestTxExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	if txExpired(0, now) {
+		t.Error("expected no expiry without a max time")
+	}
+	if txExpired(1000, now) {
+		t.Error("expected transaction to be valid at its max time")
+	}
+	if !txExpired(999, now) {
+		t.Error("expected transaction past its max time to be expired")
+	}
+}
+
+func TestTimeoutSeconds(t *testing.T) {
+	cases := map[time.Duration]int64{
+		time.Nanosecond:         1,
+		500 * time.Millisecond:  1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+		DefaultTimeBounds:       300,
+	}
+	for validity, want := range cases {
+		if got := timeoutSeconds(validity); got != want {
+			t.Errorf("timeoutSeconds(%v) = %d, want %d", validity, got, want)
+		}
+	}
+	// A sub-second window must still produce an upper time bound
+	if bounds := txnbuild.NewTimeout(timeoutSeconds(500 * time.Millisecond)); bounds.MaxTime == 0 {
+		t.Error("expected a sub-second validity to keep an upper time bound")
+	}
+}
+
+func TestSubmitWithRetry_ExpiredTransaction(t *testing.T) {
+	kp := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		BaseFee:              txnbuild.MinBaseFee,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimebounds(0, time.Now().Add(-time.Minute).Unix())},
+	})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	// No client is needed: an expired transaction is never submitted.
+	tb := &TransactionBuilder{retryConfig: DefaultRetryConfig()}
+	if _, err := tb.submitWithRetry(context.Background(), tx); !errors.Is(err, ErrTxExpired) {
+		t.Errorf("expected ErrTxExpired, got %v", err)
+	}
+}
+
+func TestRetryConfig_ConfirmPollingDefaults(t *testing.T) {
+	interval, attempts := RetryConfig{}.confirmPolling()
+	if interval != DefaultConfirmPollInterval || attempts != DefaultConfirmMaxAttempts {
+		t.Errorf("expected defaults, got %v and %d", interval, attempts)
+	}
+}
+
+func TestRetryConfig_RejectsNegativeConfirmPolling(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://localhost", Network: NetworkTestnet})
+	secret := keypair.MustRandom().Seed()
+
+	for _, rc := range []RetryConfig{{ConfirmPollInterval: -time.Second}, {ConfirmMaxAttempts: -1}} {
+		if _, err := NewTransactionBuilder(client, secret, rc); err == nil {
+			t.Errorf("expected %+v to be rejected", rc)
+		}
+	}
+	if _, err := NewTransactionBuilder(client, secret, RetryConfig{}); err != nil {
+		t.Errorf("expected an unset config to use the defaults, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_MaxAttempts(t *testing.T) {
+	srv := newFakeRPC(t)
+	srv.answer("getTransaction", pendingTx)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = 100 * time.Millisecond
+	rc.ConfirmMaxAttempts = 3
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	start := time.Now()
+	_, err := tb.WaitForConfirmation(context.Background(), "abc123", time.Minute)
+	if !errors.Is(err, ErrConfirmationTimeout) {
+		t.Fatalf("expected ErrConfirmationTimeout, got %v", err)
+	}
+	// Three ticks, not a fourth just to notice the limit
+	if elapsed := time.Since(start); elapsed >= 350*time.Millisecond {
+		t.Errorf("expected to give up right after the last poll, waited %v", elapsed)
+	}
+	var timeoutErr *ConfirmationTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.TxHash != "abc123" || timeoutErr.Attempts != 3 {
+		t.Errorf("expected the error to carry the hash and attempts, got %+v", timeoutErr)
+	}
+	if n := srv.callCount("getTransaction"); n != 3 {
+		t.Errorf("expected 3 lookups, got %d", n)
+	}
+}
+
+func TestBuildAndSubmitAs_UsesOwnSequence(t *testing.T) {
+	var requested atomic.Value
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(horizon.Close)
+
+	client, _ := NewClient(Config{RPCURL: "http://localhost"})
+	client.horizonClient.HorizonURL = horizon.URL
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	tb.account.store(&txnbuild.SimpleAccount{AccountID: tb.signer.PublicKey(), Sequence: 7})
+
+	user := keypair.MustRandom()
+	if _, err := tb.BuildAndSubmitAs(context.Background(), user, nil); err == nil {
+		t.Fatal("expected the missing source account to fail the submission")
+	}
+	if got, _ := requested.Load().(string); got != "/accounts/"+user.Address() {
+		t.Errorf("expected the override source to be looked up, got %q", got)
+	}
+	if account := tb.account.take(); account == nil || account.Sequence != 7 {
+		t.Error("expected the default source's cached account to be untouched")
+	}
+
+	if _, err := tb.BuildAndSubmitAs(context.Background(), nil, nil); err == nil {
+		t.Error("expected an error without a source key")
+	}
+}
+
+func TestPrewarmSequence_ReusedAcrossSubmissions(t *testing.T) {
+	kp := keypair.MustRandom()
+	accountID, _ := xdr.AddressToAccountId(kp.Address())
+	key, _ := xdr.MarshalBase64(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	})
+	data, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: accountID, Balance: 100, SeqNum: 77},
+	})
+	srv := newFakeRPC(t)
+	srv.answer("getLedgerEntries", fmt.Sprintf(`{"entries":[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":5}],"latestLedger":100}`, key, data))
+
+	var badSeq atomic.Bool
+	badSeqResult := rejectedTx(t, xdr.TransactionResultCodeTxBadSeq)
+	srv.answerFunc("sendTransaction", func(json.RawMessage) string {
+		if badSeq.Load() {
+			return badSeqResult
+		}
+		return sentTx
+	})
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), RetryConfig{})
+	tb.AutoAuth = false
+
+	ctx := context.Background()
+	if err := tb.PrewarmSequence(ctx); err != nil {
+		t.Fatalf("PrewarmSequence failed: %v", err)
+	}
+	if err := tb.PrewarmSequence(ctx); err != nil {
+		t.Fatalf("second PrewarmSequence failed: %v", err)
+	}
+
+	ops := []txnbuild.Operation{&txnbuild.BumpSequence{}}
+	for i := 0; i < 2; i++ {
+		if _, err := tb.BuildAndSubmit(ctx, ops); err != nil {
+			t.Fatalf("BuildAndSubmit failed: %v", err)
+		}
+	}
+	account := tb.account.take()
+	if account == nil || account.Sequence != 79 {
+		t.Fatalf("expected the cache to hold sequence 79 after two submissions, got %+v", account)
+	}
+	tb.account.store(account)
+
+	badSeq.Store(true)
+	if _, err := tb.BuildAndSubmit(ctx, ops); err == nil {
+		t.Fatal("expected tx_bad_seq to fail the submission")
+	}
+	if tb.account.take() != nil {
+		t.Error("expected tx_bad_seq to drop the cached sequence")
+	}
+	if n := srv.callCount("getLedgerEntries"); n != 2 {
+		t.Errorf("expected one account read per PrewarmSequence call, got %d", n)
+	}
+}
+
+func TestBuildAndSubmit_AllowedFunctions(t *testing.T) {
+	contractAddr, _ := EncodeContractAddress(testContractHex)
+	release, _ := BuildInvokeHostFunctionOp(contractAddr, "release_funds", nil)
+	upgrade, _ := BuildInvokeHostFunctionOp(contractAddr, "upgrade", nil)
+
+	client, _ := NewClient(Config{RPCURL: "http://127.0.0.1:0"})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	tb.AllowedFunctions = []string{"release_funds", "refund"}
+
+	if _, err := tb.BuildAndSubmit(context.Background(), []txnbuild.Operation{upgrade}); !errors.Is(err, ErrFunctionNotAllowed) {
+		t.Errorf("expected ErrFunctionNotAllowed for upgrade, got %v", err)
+	}
+	if err := checkFunctionsAllowed([]txnbuild.Operation{release}, tb.AllowedFunctions); err != nil {
+		t.Errorf("expected release_funds to be allowed, got %v", err)
+	}
+	if err := checkFunctionsAllowed([]txnbuild.Operation{upgrade}, nil); err != nil {
+		t.Errorf("expected an empty allowlist to allow everything, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_ConfirmTimeout(t *testing.T) {
+	horizon := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(horizon.Close)
+
+	client, _ := NewClient(Config{RPCURL: "http://localhost"})
+	client.horizonClient.HorizonURL = horizon.URL
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = 5 * time.Millisecond
+	rc.ConfirmTimeout = 20 * time.Millisecond
+	tb := &TransactionBuilder{client: client, retryConfig: rc}
+
+	start := time.Now()
+	_, err := tb.WaitForConfirmation(context.Background(), "abc123", 0)
+	if !errors.Is(err, ErrConfirmationTimeout) {
+		t.Fatalf("expected ErrConfirmationTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected ConfirmTimeout to apply without a timeout argument, waited %v", elapsed)
+	}
+
+	// An explicit timeout takes precedence over ConfirmTimeout
+	tb.retryConfig.ConfirmTimeout = time.Minute
+	start = time.Now()
+	if _, err := tb.WaitForConfirmation(context.Background(), "abc123", 20*time.Millisecond); !errors.Is(err, ErrConfirmationTimeout) {
+		t.Fatalf("expected ErrConfirmationTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the timeout argument to replace ConfirmTimeout, waited %v", elapsed)
+	}
+}
+
+func TestSimulate_SimulateTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.SimulateTimeout = 20 * time.Millisecond
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), rc)
+
+	op, err := buildContractOp(testContractHex, "get_balance", nil)
+	if err != nil {
+		t.Fatalf("buildContractOp failed: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := tb.simulatePreview(context.Background(), []txnbuild.Operation{op}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the simulation to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected SimulateTimeout to cut the call short, waited %v", elapsed)
+	}
+}
+
+func TestSequenceCoordinator_SharedSource(t *testing.T) {
+	kp := keypair.MustRandom()
+	var lookups atomic.Int32
+	var mu sync.Mutex
+	next := int64(101)
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		mu.Lock()
+		seq := next - 1
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"account_id":%q,"sequence":"%d"}`, kp.Address(), seq)
+	}))
+	t.Cleanup(horizon.Close)
+
+	srv := newFakeRPC(t)
+	badSeqResult := rejectedTx(t, xdr.TransactionResultCodeTxBadSeq)
+	srv.answerFunc("sendTransaction", func(params json.RawMessage) string {
+		var p struct {
+			Transaction string `json:"transaction"`
+		}
+		_ = json.Unmarshal(params, &p)
+		tx, err := txnbuild.TransactionFromXDR(p.Transaction)
+		if err != nil {
+			t.Errorf("failed to decode submitted transaction: %v", err)
+			return badSeqResult
+		}
+		inner, _ := tx.Transaction()
+		mu.Lock()
+		defer mu.Unlock()
+		if inner.SequenceNumber() != next {
+			return badSeqResult
+		}
+		next++
+		return sentTx
+	})
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	client.horizonClient.HorizonURL = horizon.URL
+	sequences := NewSequenceCoordinator()
+
+	const builders = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, builders*2)
+	for i := 0; i < builders; i++ {
+		tb, _ := NewTransactionBuilder(client, kp.Seed(), RetryConfig{})
+		tb.AutoAuth = false
+		tb.Sequences = sequences
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 2; j++ {
+				_, err := tb.BuildAndSubmit(context.Background(), []txnbuild.Operation{&txnbuild.BumpSequence{}})
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("BuildAndSubmit failed: %v", err)
+		}
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("expected the shared sequence to be loaded once, got %d lookups", n)
+	}
+	if next != 101+builders*2 {
+		t.Errorf("expected %d submissions, got %d", builders*2, next-101)
+	}
+}
+
+func TestSequenceCoordinator_DistinctSourcesDontWait(t *testing.T) {
+	c := NewSequenceCoordinator()
+	held, err := c.acquire(context.Background(), "GA")
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	defer held.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	other, err := c.acquire(ctx, "GB")
+	if err != nil {
+		t.Fatalf("expected a distinct source to be available, got %v", err)
+	}
+	other.release()
+
+	if _, err := c.acquire(ctx, "GA"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the held source to wait until the context expired, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_LogsLifecycle(t *testing.T) {
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+			Ext:           xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{ResourceFee: 1234}},
+		}},
+	}
+	envXDR, err := xdr.MarshalBase64(env)
+	if err != nil {
+		t.Fatalf("MarshalBase64 failed: %v", err)
+	}
+	srv := newFakeRPC(t)
+	srv.answer("getTransaction", confirmedTx(t, 42, 5678, envXDR))
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	rc := DefaultRetryConfig()
+	rc.ConfirmPollInterval = 5 * time.Millisecond
+
+	var buf bytes.Buffer
+	tb := &TransactionBuilder{
+		client:      client,
+		retryConfig: rc,
+		Logger:      slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	result, err := tb.WaitForConfirmation(context.Background(), "abc123", time.Minute)
+	if err != nil {
+		t.Fatalf("WaitForConfirmation failed: %v", err)
+	}
+	if result.FeeCharged != 5678 || result.ResourceFee != 1234 {
+		t.Errorf("expected the fees on the result, got %+v", result)
+	}
+	logs := buf.String()
+	for _, want := range []string{`msg="transaction confirming"`, `msg="transaction confirmed"`, "tx_hash=abc123", "ledger=42", "resource_fee=1234"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected %s in the logs, got:\n%s", want, logs)
+		}
+	}
+
+	buf.Reset()
+	tb.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	if _, err := tb.WaitForConfirmation(context.Background(), "abc123", time.Minute); err != nil {
+		t.Fatalf("WaitForConfirmation failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "transaction confirm") {
+		t.Errorf("expected no lifecycle entries above debug level, got:\n%s", buf.String())
+	}
+}
@@ -1,10 +1,13 @@
 package soroban
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/stellar/go/xdr"
+
+	"github.com/jagadeesh/grainlify/backend/internal/backoff"
 )
 
 // Network represents the Stellar network (testnet or mainnet)
@@ -61,6 +64,10 @@ type TransactionResult struct {
 	Status    string    `json:"status"`
 	Submitted time.Time `json:"submitted"`
 	Confirmed time.Time `json:"confirmed,omitempty"`
+	// FeeCharged is the total fee the confirmed transaction paid, in stroops
+	FeeCharged int64 `json:"fee_charged,omitempty"`
+	// ResourceFee is the Soroban resource fee the transaction declared
+	ResourceFee int64 `json:"resource_fee,omitempty"`
 }
 
 // ContractAddress represents a Soroban contract address
@@ -77,20 +84,107 @@ func (ca *ContractAddress) String() string {
 	return ""
 }
 
-// RetryConfig configures retry behavior for transactions
+// RetryConfig configures retry behavior for transactions.
+//
+// Retry delays grow exponentially from InitialDelay by BackoffMultiplier and
+// are capped at MaxDelay. Each wait then uses "full jitter": a random duration
+// between 0 and the computed backoff, so concurrent submitters don't retry in
+// lockstep. Set DisableJitter to restore the previous fixed exponential delays.
 type RetryConfig struct {
 	MaxRetries        int
 	InitialDelay      time.Duration
 	MaxDelay          time.Duration
 	BackoffMultiplier float64
+
+	// DisableJitter waits for the full computed backoff instead of a random
+	// fraction of it
+	DisableJitter bool
+	// JitterFunc returns a duration in [0, d]. Nil uses math/rand; inject a
+	// deterministic function in tests.
+	JitterFunc func(d time.Duration) time.Duration
+
+	// ConfirmPollInterval is how often WaitForConfirmation checks for the
+	// transaction. Zero uses DefaultConfirmPollInterval; a negative interval
+	// is rejected by Validate.
+	ConfirmPollInterval time.Duration
+	// ConfirmMaxAttempts is how many checks WaitForConfirmation makes before
+	// giving up. Zero uses DefaultConfirmMaxAttempts; a negative count is
+	// rejected by Validate.
+	ConfirmMaxAttempts int
+
+	// SimulateTimeout bounds each simulation, including the auth preflight
+	// before a submission, so read-only calls can fail fast. Zero leaves
+	// simulations bounded only by the caller's context.
+	//
+	// ConfirmTimeout bounds WaitForConfirmation when it is called without a
+	// timeout, as the contract helpers do, so a write can be given longer to
+	// land; raise ConfirmMaxAttempts with it, since polling also stops after
+	// that many checks. A timeout passed to WaitForConfirmation takes
+	// precedence, and zero uses DefaultConfirmTimeout. A shorter deadline on
+	// the caller's context still wins.
+	SimulateTimeout time.Duration
+	ConfirmTimeout  time.Duration
+}
+
+const (
+	// DefaultConfirmPollInterval is the default confirmation poll interval
+	DefaultConfirmPollInterval = 2 * time.Second
+	// DefaultConfirmMaxAttempts is the default number of confirmation checks
+	DefaultConfirmMaxAttempts = 30
+	// DefaultConfirmTimeout bounds WaitForConfirmation when neither the call
+	// nor the RetryConfig sets a timeout
+	DefaultConfirmTimeout = 60 * time.Second
+)
+
+// Validate reports settings that cannot be used, such as a negative
+// confirmation poll interval. Transaction builders are only created from a
+// config that passes.
+func (rc RetryConfig) Validate() error {
+	var errs []error
+	if rc.ConfirmPollInterval < 0 {
+		errs = append(errs, fmt.Errorf("confirm poll interval must not be negative, got %s", rc.ConfirmPollInterval))
+	}
+	if rc.ConfirmMaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("confirm max attempts must not be negative, got %d", rc.ConfirmMaxAttempts))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid retry config: %w", err)
+	}
+	return nil
+}
+
+// confirmPolling returns the effective poll interval and attempt limit,
+// using the defaults for unset values
+func (rc RetryConfig) confirmPolling() (time.Duration, int) {
+	interval, attempts := rc.ConfirmPollInterval, rc.ConfirmMaxAttempts
+	if interval == 0 {
+		interval = DefaultConfirmPollInterval
+	}
+	if attempts == 0 {
+		attempts = DefaultConfirmMaxAttempts
+	}
+	return interval, attempts
+}
+
+// backoff returns the retry schedule described by rc
+func (rc RetryConfig) backoff() backoff.Backoff {
+	return backoff.Backoff{
+		Base:       rc.InitialDelay,
+		Max:        rc.MaxDelay,
+		Multiplier: rc.BackoffMultiplier,
+		Jitter:     !rc.DisableJitter,
+		JitterFunc: rc.JitterFunc,
+	}
 }
 
 // DefaultRetryConfig returns a default retry configuration
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
-		MaxRetries:        3,
-		InitialDelay:      time.Second,
-		MaxDelay:          30 * time.Second,
-		BackoffMultiplier: 2.0,
+		MaxRetries:          3,
+		InitialDelay:        time.Second,
+		MaxDelay:            30 * time.Second,
+		BackoffMultiplier:   2.0,
+		ConfirmPollInterval: DefaultConfirmPollInterval,
+		ConfirmMaxAttempts:  DefaultConfirmMaxAttempts,
 	}
 }
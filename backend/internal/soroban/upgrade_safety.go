@@ -1,10 +1,18 @@
 package soroban
 
 import (
+	"bufio"
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 )
@@ -16,6 +24,11 @@ type UpgradeSafetyReport struct {
 	ChecksFailed uint32            `json:"checks_failed"`
 	Warnings     []UpgradeWarning `json:"warnings"`
 	Errors       []UpgradeError   `json:"errors"`
+
+	// LedgerSeq is the ledger whose state the checks ran against, and
+	// ComputedAt is when the report was produced
+	LedgerSeq  uint32    `json:"ledger_seq"`
+	ComputedAt time.Time `json:"computed_at"`
 }
 
 // UpgradeWarning represents a warning during safety check
@@ -46,68 +59,240 @@ var SafetyCheckCodes = map[uint32]string{
 
 // UpgradeSafetyClient provides methods for upgrade safety checks
 type UpgradeSafetyClient struct {
-	client        *Client
-	contractAddr  string
+	client       *Client
+	txBuilder    *TransactionBuilder
+	contractAddr string
+
+	// AllowedWasmHashes restricts ValidateUpgrade to audited WASM hashes.
+	// Empty means any hash is allowed.
+	AllowedWasmHashes [][32]byte
+
+	// RequireNetwork is the network passphrase mutating methods must run
+	// against. When set, they return ErrWrongNetwork before submitting
+	// anything if the client is connected elsewhere.
+	RequireNetwork string
+
+	// AuditLogger, if set, records each privileged operation after it is
+	// submitted
+	AuditLogger AuditLogger
 }
 
-// NewUpgradeSafetyClient creates a new upgrade safety client
-func NewUpgradeSafetyClient(client *Client, contractAddress string) *UpgradeSafetyClient {
+// NewUpgradeSafetyClient creates a new upgrade safety client. It returns an
+// error if contractAddress is malformed.
+func NewUpgradeSafetyClient(client *Client, txBuilder *TransactionBuilder, contractAddress string) (*UpgradeSafetyClient, error) {
+	if err := ValidateContractAddress(contractAddress); err != nil {
+		return nil, fmt.Errorf("invalid contract address: %w", err)
+	}
+
 	return &UpgradeSafetyClient{
 		client:       client,
+		txBuilder:    txBuilder,
 		contractAddr: contractAddress,
-	}
+	}, nil
 }
 
 // SimulateUpgrade performs a dry-run of the upgrade safety checks
 // This does not modify any state but validates all pre-conditions
 func (u *UpgradeSafetyClient) SimulateUpgrade(ctx context.Context) (*UpgradeSafetyReport, error) {
+	report, _, err := u.simulateUpgrade(ctx)
+	return report, err
+}
+
+// SimulateUpgradeAt runs the safety checks and asserts that they ran against
+// ledgerSeq, so a report is tied to the ledger a decision was made at.
+// Soroban RPC simulates against its latest ledger only, so this cannot
+// replay historical state: unless ledgerSeq is the RPC's current ledger it
+// returns an error matching ErrLedgerUnavailable.
+func (u *UpgradeSafetyClient) SimulateUpgradeAt(ctx context.Context, ledgerSeq uint32) (*UpgradeSafetyReport, error) {
+	if ledgerSeq == 0 {
+		return nil, fmt.Errorf("ledger sequence is required")
+	}
+
+	report, latest, err := u.simulateUpgrade(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != ledgerSeq {
+		return nil, fmt.Errorf("%w: simulating at a ledger other than the latest is not supported (ledger %d requested, RPC is at ledger %d)", ErrLedgerUnavailable, ledgerSeq, latest)
+	}
+	return report, nil
+}
+
+// simulateUpgrade simulates simulate_upgrade and returns the report along
+// with the ledger the simulation ran against
+func (u *UpgradeSafetyClient) simulateUpgrade(ctx context.Context) (*UpgradeSafetyReport, uint32, error) {
 	// Encode the contract address
 	contractAddr, err := EncodeContractAddress(u.contractAddr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid contract address: %w", err)
+		return nil, 0, fmt.Errorf("invalid contract address: %w", err)
 	}
 
 	// Build the invoke host function for simulate_upgrade
 	// The function takes no arguments
 	op, err := BuildInvokeHostFunctionOp(contractAddr, "simulate_upgrade", []xdr.ScVal{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to build operation: %w", err)
+		return nil, 0, fmt.Errorf("failed to build operation: %w", err)
 	}
 
-	// Build and submit the transaction
-	txBuilder := NewTransactionBuilder(u.client, u.contractAddr)
-	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	// Simulate the transaction; simulate_upgrade is read-only
+	result, err := u.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
 	if err != nil {
-		return nil, fmt.Errorf("failed to simulate upgrade: %w", err)
+		return nil, 0, fmt.Errorf("failed to simulate upgrade: %w", err)
 	}
 
 	// Parse the result
-	if len(result.Results) == 0 || result.Results[0] == nil {
-		return nil, fmt.Errorf("no results returned from simulation")
+	ret, err := ParseReturnValue(result)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// The result should contain the UpgradeSafetyReport
-	// Parse the XDR return value
-	var report UpgradeSafetyReport
-	if err := xdr.Unmarshal(&report, result.Results[0].ReturnValue); err != nil {
-		// If we can't parse, return a default report
-		// This might happen if the contract hasn't implemented simulate_upgrade
-		return &UpgradeSafetyReport{
+	report, err := DecodeUpgradeSafetyReport(ret)
+	if err != nil {
+		// This might happen if the contract hasn't implemented
+		// simulate_upgrade, so report it as unsafe rather than failing
+		slog.Warn("failed to decode upgrade safety report", "contract", u.contractAddr, "error", err)
+		report = &UpgradeSafetyReport{
 			IsSafe:       false,
 			ChecksPassed: 0,
 			ChecksFailed: 1,
 			Errors: []UpgradeError{
 				{Code: 0, Message: "Contract does not support upgrade safety checks"},
 			},
-		}, nil
+		}
+	}
+	report.LedgerSeq = result.LatestLedger
+	report.ComputedAt = time.Now().UTC()
+	return report, result.LatestLedger, nil
+}
+
+// DecodeUpgradeSafetyReport decodes the UpgradeSafetyReport struct returned
+// by simulate_upgrade. The error names the first field that didn't decode.
+func DecodeUpgradeSafetyReport(v xdr.ScVal) (*UpgradeSafetyReport, error) {
+	fields, err := DecodeScValStruct(v)
+	if err != nil {
+		return nil, err
 	}
 
-	return &report, nil
+	report := &UpgradeSafetyReport{}
+	if report.IsSafe, err = structField(fields, "is_safe", DecodeScValBool); err != nil {
+		return nil, err
+	}
+	if report.ChecksPassed, err = structField(fields, "checks_passed", DecodeScValUint32); err != nil {
+		return nil, err
+	}
+	if report.ChecksFailed, err = structField(fields, "checks_failed", DecodeScValUint32); err != nil {
+		return nil, err
+	}
+	warnings, err := structField(fields, "warnings", decodeUpgradeIssues)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		report.Warnings = append(report.Warnings, UpgradeWarning(w))
+	}
+	if report.Errors, err = structField(fields, "errors", decodeUpgradeIssues); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// decodeUpgradeIssues decodes a vector of {code, message} structs
+func decodeUpgradeIssues(v xdr.ScVal) ([]UpgradeError, error) {
+	vec, ok := v.GetVec()
+	if !ok || vec == nil {
+		return nil, fmt.Errorf("expected vec, got %s", v.Type)
+	}
+
+	issues := make([]UpgradeError, 0, len(*vec))
+	for i, item := range *vec {
+		fields, err := DecodeScValStruct(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		code, err := structField(fields, "code", DecodeScValUint32)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		message, err := structField(fields, "message", DecodeScValString)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		issues = append(issues, UpgradeError{Code: code, Message: message})
+	}
+	return issues, nil
+}
+
+// SimulateUpgradeHandle tracks an in-flight asynchronous upgrade simulation
+type SimulateUpgradeHandle struct {
+	cancel  context.CancelFunc
+	reportC chan *UpgradeSafetyReport
+	errC    chan error
+	done    chan struct{}
+}
+
+// SimulateUpgradeAsync starts SimulateUpgrade in the background and returns a
+// handle that can cancel it. Cancelling aborts the underlying RPC call via the
+// derived context. Callers that re-trigger simulations (e.g. a UI refreshing
+// on input) should Cancel the previous handle before starting a new one.
+func (u *UpgradeSafetyClient) SimulateUpgradeAsync(ctx context.Context) *SimulateUpgradeHandle {
+	return startSimulateUpgrade(ctx, u.SimulateUpgrade)
+}
+
+// startSimulateUpgrade runs simulate in a goroutine bound to a cancelable context
+func startSimulateUpgrade(ctx context.Context, simulate func(context.Context) (*UpgradeSafetyReport, error)) *SimulateUpgradeHandle {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &SimulateUpgradeHandle{
+		cancel: cancel,
+		// Buffered so the goroutine never blocks if nobody reads the result
+		reportC: make(chan *UpgradeSafetyReport, 1),
+		errC:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		// Release the context's resources once the simulation finishes
+		defer cancel()
+		defer close(h.done)
+
+		report, err := simulate(ctx)
+		if err != nil {
+			h.errC <- err
+			return
+		}
+		h.reportC <- report
+	}()
+
+	return h
+}
+
+// Cancel aborts the simulation. It is safe to call multiple times and after
+// the simulation has completed.
+func (h *SimulateUpgradeHandle) Cancel() {
+	h.cancel()
+}
+
+// Result returns channels that receive the outcome. Exactly one of them
+// receives a single value: the report on success or the error on failure
+// (context.Canceled if the simulation was cancelled).
+func (h *SimulateUpgradeHandle) Result() (<-chan *UpgradeSafetyReport, <-chan error) {
+	return h.reportC, h.errC
+}
+
+// Done is closed once the simulation has finished and its resources are released
+func (h *SimulateUpgradeHandle) Done() <-chan struct{} {
+	return h.done
 }
 
 // ValidateUpgrade performs the actual upgrade with safety checks
 // This will fail if any safety check fails
-func (u *UpgradeSafetyClient) ValidateUpgrade(ctx context.Context, newWasmHash uint32) error {
+func (u *UpgradeSafetyClient) ValidateUpgrade(ctx context.Context, newWasmHash [32]byte) error {
+	if err := u.client.requireNetwork(u.RequireNetwork); err != nil {
+		return err
+	}
+	if err := checkWasmHashAllowed(newWasmHash, u.AllowedWasmHashes); err != nil {
+		return err
+	}
+
 	// First, run safety simulation
 	report, err := u.SimulateUpgrade(ctx)
 	if err != nil {
@@ -127,7 +312,7 @@ func (u *UpgradeSafetyClient) ValidateUpgrade(ctx context.Context, newWasmHash u
 	}
 
 	// Encode the wasm hash as argument
-	wasmHashVal, err := EncodeScValUint32(newWasmHash)
+	wasmHashVal, err := EncodeScValBytes(newWasmHash[:])
 	if err != nil {
 		return fmt.Errorf("failed to encode wasm hash: %w", err)
 	}
@@ -139,16 +324,25 @@ func (u *UpgradeSafetyClient) ValidateUpgrade(ctx context.Context, newWasmHash u
 	}
 
 	// Build and submit the transaction
-	txBuilder := NewTransactionBuilder(u.client, u.contractAddr)
-	_, err = txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	result, err := u.txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
 	if err != nil {
 		return fmt.Errorf("failed to upgrade contract: %w", err)
 	}
 
+	recordAudit(ctx, u.AuditLogger, AuditEntry{
+		Operation: "upgrade",
+		Contract:  u.contractAddr,
+		Signer:    u.txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"new_wasm_hash": hex.EncodeToString(newWasmHash[:])},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
 	return nil
 }
 
-// GetUpgradeSafetyStatus checks if safety checks are enabled
+// GetUpgradeSafetyStatus checks if safety checks are enabled. Contracts that
+// predate get_upgrade_safety_status return ErrFunctionNotImplemented.
 func (u *UpgradeSafetyClient) GetUpgradeSafetyStatus(ctx context.Context) (bool, error) {
 	contractAddr, err := EncodeContractAddress(u.contractAddr)
 	if err != nil {
@@ -160,27 +354,159 @@ func (u *UpgradeSafetyClient) GetUpgradeSafetyStatus(ctx context.Context) (bool,
 		return false, fmt.Errorf("failed to build operation: %w", err)
 	}
 
-	txBuilder := NewTransactionBuilder(u.client, u.contractAddr)
-	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	result, err := u.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
 	if err != nil {
 		return false, fmt.Errorf("failed to get safety status: %w", err)
 	}
 
-	if len(result.Results) == 0 || result.Results[0] == nil {
-		return false, fmt.Errorf("no results returned")
+	ret, err := ParseReturnValue(result)
+	if err != nil {
+		return false, err
 	}
 
 	// Parse boolean result
-	var enabled bool
-	if err := xdr.Unmarshal(&enabled, result.Results[0].ReturnValue); err != nil {
+	enabled, err := DecodeScValBool(ret)
+	if err != nil {
 		return false, fmt.Errorf("failed to parse result: %w", err)
 	}
 
 	return enabled, nil
 }
 
-// SetUpgradeSafety enables or disables safety checks
-func (u *UpgradeSafetyClient) SetUpgradeSafety(ctx context.Context, enabled bool, adminKey *txnbuild.SimpleKey) error {
+// GetUpgradeSafetyStatusOrDefault is GetUpgradeSafetyStatus, except that def
+// is returned with a nil error when the contract doesn't implement the
+// status function. RPC and decoding errors are still returned.
+func (u *UpgradeSafetyClient) GetUpgradeSafetyStatusOrDefault(ctx context.Context, def bool) (bool, error) {
+	enabled, err := u.GetUpgradeSafetyStatus(ctx)
+	if errors.Is(err, ErrFunctionNotImplemented) {
+		slog.Debug("contract does not implement get_upgrade_safety_status, using default",
+			"contract", u.contractAddr,
+			"default", def,
+		)
+		return def, nil
+	}
+	return enabled, err
+}
+
+// GetCurrentWasmHash reads the hash of the WASM the contract is running from
+// its instance ledger entry. No host function is invoked, so the result
+// doesn't depend on the contract reporting its own version honestly, which
+// makes it the reference to verify an upgrade against. A contract that
+// isn't backed by WASM, e.g. a Stellar Asset Contract, returns a
+// *NotWasmContractError.
+func (u *UpgradeSafetyClient) GetCurrentWasmHash(ctx context.Context) ([32]byte, error) {
+	instance, err := u.readInstance(ctx)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return instanceWasmHash(instance)
+}
+
+// readInstance reads the contract's instance ledger entry
+func (u *UpgradeSafetyClient) readInstance(ctx context.Context) (xdr.ScVal, error) {
+	keys, err := NewLedgerKeyBuilder(u.contractAddr)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+
+	entries, err := u.client.ReadEntries(ctx, []xdr.LedgerKey{keys.Instance()})
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to read contract instance: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].Found || entries[0].Data.ContractData == nil {
+		return xdr.ScVal{}, fmt.Errorf("contract instance not found")
+	}
+	return entries[0].Data.ContractData.Val, nil
+}
+
+// IsInitialized reports whether the contract has been initialized, i.e.
+// whether init has stored an admin in its instance storage. A deployed but
+// uninitialized contract fails safety check 1002 until Initialize is called.
+func (u *UpgradeSafetyClient) IsInitialized(ctx context.Context) (bool, error) {
+	v, err := u.readInstance(ctx)
+	if err != nil {
+		return false, err
+	}
+	instance, ok := v.GetInstance()
+	if !ok {
+		return false, fmt.Errorf("ledger entry is not a contract instance: %s", v.Type)
+	}
+	if instance.Storage == nil {
+		return false, nil
+	}
+	adminKey := EnumKey("Admin")
+	for _, entry := range *instance.Storage {
+		if entry.Key.Equals(adminKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Initialize calls the contract's init function with args, e.g. the admin
+// and token addresses for the escrow. It checks IsInitialized first and
+// returns ErrAlreadyInitialized rather than submitting a re-init the contract
+// would reject, so it is safe to call on every deploy. If adminKey is nil the
+// transaction builder's source account signs.
+func (u *UpgradeSafetyClient) Initialize(ctx context.Context, args []xdr.ScVal, adminKey *keypair.Full) error {
+	if err := u.client.requireNetwork(u.RequireNetwork); err != nil {
+		return err
+	}
+
+	initialized, err := u.IsInitialized(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check initialization: %w", err)
+	}
+	if initialized {
+		return ErrAlreadyInitialized
+	}
+
+	contractAddr, err := EncodeContractAddress(u.contractAddr)
+	if err != nil {
+		return fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, "init", args)
+	if err != nil {
+		return fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	txBuilder := u.txBuilder.withSigner(adminKey)
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return fmt.Errorf("failed to initialize contract: %w", err)
+	}
+
+	recordAudit(ctx, u.AuditLogger, AuditEntry{
+		Operation: "init",
+		Contract:  u.contractAddr,
+		Signer:    txBuilder.signer.PublicKey(),
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
+	return nil
+}
+
+// instanceWasmHash extracts the WASM hash from a contract instance value
+func instanceWasmHash(v xdr.ScVal) ([32]byte, error) {
+	instance, ok := v.GetInstance()
+	if !ok {
+		return [32]byte{}, fmt.Errorf("ledger entry is not a contract instance: %s", v.Type)
+	}
+	if instance.Executable.Type != xdr.ContractExecutableTypeContractExecutableWasm || instance.Executable.WasmHash == nil {
+		return [32]byte{}, &NotWasmContractError{Executable: instance.Executable.Type}
+	}
+	return *instance.Executable.WasmHash, nil
+}
+
+// SetUpgradeSafety enables or disables safety checks. If adminKey is nil the
+// transaction builder's source account signs.
+func (u *UpgradeSafetyClient) SetUpgradeSafety(ctx context.Context, enabled bool, adminKey *keypair.Full) error {
+	if err := u.client.requireNetwork(u.RequireNetwork); err != nil {
+		return err
+	}
+
 	contractAddr, err := EncodeContractAddress(u.contractAddr)
 	if err != nil {
 		return fmt.Errorf("invalid contract address: %w", err)
@@ -196,12 +522,21 @@ func (u *UpgradeSafetyClient) SetUpgradeSafety(ctx context.Context, enabled bool
 		return fmt.Errorf("failed to build operation: %w", err)
 	}
 
-	txBuilder := NewTransactionBuilderWithKey(u.client, u.contractAddr, adminKey)
-	_, err = txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	txBuilder := u.txBuilder.withSigner(adminKey)
+	result, err := txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
 	if err != nil {
 		return fmt.Errorf("failed to set safety status: %w", err)
 	}
 
+	recordAudit(ctx, u.AuditLogger, AuditEntry{
+		Operation: "set_upgrade_safety",
+		Contract:  u.contractAddr,
+		Signer:    txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"enabled": enabled},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
 	return nil
 }
 
@@ -213,6 +548,16 @@ type UpgradeSafetyConfig struct {
 	RequireSafetyChecks bool
 	// Maximum number of warnings allowed
 	MaxWarnings uint32
+	// WASM hashes the contract may be upgraded to; empty allows any hash
+	AllowedWasmHashes [][32]byte
+	// MinSourceVersion, if set, rejects upgrading contracts older than it
+	MinSourceVersion *ContractVersion
+	// MaxTargetVersion, if set, rejects WASM declaring a newer version
+	MaxTargetVersion *ContractVersion
+	// ConfirmFunc, if set, is the final gate before submission: it is shown
+	// the passing safety report, e.g. to prompt "proceed? [y/N]", and
+	// returning false aborts with ErrUpgradeAborted. Nil proceeds.
+	ConfirmFunc func(report *UpgradeSafetyReport) (bool, error)
 }
 
 // DefaultUpgradeSafetyConfig returns the default configuration
@@ -225,28 +570,29 @@ func DefaultUpgradeSafetyConfig() UpgradeSafetyConfig {
 }
 
 // ValidateUpgradeWithConfig performs upgrade with custom configuration
-func (u *UpgradeSafetyClient) ValidateUpgradeWithConfig(ctx context.Context, newWasmHash uint32, config UpgradeSafetyConfig) error {
-	// Run safety simulation
-	ctx, cancel := context.WithTimeout(ctx, config.SimulationTimeout)
+func (u *UpgradeSafetyClient) ValidateUpgradeWithConfig(ctx context.Context, newWasmHash [32]byte, config UpgradeSafetyConfig) error {
+	checkCtx, cancel := context.WithTimeout(ctx, config.SimulationTimeout)
 	defer cancel()
 
-	report, err := u.SimulateUpgrade(ctx)
+	report, err := u.checkUpgrade(checkCtx, newWasmHash, config)
 	if err != nil {
-		return fmt.Errorf("safety simulation failed: %w", err)
+		return err
 	}
 
-	// Check if safety checks are required
-	if config.RequireSafetyChecks && !report.IsSafe {
-		return fmt.Errorf("upgrade rejected by safety checks: %d errors", len(report.Errors))
-	}
-
-	// Check warning threshold
-	if report.ChecksFailed > 0 {
-		return fmt.Errorf("upgrade has %d failed checks", report.ChecksFailed)
-	}
-
-	if report.ChecksPassed < 10 {
-		return fmt.Errorf("incomplete safety check: only %d/10 checks passed", report.ChecksPassed)
+	submitCtx := checkCtx
+	if config.ConfirmFunc != nil {
+		proceed, err := config.ConfirmFunc(report)
+		if err != nil {
+			return fmt.Errorf("upgrade confirmation failed: %w", err)
+		}
+		if !proceed {
+			return ErrUpgradeAborted
+		}
+		// Answering may outlast the simulation timeout, so the submission
+		// gets a fresh one
+		var cancelSubmit context.CancelFunc
+		submitCtx, cancelSubmit = context.WithTimeout(ctx, config.SimulationTimeout)
+		defer cancelSubmit()
 	}
 
 	// Perform the upgrade
@@ -255,7 +601,7 @@ func (u *UpgradeSafetyClient) ValidateUpgradeWithConfig(ctx context.Context, new
 		return fmt.Errorf("invalid contract address: %w", err)
 	}
 
-	wasmHashVal, err := EncodeScValUint32(newWasmHash)
+	wasmHashVal, err := EncodeScValBytes(newWasmHash[:])
 	if err != nil {
 		return fmt.Errorf("failed to encode wasm hash: %w", err)
 	}
@@ -265,17 +611,146 @@ func (u *UpgradeSafetyClient) ValidateUpgradeWithConfig(ctx context.Context, new
 		return fmt.Errorf("failed to build operation: %w", err)
 	}
 
-	txBuilder := NewTransactionBuilder(u.client, u.contractAddr)
-	_, err = txBuilder.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	result, err := u.txBuilder.BuildAndSubmit(submitCtx, []txnbuild.Operation{op})
 	if err != nil {
 		return fmt.Errorf("failed to upgrade contract: %w", err)
 	}
 
+	recordAudit(submitCtx, u.AuditLogger, AuditEntry{
+		Operation: "upgrade",
+		Contract:  u.contractAddr,
+		Signer:    u.txBuilder.signer.PublicKey(),
+		Args:      map[string]interface{}{"new_wasm_hash": hex.EncodeToString(newWasmHash[:])},
+		Call:      invokeSummary([]txnbuild.Operation{op}),
+		TxHash:    result.Hash,
+	})
+
 	return nil
 }
 
-// FormatSafetyReport creates a human-readable string from the report
+// ValidateUpgradeDryRun runs every check ValidateUpgradeWithConfig does
+// without submitting the upgrade, e.g. as a CI deploy gate. The safety report
+// is returned whenever the simulation ran, alongside the error if a check
+// rejected the upgrade.
+func (u *UpgradeSafetyClient) ValidateUpgradeDryRun(ctx context.Context, newWasmHash [32]byte, config UpgradeSafetyConfig) (*UpgradeSafetyReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.SimulationTimeout)
+	defer cancel()
+
+	return u.checkUpgrade(ctx, newWasmHash, config)
+}
+
+// checkUpgrade runs ValidateUpgradeWithConfig's pre-conditions: network,
+// allowlist, safety simulation and version policy. The report is nil if the
+// simulation didn't run.
+func (u *UpgradeSafetyClient) checkUpgrade(ctx context.Context, newWasmHash [32]byte, config UpgradeSafetyConfig) (*UpgradeSafetyReport, error) {
+	if err := u.client.requireNetwork(u.RequireNetwork); err != nil {
+		return nil, err
+	}
+	if err := checkWasmHashAllowed(newWasmHash, config.AllowedWasmHashes); err != nil {
+		return nil, err
+	}
+
+	// Run safety simulation
+	report, err := u.SimulateUpgrade(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("safety simulation failed: %w", err)
+	}
+
+	// Check if safety checks are required
+	if config.RequireSafetyChecks && !report.IsSafe {
+		return report, fmt.Errorf("upgrade rejected by safety checks: %d errors", len(report.Errors))
+	}
+
+	// Check warning threshold
+	if report.ChecksFailed > 0 {
+		return report, fmt.Errorf("upgrade has %d failed checks", report.ChecksFailed)
+	}
+
+	if report.ChecksPassed < 10 {
+		return report, fmt.Errorf("incomplete safety check: only %d/10 checks passed", report.ChecksPassed)
+	}
+
+	if err := u.enforceVersionPolicy(ctx, newWasmHash, config); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// enforceVersionPolicy applies config's version bounds. Versions that can't
+// be determined fail the check rather than skipping it.
+func (u *UpgradeSafetyClient) enforceVersionPolicy(ctx context.Context, newWasmHash [32]byte, config UpgradeSafetyConfig) error {
+	var current, target ContractVersion
+	var err error
+	if config.MinSourceVersion != nil {
+		if current, err = u.GetContractVersion(ctx); err != nil {
+			return fmt.Errorf("failed to read current contract version: %w", err)
+		}
+	}
+	if config.MaxTargetVersion != nil {
+		if target, err = u.GetWasmVersion(ctx, newWasmHash); err != nil {
+			return fmt.Errorf("failed to read target wasm version: %w", err)
+		}
+	}
+	return checkVersionPolicy(current, target, config.MinSourceVersion, config.MaxTargetVersion)
+}
+
+// checkWasmHashAllowed rejects hashes missing from a non-empty allowlist
+func checkWasmHashAllowed(hash [32]byte, allowed [][32]byte) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, h := range allowed {
+		if h == hash {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrWasmHashNotAllowed, hex.EncodeToString(hash[:]))
+}
+
+// LoadWasmHashAllowlist reads an allowlist file with one hex-encoded WASM hash
+// per line. Blank lines and lines starting with # are ignored.
+func LoadWasmHashAllowlist(path string) ([][32]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open allowlist: %w", err)
+	}
+	defer f.Close()
+
+	return parseWasmHashAllowlist(f)
+}
+
+func parseWasmHashAllowlist(r io.Reader) ([][32]byte, error) {
+	var hashes [][32]byte
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		raw, err := hex.DecodeString(line)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("line %d: invalid wasm hash %q", lineNo, line)
+		}
+
+		var hash [32]byte
+		copy(hash[:], raw)
+		hashes = append(hashes, hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// FormatSafetyReport creates a human-readable string from the report. A nil
+// report, e.g. from a failed simulation, formats as a notice instead.
 func FormatSafetyReport(report *UpgradeSafetyReport) string {
+	if report == nil {
+		return "Upgrade safety report: no report available\n"
+	}
+
 	var status string
 	if report.IsSafe {
 		status = "✓ SAFE TO UPGRADE"
@@ -283,15 +758,25 @@ func FormatSafetyReport(report *UpgradeSafetyReport) string {
 		status = "✗ UNSAFE TO UPGRADE"
 	}
 
+	ledger, computedAt := "unknown", "unknown"
+	if report.LedgerSeq != 0 {
+		ledger = fmt.Sprintf("%d", report.LedgerSeq)
+	}
+	if !report.ComputedAt.IsZero() {
+		computedAt = report.ComputedAt.UTC().Format(time.RFC3339)
+	}
+
 	output := fmt.Sprintf(`
 ══════════════════════════════════════════════════════════════════
   UPGRADE SAFETY REPORT
 ══════════════════════════════════════════════════════════════════
   Status: %s
+  Ledger: %s
+  Computed At: %s
   Checks Passed: %d
   Checks Failed: %d
 ══════════════════════════════════════════════════════════════════
-`, status, report.ChecksPassed, report.ChecksFailed)
+`, status, ledger, computedAt, report.ChecksPassed, report.ChecksFailed)
 
 	if len(report.Errors) > 0 {
 		output += "\nERRORS:\n"
@@ -0,0 +1,437 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSimulateUpgradeAsync_Result(t *testing.T) {
+	h := startSimulateUpgrade(context.Background(), func(ctx context.Context) (*UpgradeSafetyReport, error) {
+		return &UpgradeSafetyReport{IsSafe: true, ChecksPassed: 10}, nil
+	})
+
+	reportC, errC := h.Result()
+	select {
+	case report := <-reportC:
+		if !report.IsSafe {
+			t.Error("expected safe report")
+		}
+	case err := <-errC:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for simulation result")
+	}
+}
+
+func TestSimulateUpgradeAsync_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	h := startSimulateUpgrade(context.Background(), func(ctx context.Context) (*UpgradeSafetyReport, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	<-started
+	h.Cancel()
+
+	_, errC := h.Result()
+	select {
+	case err := <-errC:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancellation did not propagate to the simulation")
+	}
+
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("handle not marked done after cancellation")
+	}
+
+	// Cancel after completion must be a no-op.
+	h.Cancel()
+}
+
+func TestCheckWasmHashAllowed(t *testing.T) {
+	audited := [32]byte{1}
+	other := [32]byte{2}
+
+	if err := checkWasmHashAllowed(other, nil); err != nil {
+		t.Errorf("expected empty allowlist to allow any hash, got %v", err)
+	}
+	if err := checkWasmHashAllowed(audited, [][32]byte{audited}); err != nil {
+		t.Errorf("expected allowlisted hash to pass, got %v", err)
+	}
+	if err := checkWasmHashAllowed(other, [][32]byte{audited}); !errors.Is(err, ErrWasmHashNotAllowed) {
+		t.Errorf("expected ErrWasmHashNotAllowed, got %v", err)
+	}
+}
+
+func TestValidateUpgrade_RejectsBeforeSimulation(t *testing.T) {
+	// No client or builder: the allowlist must reject before any RPC is made.
+	u := &UpgradeSafetyClient{AllowedWasmHashes: [][32]byte{{1}}}
+	if err := u.ValidateUpgrade(context.Background(), [32]byte{2}); !errors.Is(err, ErrWasmHashNotAllowed) {
+		t.Errorf("expected ErrWasmHashNotAllowed, got %v", err)
+	}
+
+	cfg := DefaultUpgradeSafetyConfig()
+	cfg.AllowedWasmHashes = [][32]byte{{1}}
+	if err := u.ValidateUpgradeWithConfig(context.Background(), [32]byte{2}, cfg); !errors.Is(err, ErrWasmHashNotAllowed) {
+		t.Errorf("expected ErrWasmHashNotAllowed, got %v", err)
+	}
+}
+
+func TestParseWasmHashAllowlist(t *testing.T) {
+	input := `# audited releases
+0101010101010101010101010101010101010101010101010101010101010101
+
+0202020202020202020202020202020202020202020202020202020202020202
+`
+	hashes, err := parseWasmHashAllowlist(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseWasmHashAllowlist failed: %v", err)
+	}
+	if len(hashes) != 2 || hashes[0][0] != 1 || hashes[1][31] != 2 {
+		t.Errorf("unexpected hashes: %x", hashes)
+	}
+
+	if _, err := parseWasmHashAllowlist(strings.NewReader("abcd\n")); err == nil {
+		t.Error("expected error for short hash")
+	}
+}
+
+func TestUpgradeSafety_RequireNetwork(t *testing.T) {
+	client, _ := NewClient(Config{RPCURL: "http://localhost", Network: NetworkTestnet})
+	u, _ := NewUpgradeSafetyClient(client, nil, "0000000000000000000000000000000000000000000000000000000000000000")
+	u.RequireNetwork = network.PublicNetworkPassphrase
+
+	if err := u.ValidateUpgrade(context.Background(), [32]byte{1}); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("ValidateUpgrade: expected ErrWrongNetwork, got %v", err)
+	}
+	if err := u.ValidateUpgradeWithConfig(context.Background(), [32]byte{1}, DefaultUpgradeSafetyConfig()); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("ValidateUpgradeWithConfig: expected ErrWrongNetwork, got %v", err)
+	}
+	if err := u.SetUpgradeSafety(context.Background(), false, nil); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("SetUpgradeSafety: expected ErrWrongNetwork, got %v", err)
+	}
+
+	// The guard passes once the client matches
+	u.RequireNetwork = network.TestNetworkPassphrase
+	if err := client.requireNetwork(u.RequireNetwork); err != nil {
+		t.Errorf("expected matching network to pass, got %v", err)
+	}
+}
+
+func TestGetUpgradeSafetyStatusOrDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  string
+		want    bool
+		wantErr bool
+	}{
+		{"not implemented", `{"error":"HostError: Error(WasmVm, MissingValue)","latestLedger":1}`, true, false},
+		{"contract error", `{"error":"HostError: Error(Contract, #1)","latestLedger":1}`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := rpcServer(t, tt.result, nil)
+			client, _ := NewClient(Config{RPCURL: srv.URL})
+			kp := keypair.MustRandom()
+			tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+			// Skip the Horizon account lookup
+			tb.account.store(&txnbuild.SimpleAccount{AccountID: kp.Address()})
+			u, _ := NewUpgradeSafetyClient(client, tb, "0000000000000000000000000000000000000000000000000000000000000000")
+
+			got, err := u.GetUpgradeSafetyStatusOrDefault(context.Background(), true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSimulateUpgradeAt(t *testing.T) {
+	void, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	srv := rpcServer(t, fmt.Sprintf(`{"latestLedger":500,"results":[{"xdr":%q}]}`, void), nil)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	kp := keypair.MustRandom()
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	u, _ := NewUpgradeSafetyClient(client, tb, testContractHex)
+
+	tb.account.store(&txnbuild.SimpleAccount{AccountID: kp.Address()})
+	report, err := u.SimulateUpgradeAt(context.Background(), 500)
+	if err != nil {
+		t.Fatalf("SimulateUpgradeAt failed: %v", err)
+	}
+	if report.LedgerSeq != 500 {
+		t.Errorf("expected report computed at ledger 500, got %d", report.LedgerSeq)
+	}
+	if report.ComputedAt.IsZero() {
+		t.Error("expected ComputedAt to be set")
+	}
+
+	tb.account.store(&txnbuild.SimpleAccount{AccountID: kp.Address()})
+	_, err = u.SimulateUpgradeAt(context.Background(), 400)
+	if !errors.Is(err, ErrLedgerUnavailable) || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected a past ledger to be reported as unsupported, got %v", err)
+	}
+}
+
+func TestFormatSafetyReport_Header(t *testing.T) {
+	report := &UpgradeSafetyReport{
+		IsSafe:     true,
+		LedgerSeq:  12345,
+		ComputedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+	out := FormatSafetyReport(report)
+	if !strings.Contains(out, "Ledger: 12345") || !strings.Contains(out, "Computed At: 2024-03-01T10:00:00Z") {
+		t.Errorf("expected ledger and timestamp in header, got:\n%s", out)
+	}
+
+	if out := FormatSafetyReport(&UpgradeSafetyReport{}); !strings.Contains(out, "Ledger: unknown") {
+		t.Errorf("expected unknown ledger for a report without one, got:\n%s", out)
+	}
+}
+
+func TestFormatSafetyReport_Nil(t *testing.T) {
+	if out := FormatSafetyReport(nil); !strings.Contains(out, "no report available") {
+		t.Errorf("expected a notice for a nil report, got:\n%s", out)
+	}
+
+	out := FormatSafetyReport(&UpgradeSafetyReport{ChecksFailed: 1, Errors: nil, Warnings: nil})
+	if strings.Contains(out, "ERRORS:") || strings.Contains(out, "WARNINGS:") {
+		t.Errorf("expected no issue sections for nil slices, got:\n%s", out)
+	}
+}
+
+func TestDecodeUpgradeSafetyReport_RoundTrip(t *testing.T) {
+	structVal := func(fields ...interface{}) xdr.ScVal {
+		m := xdr.ScMap{}
+		for i := 0; i < len(fields); i += 2 {
+			key, _ := EncodeScValSymbol(fields[i].(string))
+			m = append(m, xdr.ScMapEntry{Key: key, Val: fields[i+1].(xdr.ScVal)})
+		}
+		mPtr := &m
+		return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}
+	}
+	u32 := func(n uint32) xdr.ScVal {
+		v := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	}
+	str := func(s string) xdr.ScVal {
+		v := xdr.ScString(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}
+	}
+	isSafe := true
+	issue := structVal("code", u32(1003), "message", str("escrow mismatch"))
+	warnings, _ := EncodeScValVec([]xdr.ScVal{issue})
+	noErrors, _ := EncodeScValVec([]xdr.ScVal{})
+
+	report, err := DecodeUpgradeSafetyReport(structVal(
+		"is_safe", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &isSafe},
+		"checks_passed", u32(9),
+		"checks_failed", u32(1),
+		"warnings", warnings,
+		"errors", noErrors,
+	))
+	if err != nil {
+		t.Fatalf("DecodeUpgradeSafetyReport failed: %v", err)
+	}
+	if !report.IsSafe || report.ChecksPassed != 9 || report.ChecksFailed != 1 || len(report.Errors) != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0] != (UpgradeWarning{Code: 1003, Message: "escrow mismatch"}) {
+		t.Errorf("unexpected warnings %+v", report.Warnings)
+	}
+
+	_, err = DecodeUpgradeSafetyReport(structVal("is_safe", u32(1)))
+	if err == nil || !strings.Contains(err.Error(), "is_safe") {
+		t.Errorf("expected an error naming is_safe, got %v", err)
+	}
+}
+
+// passingUpgradeClient returns an UpgradeSafetyClient whose safety
+// simulation reports all 10 checks passing. Any submission would fail: the
+// RPC answers sendTransaction with the simulation result, which has no hash.
+func passingUpgradeClient(t *testing.T, calls map[string]*int32) *UpgradeSafetyClient {
+	t.Helper()
+	field := func(name string, val xdr.ScVal) xdr.ScMapEntry {
+		key, _ := EncodeScValSymbol(name)
+		return xdr.ScMapEntry{Key: key, Val: val}
+	}
+	isSafe, passed, failed := true, xdr.Uint32(10), xdr.Uint32(0)
+	empty, _ := EncodeScValVec([]xdr.ScVal{})
+	m := xdr.ScMap{
+		field("checks_failed", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &failed}),
+		field("checks_passed", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &passed}),
+		field("errors", empty),
+		field("is_safe", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &isSafe}),
+		field("warnings", empty),
+	}
+	mPtr := &m
+	ret, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr})
+
+	srv := rpcServer(t, fmt.Sprintf(`{"latestLedger":500,"results":[{"xdr":%q}]}`, ret), calls)
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	client.horizonClient.HorizonURL = "http://127.0.0.1:0"
+	kp := keypair.MustRandom()
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	tb.account.prewarm(&txnbuild.SimpleAccount{AccountID: kp.Address()})
+	u, _ := NewUpgradeSafetyClient(client, tb, testContractHex)
+	return u
+}
+
+func TestValidateUpgradeDryRun(t *testing.T) {
+	u := passingUpgradeClient(t, nil)
+
+	report, err := u.ValidateUpgradeDryRun(context.Background(), [32]byte{1}, DefaultUpgradeSafetyConfig())
+	if err != nil {
+		t.Fatalf("ValidateUpgradeDryRun failed: %v", err)
+	}
+	if !report.IsSafe || report.ChecksPassed != 10 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	cfg := DefaultUpgradeSafetyConfig()
+	cfg.AllowedWasmHashes = [][32]byte{{1}}
+	if _, err := u.ValidateUpgradeDryRun(context.Background(), [32]byte{2}, cfg); !errors.Is(err, ErrWasmHashNotAllowed) {
+		t.Errorf("expected the dry run to apply the allowlist, got %v", err)
+	}
+}
+
+// instanceEntryClient returns an UpgradeSafetyClient whose RPC answers
+// getLedgerEntries with instance as the contract's instance entry
+func instanceEntryClient(t *testing.T, instance xdr.ScContractInstance) (*UpgradeSafetyClient, *fakeRPC) {
+	t.Helper()
+	b, err := NewLedgerKeyBuilder(testContractHex)
+	if err != nil {
+		t.Fatalf("NewLedgerKeyBuilder failed: %v", err)
+	}
+	srv := newFakeRPC(t)
+	srv.putEntry(b.Instance(), xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &instance})
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	tb, _ := NewTransactionBuilder(client, keypair.MustRandom().Seed(), DefaultRetryConfig())
+	u, _ := NewUpgradeSafetyClient(client, tb, testContractHex)
+	return u, srv
+}
+
+func TestGetCurrentWasmHash(t *testing.T) {
+	wasmHash := xdr.Hash{0xab, 0xcd, 0x01}
+	tests := []struct {
+		name       string
+		executable xdr.ContractExecutable
+		wantErr    error
+	}{
+		{"wasm", xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &wasmHash}, nil},
+		{"stellar asset", xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset}, ErrNotWasmContract},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, srv := instanceEntryClient(t, xdr.ScContractInstance{Executable: tt.executable})
+
+			got, err := u.GetCurrentWasmHash(context.Background())
+			if tt.wantErr != nil {
+				var notWasm *NotWasmContractError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &notWasm) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCurrentWasmHash failed: %v", err)
+			}
+			if got != wasmHash {
+				t.Errorf("expected %x, got %x", wasmHash, got)
+			}
+			if n := srv.callCount("getLedgerEntries"); n != 1 {
+				t.Errorf("expected a single getLedgerEntries call, got %d", n)
+			}
+		})
+	}
+}
+
+func TestIsInitialized(t *testing.T) {
+	wasmHash := xdr.Hash{1}
+	executable := xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &wasmHash}
+	admin, _ := EncodeScValAddress(keypair.MustRandom().Address())
+	tests := []struct {
+		name    string
+		storage *xdr.ScMap
+		want    bool
+	}{
+		{"no storage", nil, false},
+		{"no admin", &xdr.ScMap{{Key: EnumKey("Version"), Val: xdr.ScVal{Type: xdr.ScValTypeScvVoid}}}, false},
+		{"admin set", &xdr.ScMap{{Key: EnumKey("Admin"), Val: admin}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := instanceEntryClient(t, xdr.ScContractInstance{Executable: executable, Storage: tt.storage})
+			got, err := u.IsInitialized(context.Background())
+			if err != nil {
+				t.Fatalf("IsInitialized failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInitialize_AlreadyInitialized(t *testing.T) {
+	wasmHash := xdr.Hash{1}
+	admin, _ := EncodeScValAddress(keypair.MustRandom().Address())
+	u, srv := instanceEntryClient(t, xdr.ScContractInstance{
+		Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &wasmHash},
+		Storage:    &xdr.ScMap{{Key: EnumKey("Admin"), Val: admin}},
+	})
+
+	err := u.Initialize(context.Background(), []xdr.ScVal{admin, admin}, nil)
+	if !errors.Is(err, ErrAlreadyInitialized) {
+		t.Fatalf("expected ErrAlreadyInitialized, got %v", err)
+	}
+	if n := srv.callCount("simulateTransaction"); n != 0 {
+		t.Errorf("expected no init to be attempted, got %d simulations", n)
+	}
+}
+
+func TestValidateUpgradeWithConfig_ConfirmFunc(t *testing.T) {
+	calls := map[string]*int32{"simulateTransaction": new(int32)}
+	u := passingUpgradeClient(t, calls)
+
+	var shown *UpgradeSafetyReport
+	cfg := DefaultUpgradeSafetyConfig()
+	cfg.ConfirmFunc = func(report *UpgradeSafetyReport) (bool, error) {
+		shown = report
+		return false, nil
+	}
+	if err := u.ValidateUpgradeWithConfig(context.Background(), [32]byte{1}, cfg); !errors.Is(err, ErrUpgradeAborted) {
+		t.Fatalf("expected ErrUpgradeAborted, got %v", err)
+	}
+	if shown == nil || !shown.IsSafe {
+		t.Errorf("expected the passing report to be shown, got %+v", shown)
+	}
+	if n := atomic.LoadInt32(calls["simulateTransaction"]); n != 1 {
+		t.Errorf("expected only the safety simulation before aborting, got %d simulations", n)
+	}
+
+	promptErr := errors.New("no terminal")
+	cfg.ConfirmFunc = func(*UpgradeSafetyReport) (bool, error) { return false, promptErr }
+	if err := u.ValidateUpgradeWithConfig(context.Background(), [32]byte{1}, cfg); !errors.Is(err, promptErr) {
+		t.Errorf("expected the confirmation error, got %v", err)
+	}
+}
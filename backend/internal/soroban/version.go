@@ -0,0 +1,237 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// wasmVersionMetaKeys are the contractmetav0 keys read as a version, in
+// order of preference
+var wasmVersionMetaKeys = []string{"binver", "version"}
+
+// ContractVersion is a contract's semantic version
+type ContractVersion struct {
+	Major uint32 `json:"major"`
+	Minor uint32 `json:"minor"`
+	Patch uint32 `json:"patch"`
+}
+
+// String renders the version as "major.minor.patch"
+func (v ContractVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o
+func (v ContractVersion) Compare(o ContractVersion) int {
+	for _, d := range [][2]uint32{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] < d[1] {
+			return -1
+		}
+		if d[0] > d[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ParseContractVersion parses "major.minor.patch", with an optional leading
+// "v" and optional minor and patch components
+func ParseContractVersion(s string) (ContractVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return ContractVersion{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	var fields [3]uint32
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return ContractVersion{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		fields[i] = uint32(n)
+	}
+	return ContractVersion{Major: fields[0], Minor: fields[1], Patch: fields[2]}, nil
+}
+
+// decodeEncodedVersion decodes the contract's major*10_000 + minor*100 + patch
+// encoding. Values below 10_000 are plain major versions.
+func decodeEncodedVersion(raw uint32) ContractVersion {
+	if raw < 10_000 {
+		return ContractVersion{Major: raw}
+	}
+	return ContractVersion{Major: raw / 10_000, Minor: raw / 100 % 100, Patch: raw % 100}
+}
+
+// GetContractVersion reads the version the deployed contract declares via
+// get_version_numeric_encoded, falling back to get_version. Contracts that
+// implement neither, or have no version set, return ErrVersionUnknown.
+func (u *UpgradeSafetyClient) GetContractVersion(ctx context.Context) (ContractVersion, error) {
+	for _, fn := range []string{"get_version_numeric_encoded", "get_version"} {
+		raw, err := u.readUint32(ctx, fn)
+		if errors.Is(err, ErrFunctionNotImplemented) {
+			continue
+		}
+		if err != nil {
+			return ContractVersion{}, err
+		}
+		if raw == 0 {
+			return ContractVersion{}, fmt.Errorf("%w: contract has no version set", ErrVersionUnknown)
+		}
+		return decodeEncodedVersion(raw), nil
+	}
+	return ContractVersion{}, fmt.Errorf("%w: contract does not expose a version function", ErrVersionUnknown)
+}
+
+// readUint32 simulates a no-argument contract function returning u32
+func (u *UpgradeSafetyClient) readUint32(ctx context.Context, fn string) (uint32, error) {
+	contractAddr, err := EncodeContractAddress(u.contractAddr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid contract address: %w", err)
+	}
+
+	op, err := BuildInvokeHostFunctionOp(contractAddr, fn, []xdr.ScVal{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to build operation: %w", err)
+	}
+
+	result, err := u.txBuilder.Simulate(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return 0, fmt.Errorf("failed to call %s: %w", fn, err)
+	}
+
+	ret, err := ParseReturnValue(result)
+	if err != nil {
+		return 0, err
+	}
+	return DecodeScValUint32(ret)
+}
+
+// GetWasmVersion reads the version declared in the contractmetav0 section of
+// WASM already uploaded to the network under wasmHash
+func (u *UpgradeSafetyClient) GetWasmVersion(ctx context.Context, wasmHash [32]byte) (ContractVersion, error) {
+	key := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash(wasmHash)},
+	}
+	entries, err := u.client.ReadEntries(ctx, []xdr.LedgerKey{key})
+	if err != nil {
+		return ContractVersion{}, fmt.Errorf("failed to read contract code: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].Found || entries[0].Data.ContractCode == nil {
+		return ContractVersion{}, fmt.Errorf("contract code %x not found on network", wasmHash)
+	}
+	return ReadWasmVersion(entries[0].Data.ContractCode.Code)
+}
+
+// ReadWasmVersion extracts the version from a contract's contractmetav0
+// custom section (set with contractmeta!(key = "binver", ...)). WASM without
+// a section or version key returns ErrVersionUnknown.
+func ReadWasmVersion(wasm []byte) (ContractVersion, error) {
+	section, err := wasmCustomSection(wasm, "contractmetav0")
+	if err != nil {
+		return ContractVersion{}, err
+	}
+	if section == nil {
+		return ContractVersion{}, fmt.Errorf("%w: wasm has no contractmetav0 section", ErrVersionUnknown)
+	}
+
+	meta := make(map[string]string)
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScMetaEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return ContractVersion{}, fmt.Errorf("failed to decode contract metadata: %w", err)
+		}
+		if entry.V0 != nil {
+			meta[entry.V0.Key] = entry.V0.Val
+		}
+	}
+
+	for _, key := range wasmVersionMetaKeys {
+		if val, ok := meta[key]; ok {
+			return ParseContractVersion(val)
+		}
+	}
+	return ContractVersion{}, fmt.Errorf("%w: contract metadata has no version", ErrVersionUnknown)
+}
+
+// wasmCustomSection returns the payload of the named custom section, or nil
+// if the module has none
+func wasmCustomSection(wasm []byte, name string) ([]byte, error) {
+	var data []byte
+	var parseErr error
+	err := wasmSections(wasm, func(id byte, payload []byte) bool {
+		if id != 0 {
+			return true
+		}
+
+		// Custom section: a length-prefixed name followed by its data
+		pr := bytes.NewReader(payload)
+		nameLen, err := binary.ReadUvarint(pr)
+		if err != nil || nameLen > uint64(pr.Len()) {
+			parseErr = fmt.Errorf("malformed wasm custom section")
+			return false
+		}
+		sectionName := make([]byte, nameLen)
+		_, _ = io.ReadFull(pr, sectionName)
+		if string(sectionName) == name {
+			data = make([]byte, pr.Len())
+			_, _ = io.ReadFull(pr, data)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, parseErr
+}
+
+// wasmSections calls visit with the id and payload of each section of a WASM
+// module until visit returns false
+func wasmSections(wasm []byte, visit func(id byte, payload []byte) bool) error {
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], []byte("\x00asm")) {
+		return fmt.Errorf("not a wasm module")
+	}
+
+	r := bytes.NewReader(wasm[8:])
+	for r.Len() > 0 {
+		id, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		size, err := binary.ReadUvarint(r)
+		if err != nil || size > uint64(r.Len()) {
+			return fmt.Errorf("malformed wasm section")
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return fmt.Errorf("malformed wasm section: %w", err)
+		}
+		if !visit(id, payload) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkVersionPolicy rejects upgrades from a contract older than minSource or
+// to WASM newer than maxTarget. Nil bounds are not enforced.
+func checkVersionPolicy(current, target ContractVersion, minSource, maxTarget *ContractVersion) error {
+	if minSource != nil && current.Compare(*minSource) < 0 {
+		return fmt.Errorf("%w: current version %s is older than minimum %s", ErrIncompatibleVersion, current, minSource)
+	}
+	if maxTarget != nil && target.Compare(*maxTarget) > 0 {
+		return fmt.Errorf("%w: target version %s is newer than maximum %s", ErrIncompatibleVersion, target, maxTarget)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestParseContractVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ContractVersion
+		ok   bool
+	}{
+		{"1.2.3", ContractVersion{1, 2, 3}, true},
+		{"v2", ContractVersion{Major: 2}, true},
+		{"1.10", ContractVersion{Major: 1, Minor: 10}, true},
+		{"", ContractVersion{}, false},
+		{"1.2.3.4", ContractVersion{}, false},
+		{"1.x", ContractVersion{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseContractVersion(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseContractVersion(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestDecodeEncodedVersion(t *testing.T) {
+	if got := decodeEncodedVersion(2); got != (ContractVersion{Major: 2}) {
+		t.Errorf("expected plain major version, got %s", got)
+	}
+	if got := decodeEncodedVersion(10102); got != (ContractVersion{1, 1, 2}) {
+		t.Errorf("expected 1.1.2, got %s", got)
+	}
+}
+
+func TestCheckVersionPolicy(t *testing.T) {
+	min := ContractVersion{Major: 1, Minor: 1}
+	max := ContractVersion{Major: 2}
+
+	if err := checkVersionPolicy(ContractVersion{1, 2, 0}, ContractVersion{2, 0, 0}, &min, &max); err != nil {
+		t.Errorf("expected versions within bounds to pass, got %v", err)
+	}
+	if err := checkVersionPolicy(ContractVersion{1, 0, 9}, ContractVersion{}, &min, nil); !errors.Is(err, ErrIncompatibleVersion) {
+		t.Errorf("expected old source to be rejected, got %v", err)
+	}
+	if err := checkVersionPolicy(ContractVersion{}, ContractVersion{3, 0, 0}, nil, &max); !errors.Is(err, ErrIncompatibleVersion) {
+		t.Errorf("expected version jump to be rejected, got %v", err)
+	}
+}
+
+// testWasm builds a minimal module with a contractmetav0 section holding meta
+func testWasm(t *testing.T, meta map[string]string) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	for k, v := range meta {
+		entry := xdr.ScMetaEntry{Kind: xdr.ScMetaKindScMetaV0, V0: &xdr.ScMetaV0{Key: k, Val: v}}
+		if _, err := xdr.Marshal(&data, entry); err != nil {
+			t.Fatalf("failed to encode meta entry: %v", err)
+		}
+	}
+
+	name := "contractmetav0"
+	var payload []byte
+	payload = binary.AppendUvarint(payload, uint64(len(name)))
+	payload = append(payload, name...)
+	payload = append(payload, data.Bytes()...)
+
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	// An unrelated type section precedes the custom section
+	wasm = append(wasm, 1, 1, 0)
+	wasm = append(wasm, 0)
+	wasm = binary.AppendUvarint(wasm, uint64(len(payload)))
+	return append(wasm, payload...)
+}
+
+func TestReadWasmVersion(t *testing.T) {
+	got, err := ReadWasmVersion(testWasm(t, map[string]string{"binver": "1.4.0", "rsver": "1.80.0"}))
+	if err != nil {
+		t.Fatalf("ReadWasmVersion failed: %v", err)
+	}
+	if got != (ContractVersion{Major: 1, Minor: 4}) {
+		t.Errorf("expected 1.4.0, got %s", got)
+	}
+
+	if _, err := ReadWasmVersion(testWasm(t, map[string]string{"rsver": "1.80.0"})); !errors.Is(err, ErrVersionUnknown) {
+		t.Errorf("expected ErrVersionUnknown without a version key, got %v", err)
+	}
+	if _, err := ReadWasmVersion([]byte("\x00asm\x01\x00\x00\x00")); !errors.Is(err, ErrVersionUnknown) {
+		t.Errorf("expected ErrVersionUnknown without a meta section, got %v", err)
+	}
+	if _, err := ReadWasmVersion([]byte("not wasm")); err == nil || errors.Is(err, ErrVersionUnknown) {
+		t.Errorf("expected a decoding error for invalid wasm, got %v", err)
+	}
+}
+
+func TestGetContractVersion_NotImplemented(t *testing.T) {
+	var calls int32
+	srv := rpcServer(t, `{"error":"HostError: Error(WasmVm, MissingValue)","latestLedger":1}`, map[string]*int32{
+		"simulateTransaction": &calls,
+	})
+	kp := keypair.MustRandom()
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%q,"account_id":%q,"sequence":"1"}`, kp.Address(), kp.Address())
+	}))
+	t.Cleanup(horizon.Close)
+
+	client, _ := NewClient(Config{RPCURL: srv.URL})
+	client.horizonClient.HorizonURL = horizon.URL
+	tb, _ := NewTransactionBuilder(client, kp.Seed(), DefaultRetryConfig())
+	u, _ := NewUpgradeSafetyClient(client, tb, testContractHex)
+
+	_, err := u.GetContractVersion(context.Background())
+	if !errors.Is(err, ErrVersionUnknown) {
+		t.Errorf("expected ErrVersionUnknown, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected both version functions to be tried, got %d simulations", calls)
+	}
+}
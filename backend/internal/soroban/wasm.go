@@ -0,0 +1,182 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// HashWasm returns the hash the network assigns to uploaded WASM bytes
+func HashWasm(wasm []byte) [32]byte {
+	return sha256.Sum256(wasm)
+}
+
+// HashWasmFile reads a WASM artifact from disk and returns its hash
+func HashWasmFile(path string) ([32]byte, error) {
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to read wasm file: %w", err)
+	}
+	return HashWasm(wasm), nil
+}
+
+// UploadWasm installs contract code on the network and returns its hash,
+// which can then be passed to an upgrade. The code is first checked against
+// the builder's WasmRequirements so a broken artifact fails before any fee
+// is spent.
+func (tb *TransactionBuilder) UploadWasm(ctx context.Context, wasm []byte) ([32]byte, error) {
+	if err := ValidateWasm(wasm, tb.WasmRequirements); err != nil {
+		return [32]byte{}, err
+	}
+
+	hash := HashWasm(wasm)
+	slog.Info("uploading contract wasm",
+		"wasm_hash", hex.EncodeToString(hash[:]),
+		"size_bytes", len(wasm),
+	)
+
+	op := &txnbuild.InvokeHostFunction{
+		HostFunction: xdr.HostFunction{
+			Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm,
+			Wasm: &wasm,
+		},
+	}
+
+	result, err := tb.BuildAndSubmit(ctx, []txnbuild.Operation{op})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to upload wasm: %w", err)
+	}
+
+	if _, err := tb.WaitForConfirmation(ctx, result.Hash, 0); err != nil {
+		slog.Warn("failed to wait for confirmation", "error", err, "tx_hash", result.Hash)
+	}
+
+	return hash, nil
+}
+
+// UploadWasmVerified uploads wasm only if it hashes to expectedHash (e.g. from
+// a release manifest), returning ErrWasmHashMismatch otherwise
+func (tb *TransactionBuilder) UploadWasmVerified(ctx context.Context, wasm []byte, expectedHash [32]byte) ([32]byte, error) {
+	if hash := HashWasm(wasm); hash != expectedHash {
+		return [32]byte{}, fmt.Errorf("%w: expected %s, got %s", ErrWasmHashMismatch,
+			hex.EncodeToString(expectedHash[:]), hex.EncodeToString(hash[:]))
+	}
+	return tb.UploadWasm(ctx, wasm)
+}
+
+// DefaultMaxWasmSize is the network's contract size limit
+// (contract_max_size_bytes) at the time of writing
+const DefaultMaxWasmSize = 128 * 1024
+
+// DefaultRequiredExports are the functions WASM must export when
+// WasmRequirements.RequiredExports is nil
+var DefaultRequiredExports = []string{"init"}
+
+// WasmRequirements are the checks ValidateWasm applies
+type WasmRequirements struct {
+	// MaxSize is the largest accepted module in bytes (default:
+	// DefaultMaxWasmSize)
+	MaxSize int
+	// RequiredExports lists functions the module must export. Nil means
+	// DefaultRequiredExports; an empty slice requires none.
+	RequiredExports []string
+}
+
+// ValidateWasm rejects WASM that is empty, too large, not a WASM module, or
+// missing a required export, returning ErrInvalidWasm with the reason
+func ValidateWasm(wasm []byte, req WasmRequirements) error {
+	if len(wasm) == 0 {
+		return fmt.Errorf("%w: empty module", ErrInvalidWasm)
+	}
+	maxSize := req.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxWasmSize
+	}
+	if len(wasm) > maxSize {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrInvalidWasm, len(wasm), maxSize)
+	}
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], []byte("\x00asm")) {
+		return fmt.Errorf("%w: missing \\0asm magic header", ErrInvalidWasm)
+	}
+
+	required := req.RequiredExports
+	if required == nil {
+		required = DefaultRequiredExports
+	}
+	if len(required) == 0 {
+		return nil
+	}
+
+	exports, err := wasmExports(wasm)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWasm, err)
+	}
+	var missing []string
+	for _, name := range required {
+		if !exports[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing exported functions: %s", ErrInvalidWasm, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// wasmExports returns the names of the functions a module exports
+func wasmExports(wasm []byte) (map[string]bool, error) {
+	exports := make(map[string]bool)
+	var parseErr error
+	err := wasmSections(wasm, func(id byte, payload []byte) bool {
+		// Section 7 is the export section
+		if id != 7 {
+			return true
+		}
+		parseErr = parseWasmExports(payload, exports)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exports, parseErr
+}
+
+// parseWasmExports decodes an export section: a count followed by entries of
+// name, kind and index
+func parseWasmExports(payload []byte, exports map[string]bool) error {
+	r := bytes.NewReader(payload)
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("malformed wasm export section")
+	}
+	for i := uint64(0); i < count; i++ {
+		nameLen, err := binary.ReadUvarint(r)
+		if err != nil || nameLen > uint64(r.Len()) {
+			return fmt.Errorf("malformed wasm export section")
+		}
+		name := make([]byte, nameLen)
+		_, _ = io.ReadFull(r, name)
+		kind, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("malformed wasm export section")
+		}
+		if _, err := binary.ReadUvarint(r); err != nil {
+			return fmt.Errorf("malformed wasm export section")
+		}
+		// Kind 0 is a function export
+		if kind == 0 {
+			exports[string(name)] = true
+		}
+	}
+	return nil
+}
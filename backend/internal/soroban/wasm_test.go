@@ -0,0 +1,85 @@
+package soroban
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashWasmFile(t *testing.T) {
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	path := filepath.Join(t.TempDir(), "contract.wasm")
+	if err := os.WriteFile(path, wasm, 0o600); err != nil {
+		t.Fatalf("failed to write wasm: %v", err)
+	}
+
+	hash, err := HashWasmFile(path)
+	if err != nil {
+		t.Fatalf("HashWasmFile failed: %v", err)
+	}
+	if hash != sha256.Sum256(wasm) {
+		t.Errorf("unexpected hash %x", hash)
+	}
+}
+
+func TestUploadWasmVerified_Mismatch(t *testing.T) {
+	// The builder has no client: a mismatch must be rejected before any upload.
+	tb := &TransactionBuilder{}
+	_, err := tb.UploadWasmVerified(context.Background(), []byte("tampered"), HashWasm([]byte("release")))
+	if !errors.Is(err, ErrWasmHashMismatch) {
+		t.Errorf("expected ErrWasmHashMismatch, got %v", err)
+	}
+}
+
+// wasmWithExports builds a minimal module exporting the named functions
+func wasmWithExports(names ...string) []byte {
+	payload := binary.AppendUvarint(nil, uint64(len(names)))
+	for i, name := range names {
+		payload = binary.AppendUvarint(payload, uint64(len(name)))
+		payload = append(payload, name...)
+		payload = append(payload, 0)
+		payload = binary.AppendUvarint(payload, uint64(i))
+	}
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, 7)
+	wasm = binary.AppendUvarint(wasm, uint64(len(payload)))
+	return append(wasm, payload...)
+}
+
+func TestValidateWasm(t *testing.T) {
+	tests := []struct {
+		name string
+		wasm []byte
+		req  WasmRequirements
+		ok   bool
+	}{
+		{"valid", wasmWithExports("init", "lock_funds"), WasmRequirements{}, true},
+		{"empty", nil, WasmRequirements{}, false},
+		{"too large", wasmWithExports("init"), WasmRequirements{MaxSize: 8}, false},
+		{"bad magic", []byte("PK\x03\x04 not wasm"), WasmRequirements{}, false},
+		{"missing init", wasmWithExports("lock_funds"), WasmRequirements{}, false},
+		{"custom exports", wasmWithExports("upgrade"), WasmRequirements{RequiredExports: []string{"upgrade"}}, true},
+		{"no exports required", []byte("\x00asm\x01\x00\x00\x00"), WasmRequirements{RequiredExports: []string{}}, true},
+	}
+	for _, tt := range tests {
+		err := ValidateWasm(tt.wasm, tt.req)
+		if tt.ok && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidWasm) {
+			t.Errorf("%s: expected ErrInvalidWasm, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestUploadWasm_RejectsInvalidBeforeUpload(t *testing.T) {
+	// The builder has no client: invalid WASM must be rejected before any upload.
+	tb := &TransactionBuilder{}
+	if _, err := tb.UploadWasm(context.Background(), []byte("truncated")); !errors.Is(err, ErrInvalidWasm) {
+		t.Errorf("expected ErrInvalidWasm, got %v", err)
+	}
+}
@@ -2,6 +2,9 @@ package soroban
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
@@ -28,6 +31,15 @@ func EncodeScValInt64(i int64) (xdr.ScVal, error) {
 	}, nil
 }
 
+// EncodeScValUint32 encodes a uint32 as ScVal
+func EncodeScValUint32(u uint32) (xdr.ScVal, error) {
+	u32 := xdr.Uint32(u)
+	return xdr.ScVal{
+		Type: xdr.ScValTypeScvU32,
+		U32:  &u32,
+	}, nil
+}
+
 // EncodeScValUint64 encodes a uint64 as ScVal
 func EncodeScValUint64(u uint64) (xdr.ScVal, error) {
 	u64 := xdr.Uint64(u)
@@ -37,6 +49,23 @@ func EncodeScValUint64(u uint64) (xdr.ScVal, error) {
 	}, nil
 }
 
+// EncodeScValBool encodes a bool as ScVal
+func EncodeScValBool(b bool) (xdr.ScVal, error) {
+	return xdr.ScVal{
+		Type: xdr.ScValTypeScvBool,
+		B:    &b,
+	}, nil
+}
+
+// EncodeScValSymbol encodes a string as an ScVal symbol
+func EncodeScValSymbol(s string) (xdr.ScVal, error) {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{
+		Type: xdr.ScValTypeScvSymbol,
+		Sym:  &sym,
+	}, nil
+}
+
 // EncodeScValAddress encodes an address string as ScVal
 func EncodeScValAddress(addrStr string) (xdr.ScVal, error) {
 	// Try parsing as account address first
@@ -70,14 +99,71 @@ func EncodeScValAddress(addrStr string) (xdr.ScVal, error) {
 	return xdr.ScVal{}, fmt.Errorf("invalid address format: %s", addrStr)
 }
 
+// EncodeScValBytes encodes a byte slice as ScVal bytes (also used for BytesN)
+func EncodeScValBytes(b []byte) (xdr.ScVal, error) {
+	scBytes := xdr.ScBytes(b)
+	return xdr.ScVal{
+		Type:  xdr.ScValTypeScvBytes,
+		Bytes: &scBytes,
+	}, nil
+}
+
 // EncodeScValVec encodes a slice of ScVal as ScVal vector
 func EncodeScValVec(vals []xdr.ScVal) (xdr.ScVal, error) {
+	return NewScValVec(vals...), nil
+}
+
+// NewScValVec builds an ScVal vector from vals
+func NewScValVec(vals ...xdr.ScVal) xdr.ScVal {
 	vec := xdr.ScVec(vals)
 	vecPtr := &vec
 	return xdr.ScVal{
 		Type: xdr.ScValTypeScvVec,
 		Vec:  &vecPtr,
-	}, nil
+	}
+}
+
+// maxSymbolLen is the longest symbol the Soroban host accepts
+const maxSymbolLen = 32
+
+// NewScValMap builds an ScVal map with symbol keys, as used for
+// #[contracttype] structs. The host rejects maps whose keys are not in
+// ascending order; symbols order by their bytes, so entries are sorted by key
+// string rather than by serialized XDR, which is length-prefixed.
+func NewScValMap(entries map[string]xdr.ScVal) (xdr.ScVal, error) {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		if err := validateSymbol(k); err != nil {
+			return xdr.ScVal{}, err
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := make(xdr.ScMap, len(keys))
+	for i, k := range keys {
+		sym := xdr.ScSymbol(k)
+		m[i] = xdr.ScMapEntry{
+			Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+			Val: entries[k],
+		}
+	}
+	mPtr := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mPtr}, nil
+}
+
+// validateSymbol checks s against the host's symbol rules: at most 32
+// characters from [a-zA-Z0-9_]
+func validateSymbol(s string) error {
+	if s == "" || len(s) > maxSymbolLen {
+		return fmt.Errorf("invalid symbol %q: must be 1-%d characters", s, maxSymbolLen)
+	}
+	for _, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("invalid symbol %q: character %q not allowed", s, r)
+		}
+	}
+	return nil
 }
 
 // EncodeScSymbol encodes a symbol (function name) as ScSymbol
@@ -86,6 +172,14 @@ func EncodeScSymbol(s string) (xdr.ScSymbol, error) {
 	return xdr.ScSymbol(s), nil
 }
 
+// hostFunction returns the name fn is exported under, per names
+func hostFunction(names map[string]string, fn string) string {
+	if name, ok := names[fn]; ok {
+		return name
+	}
+	return fn
+}
+
 // BuildInvokeHostFunctionOp builds an InvokeHostFunction operation for contract calls
 func BuildInvokeHostFunctionOp(contractAddress xdr.ScAddress, functionName string, args []xdr.ScVal) (txnbuild.Operation, error) {
 	symbol, err := EncodeScSymbol(functionName)
@@ -110,3 +204,219 @@ func BuildInvokeHostFunctionOp(contractAddress xdr.ScAddress, functionName strin
 		HostFunction: hostFunction,
 	}, nil
 }
+
+// DecodeScValStruct decodes a #[contracttype] struct, which Soroban encodes as
+// a map keyed by field-name symbols, into its fields
+func DecodeScValStruct(v xdr.ScVal) (map[string]xdr.ScVal, error) {
+	scMap, ok := v.GetMap()
+	if !ok || scMap == nil {
+		return nil, fmt.Errorf("expected struct map, got %s", v.Type)
+	}
+
+	fields := make(map[string]xdr.ScVal, len(*scMap))
+	for _, entry := range *scMap {
+		sym, ok := entry.Key.GetSym()
+		if !ok {
+			return nil, fmt.Errorf("expected symbol field name, got %s", entry.Key.Type)
+		}
+		fields[string(sym)] = entry.Val
+	}
+	return fields, nil
+}
+
+// DecodeScValBool decodes a bool ScVal
+func DecodeScValBool(v xdr.ScVal) (bool, error) {
+	b, ok := v.GetB()
+	if !ok {
+		return false, fmt.Errorf("expected bool, got %s", v.Type)
+	}
+	return b, nil
+}
+
+// DecodeScValUint32 decodes a u32 ScVal
+func DecodeScValUint32(v xdr.ScVal) (uint32, error) {
+	u32, ok := v.GetU32()
+	if !ok {
+		return 0, fmt.Errorf("expected u32, got %s", v.Type)
+	}
+	return uint32(u32), nil
+}
+
+// DecodeScValUint64 decodes a u64 ScVal
+func DecodeScValUint64(v xdr.ScVal) (uint64, error) {
+	u64, ok := v.GetU64()
+	if !ok {
+		return 0, fmt.Errorf("expected u64, got %s", v.Type)
+	}
+	return uint64(u64), nil
+}
+
+// DecodeScValInt64 decodes an i64 ScVal, or an i128 ScVal whose value fits in
+// an int64 (token amounts are i128 on-chain)
+func DecodeScValInt64(v xdr.ScVal) (int64, error) {
+	switch v.Type {
+	case xdr.ScValTypeScvI64:
+		return int64(*v.I64), nil
+	case xdr.ScValTypeScvI128:
+		parts := v.I128
+		lo := uint64(parts.Lo)
+		hi := int64(parts.Hi)
+		// Fits in int64 only if hi is the sign extension of lo
+		if (hi == 0 && lo <= math.MaxInt64) || (hi == -1 && lo > math.MaxInt64) {
+			return int64(lo), nil
+		}
+		return 0, fmt.Errorf("i128 value overflows int64")
+	default:
+		return 0, fmt.Errorf("expected i64 or i128, got %s", v.Type)
+	}
+}
+
+// DecodeScValEnumVariant returns the variant name of a #[contracttype] enum
+// value, which Soroban encodes as a vec starting with the variant symbol
+func DecodeScValEnumVariant(v xdr.ScVal) (string, error) {
+	vec, ok := v.GetVec()
+	if !ok || vec == nil || len(*vec) == 0 {
+		return "", fmt.Errorf("expected enum vec, got %s", v.Type)
+	}
+	sym, ok := (*vec)[0].GetSym()
+	if !ok {
+		return "", fmt.Errorf("expected enum variant symbol, got %s", (*vec)[0].Type)
+	}
+	return string(sym), nil
+}
+
+// DecodeScValSymbol decodes a symbol ScVal
+func DecodeScValSymbol(v xdr.ScVal) (string, error) {
+	sym, ok := v.GetSym()
+	if !ok {
+		return "", fmt.Errorf("expected symbol, got %s", v.Type)
+	}
+	return string(sym), nil
+}
+
+// DecodeEnum maps an enum value, either a bare symbol or a #[contracttype]
+// enum vec, to its entry in mapping. Variants missing from mapping are an
+// error, so a contract adding one can't be silently misread.
+func DecodeEnum(v xdr.ScVal, mapping map[string]int) (int, error) {
+	var name string
+	var err error
+	if v.Type == xdr.ScValTypeScvSymbol {
+		name, err = DecodeScValSymbol(v)
+	} else {
+		name, err = DecodeScValEnumVariant(v)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := mapping[name]
+	if !ok {
+		known := make([]string, 0, len(mapping))
+		for k := range mapping {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return 0, fmt.Errorf("unexpected enum variant %q, expected one of %s", name, strings.Join(known, ", "))
+	}
+	return value, nil
+}
+
+// DecodeScValString decodes a string or symbol ScVal
+func DecodeScValString(v xdr.ScVal) (string, error) {
+	switch v.Type {
+	case xdr.ScValTypeScvString:
+		return string(*v.Str), nil
+	case xdr.ScValTypeScvSymbol:
+		return string(*v.Sym), nil
+	default:
+		return "", fmt.Errorf("expected string, got %s", v.Type)
+	}
+}
+
+// AddressKind distinguishes the address variants contract state holds
+type AddressKind int
+
+const (
+	// AddressKindAccount is a Stellar account, G…
+	AddressKindAccount AddressKind = iota + 1
+	// AddressKindContract is a contract, C…
+	AddressKindContract
+)
+
+func (k AddressKind) String() string {
+	switch k {
+	case AddressKindAccount:
+		return "account"
+	case AddressKindContract:
+		return "contract"
+	default:
+		return "unknown"
+	}
+}
+
+// Address is a decoded Soroban address, e.g. an escrow's depositor, which
+// may be an account or a contract
+type Address struct {
+	Kind AddressKind
+	// StrKey is the address in strkey form, G… or C…
+	StrKey string
+}
+
+// String returns the address in strkey form
+func (a Address) String() string { return a.StrKey }
+
+// IsContract reports whether the address is a contract
+func (a Address) IsContract() bool { return a.Kind == AddressKindContract }
+
+// DecodeScValAddress decodes an account or contract address ScVal. Muxed
+// accounts and the other address variants aren't used by the contracts and
+// return an error.
+func DecodeScValAddress(v xdr.ScVal) (Address, error) {
+	addr, ok := v.GetAddress()
+	if !ok {
+		return Address{}, fmt.Errorf("expected address, got %s", v.Type)
+	}
+
+	var kind AddressKind
+	switch addr.Type {
+	case xdr.ScAddressTypeScAddressTypeAccount:
+		kind = AddressKindAccount
+	case xdr.ScAddressTypeScAddressTypeContract:
+		kind = AddressKindContract
+	case xdr.ScAddressTypeScAddressTypeMuxedAccount:
+		return Address{}, fmt.Errorf("muxed account addresses are not supported")
+	default:
+		return Address{}, fmt.Errorf("unsupported address type %s", addr.Type)
+	}
+
+	strkey, err := addr.String()
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to encode %s address: %w", kind, err)
+	}
+	return Address{Kind: kind, StrKey: strkey}, nil
+}
+
+// decodeScValAddressString is DecodeScValAddress for callers that only need
+// the strkey
+func decodeScValAddressString(v xdr.ScVal) (string, error) {
+	addr, err := DecodeScValAddress(v)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+// structField decodes the named field of a struct decoded by
+// DecodeScValStruct, naming the field in any error
+func structField[T any](fields map[string]xdr.ScVal, name string, decode func(xdr.ScVal) (T, error)) (T, error) {
+	var zero T
+	val, ok := fields[name]
+	if !ok {
+		return zero, fmt.Errorf("missing field %s", name)
+	}
+	v, err := decode(val)
+	if err != nil {
+		return zero, fmt.Errorf("field %s: %w", name, err)
+	}
+	return v, nil
+}
@@ -1,8 +1,10 @@
 package soroban
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 )
 
@@ -66,6 +68,50 @@ func TestEncodeScValVec(t *testing.T) {
 	}
 }
 
+func TestNewScValVec(t *testing.T) {
+	a, _ := EncodeScValUint64(1)
+	b, _ := EncodeScValUint64(2)
+	val := NewScValVec(a, b)
+	vec, ok := val.GetVec()
+	if !ok || vec == nil || len(*vec) != 2 {
+		t.Fatalf("expected a 2-element vec, got %+v", val)
+	}
+	if empty, _ := NewScValVec().GetVec(); empty == nil || len(*empty) != 0 {
+		t.Error("expected an empty, non-nil vec")
+	}
+}
+
+func TestNewScValMap_SortsKeys(t *testing.T) {
+	v, _ := EncodeScValBool(true)
+	// Serialized XDR symbols are length-prefixed, so sorting by encoded bytes
+	// would put "b" before "aa"; the host requires byte order of the symbol.
+	val, err := NewScValMap(map[string]xdr.ScVal{"b": v, "aa": v, "amount": v, "Z": v, "a_b": v})
+	if err != nil {
+		t.Fatalf("NewScValMap failed: %v", err)
+	}
+	m, ok := val.GetMap()
+	if !ok || m == nil {
+		t.Fatal("expected a map")
+	}
+	var got []string
+	for _, e := range *m {
+		got = append(got, string(*e.Key.Sym))
+	}
+	want := []string{"Z", "a_b", "aa", "amount", "b"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+}
+
+func TestNewScValMap_InvalidKey(t *testing.T) {
+	v, _ := EncodeScValBool(true)
+	for _, key := range []string{"", "has space", "dash-key", strings.Repeat("a", 33)} {
+		if _, err := NewScValMap(map[string]xdr.ScVal{key: v}); err == nil {
+			t.Errorf("expected error for key %q", key)
+		}
+	}
+}
+
 func TestEncodeScSymbol(t *testing.T) {
 	symbol, err := EncodeScSymbol("test_function")
 	if err != nil {
@@ -91,6 +137,30 @@ func TestEncodeContractAddress(t *testing.T) {
 	}
 }
 
+func TestEncodeContractAddress_Strkey(t *testing.T) {
+	id := make([]byte, 32)
+	id[31] = 7
+	addr := strkey.MustEncode(strkey.VersionByteContract, id)
+
+	sc, err := EncodeContractAddress(addr)
+	if err != nil {
+		t.Fatalf("EncodeContractAddress failed with strkey: %v", err)
+	}
+	if sc.ContractId == nil || (*sc.ContractId)[31] != 7 {
+		t.Errorf("expected contract id to be decoded from the strkey")
+	}
+
+	// Change the last character to break the checksum
+	last := byte('A')
+	if addr[55] == 'A' {
+		last = 'B'
+	}
+	corrupted := addr[:55] + string(last)
+	if _, err := EncodeContractAddress(corrupted); err == nil {
+		t.Error("expected error for a strkey with a bad checksum")
+	}
+}
+
 func TestDefaultRetryConfig(t *testing.T) {
 	config := DefaultRetryConfig()
 	if config.MaxRetries != 3 {
@@ -106,3 +176,104 @@ func TestDefaultRetryConfig(t *testing.T) {
 		t.Errorf("expected BackoffMultiplier 2.0, got %f", config.BackoffMultiplier)
 	}
 }
+
+func TestDecodeScValRoundTrip(t *testing.T) {
+	b, _ := EncodeScValBool(true)
+	if got, err := DecodeScValBool(b); err != nil || !got {
+		t.Errorf("expected true, got %v (err %v)", got, err)
+	}
+
+	u32, _ := EncodeScValUint32(7)
+	if got, err := DecodeScValUint32(u32); err != nil || got != 7 {
+		t.Errorf("expected 7, got %d (err %v)", got, err)
+	}
+
+	u64, _ := EncodeScValUint64(99)
+	if got, err := DecodeScValUint64(u64); err != nil || got != 99 {
+		t.Errorf("expected 99, got %d (err %v)", got, err)
+	}
+
+	if _, err := DecodeScValBool(u32); err == nil {
+		t.Error("expected type mismatch error")
+	}
+}
+
+func TestDecodeScValInt64_I128(t *testing.T) {
+	neg := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(^uint64(0))}}
+	if got, err := DecodeScValInt64(neg); err != nil || got != -1 {
+		t.Errorf("expected -1, got %d (err %v)", got, err)
+	}
+
+	overflow := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: 1, Lo: 0}}
+	if _, err := DecodeScValInt64(overflow); err == nil {
+		t.Error("expected overflow error")
+	}
+}
+
+func TestDecodeEnum(t *testing.T) {
+	mapping := map[string]int{"Locked": 1, "Released": 2}
+
+	sym, _ := EncodeScValSymbol("Released")
+	if got, err := DecodeEnum(sym, mapping); err != nil || got != 2 {
+		t.Errorf("expected 2 for a bare symbol, got %d (err %v)", got, err)
+	}
+
+	locked, _ := EncodeScValSymbol("Locked")
+	variant, _ := EncodeScValVec([]xdr.ScVal{locked})
+	if got, err := DecodeEnum(variant, mapping); err != nil || got != 1 {
+		t.Errorf("expected 1 for an enum vec, got %d (err %v)", got, err)
+	}
+
+	unknown, _ := EncodeScValSymbol("Frozen")
+	if _, err := DecodeEnum(unknown, mapping); err == nil || !strings.Contains(err.Error(), `"Frozen"`) {
+		t.Errorf("expected an error naming the unexpected variant, got %v", err)
+	}
+
+	u64, _ := EncodeScValUint64(1)
+	if _, err := DecodeScValSymbol(u64); err == nil {
+		t.Error("expected type mismatch error")
+	}
+}
+
+func TestDecodeScValAddress(t *testing.T) {
+	account := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	accountID := xdr.MustAddress(account)
+	contractID := xdr.ContractId{0xab}
+	contract := strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+	muxed := xdr.MuxedEd25519Account{Id: 7}
+
+	tests := []struct {
+		name    string
+		addr    xdr.ScAddress
+		want    Address
+		wantErr string
+	}{
+		{"account", xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountID}, Address{Kind: AddressKindAccount, StrKey: account}, ""},
+		{"contract", xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}, Address{Kind: AddressKindContract, StrKey: contract}, ""},
+		{"muxed", xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeMuxedAccount, MuxedAccount: &muxed}, Address{}, "muxed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeScValAddress(xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &tt.addr})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeScValAddress failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+			if got.IsContract() != (tt.want.Kind == AddressKindContract) {
+				t.Errorf("IsContract() = %v for a %s address", got.IsContract(), got.Kind)
+			}
+		})
+	}
+
+	if _, err := DecodeScValAddress(xdr.ScVal{Type: xdr.ScValTypeScvVoid}); err == nil {
+		t.Error("expected an error for a non-address value")
+	}
+}